@@ -0,0 +1,229 @@
+package payloadcms
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// Block is implemented by the Go types that represent a Payload block.
+// BlockType returns the slug of the block, which Payload sends back
+// under the blockType key.
+//
+// Example:
+//
+//	type CallToAction struct {
+//		ID      string `json:"id"`
+//		Heading string `json:"heading"`
+//	}
+//
+//	func (CallToAction) BlockType() string { return "cta" }
+//
+// See: https://payloadcms.com/docs/fields/blocks
+type Block interface {
+	BlockType() string
+}
+
+// Blocks represents a Payload blocks field. Each element is decoded into
+// the Go type registered for its blockType with DefaultBlockRegistry,
+// and encoded back with the correct blockType.
+//
+// Blocks with an unregistered type are decoded into a RawBlock, unless
+// the registry disallows unknown blocks, in which case an error wrapping
+// ErrUnknownBlock is returned.
+type Blocks []Block
+
+// RawBlock holds the JSON of a block with a type that has not been
+// registered, so that it can be inspected or encoded back unchanged.
+type RawBlock struct {
+	Type string
+	Data json.RawMessage
+}
+
+// BlockRegistry maps blockType slugs to the Go types they are decoded into.
+// It is safe for concurrent use.
+type BlockRegistry struct {
+	mu              sync.RWMutex
+	types           map[string]reflect.Type
+	disallowUnknown bool
+}
+
+// ErrUnknownBlock is returned when decoding a block with a type that has
+// not been registered and the registry disallows unknown blocks.
+var ErrUnknownBlock = errors.New("unknown block type")
+
+// DefaultBlockRegistry is the registry used when decoding Blocks.
+var DefaultBlockRegistry = NewBlockRegistry()
+
+// NewBlockRegistry creates a new, empty BlockRegistry.
+func NewBlockRegistry() *BlockRegistry {
+	return &BlockRegistry{
+		types: make(map[string]reflect.Type),
+	}
+}
+
+// RegisterBlock registers the blocks with DefaultBlockRegistry.
+// It's typically called within an init function.
+func RegisterBlock(blocks ...Block) {
+	DefaultBlockRegistry.Register(blocks...)
+}
+
+// BlocksOf returns every block within b that is of type T.
+func BlocksOf[T Block](b Blocks) []T {
+	var out []T
+	for _, block := range b {
+		if v, ok := block.(T); ok {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// Register registers the Go type of each block under its BlockType. Blocks
+// registered as a pointer are decoded as a pointer, otherwise as a value.
+func (r *BlockRegistry) Register(blocks ...Block) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, b := range blocks {
+		r.types[b.BlockType()] = reflect.TypeOf(b)
+	}
+}
+
+// DisallowUnknown causes the registry to return an error when decoding
+// a block with a type that has not been registered, instead of falling
+// back to a RawBlock.
+func (r *BlockRegistry) DisallowUnknown() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.disallowUnknown = true
+}
+
+// Decode decodes a single block into its registered type.
+func (r *BlockRegistry) Decode(data []byte) (Block, error) {
+	var head struct {
+		BlockType string `json:"blockType"`
+	}
+	if err := json.Unmarshal(data, &head); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	t, ok := r.types[head.BlockType]
+	disallowUnknown := r.disallowUnknown
+	r.mu.RUnlock()
+
+	if !ok {
+		if disallowUnknown {
+			return nil, fmt.Errorf("%w: %q", ErrUnknownBlock, head.BlockType)
+		}
+		return RawBlock{
+			Type: head.BlockType,
+			Data: append(json.RawMessage(nil), data...),
+		}, nil
+	}
+
+	if t.Kind() == reflect.Pointer {
+		v := reflect.New(t.Elem())
+		if err := json.Unmarshal(data, v.Interface()); err != nil {
+			return nil, fmt.Errorf("decoding block %q: %w", head.BlockType, err)
+		}
+		return v.Interface().(Block), nil
+	}
+
+	v := reflect.New(t)
+	if err := json.Unmarshal(data, v.Interface()); err != nil {
+		return nil, fmt.Errorf("decoding block %q: %w", head.BlockType, err)
+	}
+	return v.Elem().Interface().(Block), nil
+}
+
+// DecodeAll decodes a JSON array of blocks into their registered types.
+func (r *BlockRegistry) DecodeAll(data []byte) (Blocks, error) {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	if raw == nil {
+		return nil, nil
+	}
+
+	blocks := make(Blocks, 0, len(raw))
+	for _, item := range raw {
+		b, err := r.Decode(item)
+		if err != nil {
+			return nil, err
+		}
+		blocks = append(blocks, b)
+	}
+
+	return blocks, nil
+}
+
+// UnmarshalJSON decodes the blocks using DefaultBlockRegistry.
+func (b *Blocks) UnmarshalJSON(data []byte) error {
+	blocks, err := DefaultBlockRegistry.DecodeAll(data)
+	if err != nil {
+		return err
+	}
+	*b = blocks
+	return nil
+}
+
+// MarshalJSON encodes each block along with its blockType.
+func (b Blocks) MarshalJSON() ([]byte, error) {
+	if b == nil {
+		return []byte("null"), nil
+	}
+
+	items := make([]json.RawMessage, 0, len(b))
+	for _, block := range b {
+		buf, err := marshalBlock(block)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, buf)
+	}
+
+	return json.Marshal(items)
+}
+
+// marshalBlock encodes the block as a JSON object with the blockType set.
+func marshalBlock(block Block) ([]byte, error) {
+	if block == nil {
+		return nil, errors.New("cannot marshal nil block")
+	}
+
+	buf, err := json.Marshal(block)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(buf, &fields); err != nil {
+		return nil, fmt.Errorf("block %q must encode to a JSON object: %w", block.BlockType(), err)
+	}
+
+	blockType, err := json.Marshal(block.BlockType())
+	if err != nil {
+		return nil, err
+	}
+	fields["blockType"] = blockType
+
+	return json.Marshal(fields)
+}
+
+// BlockType returns the blockType of the raw block.
+func (b RawBlock) BlockType() string {
+	return b.Type
+}
+
+// MarshalJSON encodes the raw JSON of the block unchanged.
+func (b RawBlock) MarshalJSON() ([]byte, error) {
+	if len(bytes.TrimSpace(b.Data)) == 0 {
+		return json.Marshal(map[string]string{"blockType": b.Type})
+	}
+	return b.Data, nil
+}
@@ -0,0 +1,149 @@
+package payloadcms
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type ctaBlock struct {
+	ID      string `json:"id"`
+	Heading string `json:"heading"`
+}
+
+func (ctaBlock) BlockType() string { return "cta" }
+
+type quoteBlock struct {
+	Quote string `json:"quote"`
+}
+
+func (*quoteBlock) BlockType() string { return "quote" }
+
+type badBlock []string
+
+func (badBlock) BlockType() string { return "bad" }
+
+func init() {
+	RegisterBlock(ctaBlock{}, &quoteBlock{})
+}
+
+const blocksJSON = `[
+	{"blockType": "cta", "id": "1", "heading": "Sign up"},
+	{"blockType": "quote", "quote": "Hello"},
+	{"blockType": "gallery", "images": [1, 2]}
+]`
+
+func TestBlocks_UnmarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+
+		var b Blocks
+		require.NoError(t, json.Unmarshal([]byte(blocksJSON), &b))
+		require.Len(t, b, 3)
+
+		assert.Equal(t, ctaBlock{ID: "1", Heading: "Sign up"}, b[0])
+		assert.Equal(t, &quoteBlock{Quote: "Hello"}, b[1])
+
+		raw, ok := b[2].(RawBlock)
+		require.True(t, ok)
+		assert.Equal(t, "gallery", raw.BlockType())
+		assert.JSONEq(t, `{"blockType": "gallery", "images": [1, 2]}`, string(raw.Data))
+	})
+
+	t.Run("Null", func(t *testing.T) {
+		t.Parallel()
+
+		var b Blocks
+		require.NoError(t, json.Unmarshal([]byte(`null`), &b))
+		assert.Nil(t, b)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		t.Parallel()
+
+		var b Blocks
+		assert.Error(t, json.Unmarshal([]byte(`{}`), &b))
+		assert.Error(t, json.Unmarshal([]byte(`[{"blockType": "cta", "heading": 1}]`), &b))
+		assert.Error(t, json.Unmarshal([]byte(`[{"blockType": "quote", "quote": 1}]`), &b))
+		assert.Error(t, json.Unmarshal([]byte(`["cta"]`), &b))
+	})
+}
+
+func TestBlocks_MarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Round Trip", func(t *testing.T) {
+		t.Parallel()
+
+		var b Blocks
+		require.NoError(t, json.Unmarshal([]byte(blocksJSON), &b))
+
+		got, err := json.Marshal(b)
+		require.NoError(t, err)
+		assert.JSONEq(t, blocksJSON, string(got))
+	})
+
+	t.Run("Sets Block Type", func(t *testing.T) {
+		t.Parallel()
+
+		got, err := json.Marshal(Blocks{ctaBlock{Heading: "Hi"}, RawBlock{Type: "empty"}})
+		require.NoError(t, err)
+		assert.JSONEq(t, `[{"blockType":"cta","id":"","heading":"Hi"},{"blockType":"empty"}]`, string(got))
+	})
+
+	t.Run("Nil", func(t *testing.T) {
+		t.Parallel()
+
+		got, err := json.Marshal(Blocks(nil))
+		require.NoError(t, err)
+		assert.Equal(t, "null", string(got))
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := json.Marshal(Blocks{nil})
+		assert.Error(t, err)
+		_, err = json.Marshal(Blocks{badBlock{"a"}})
+		assert.Error(t, err)
+	})
+}
+
+func TestBlockRegistry(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Disallow Unknown", func(t *testing.T) {
+		t.Parallel()
+
+		r := NewBlockRegistry()
+		r.Register(ctaBlock{})
+		r.DisallowUnknown()
+
+		_, err := r.DecodeAll([]byte(blocksJSON))
+		assert.ErrorIs(t, err, ErrUnknownBlock)
+	})
+
+	t.Run("Registries Are Independent", func(t *testing.T) {
+		t.Parallel()
+
+		r := NewBlockRegistry()
+		b, err := r.Decode([]byte(`{"blockType": "cta", "heading": "Sign up"}`))
+		require.NoError(t, err)
+		assert.IsType(t, RawBlock{}, b)
+	})
+}
+
+func TestBlocksOf(t *testing.T) {
+	t.Parallel()
+
+	var b Blocks
+	require.NoError(t, json.Unmarshal([]byte(blocksJSON), &b))
+
+	assert.Equal(t, []ctaBlock{{ID: "1", Heading: "Sign up"}}, BlocksOf[ctaBlock](b))
+	assert.Len(t, BlocksOf[*quoteBlock](b), 1)
+	assert.Empty(t, BlocksOf[badBlock](b))
+}
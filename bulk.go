@@ -0,0 +1,351 @@
+package payloadcms
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"sort"
+	"sync"
+)
+
+// BulkUploader uploads every file within a directory to an upload
+// collection using a bounded pool of workers. It's intended for
+// migrating existing asset libraries, where files may already have been
+// uploaded by a previous run.
+//
+// When a hash field is set with WithBulkHashField, the SHA-256 checksum
+// of each file is stored in that field, and files whose checksum already
+// exists in the collection are skipped.
+//
+// Example:
+//
+//	uploader := payloadcms.NewBulkUploader(client.Media, client.Collections,
+//		payloadcms.WithBulkWorkers(8),
+//		payloadcms.WithBulkHashField("sha256"),
+//	)
+//	manifest, err := uploader.UploadDir(ctx, "./assets")
+type BulkUploader struct {
+	media       MediaService
+	collections CollectionService
+	config      bulkConfig
+
+	mu     sync.Mutex
+	hashes map[string]*bulkHash
+}
+
+// BulkOption is a functional option type that allows us to configure a
+// BulkUploader.
+type BulkOption func(*bulkConfig)
+
+type bulkConfig struct {
+	collection Collection
+	workers    int
+	hashField  string
+	fields     func(path string) any
+	filter     func(path string) bool
+}
+
+// bulkHash is the upload of a checksum, shared by the files in a run
+// with the same content so it's only uploaded once.
+type bulkHash struct {
+	done chan struct{}
+	id   any
+	err  error
+}
+
+// DefaultBulkWorkers is the default amount of files uploaded at once.
+const DefaultBulkWorkers = 4
+
+// WithBulkCollection sets the upload collection the files are uploaded
+// to, which defaults to "media".
+func WithBulkCollection(collection Collection) BulkOption {
+	return func(c *bulkConfig) {
+		c.collection = collection
+	}
+}
+
+// WithBulkWorkers sets the maximum amount of files uploaded at once.
+func WithBulkWorkers(n int) BulkOption {
+	return func(c *bulkConfig) {
+		c.workers = n
+	}
+}
+
+// WithBulkHashField sets the field of the collection that the SHA-256
+// checksum of each file is stored in, as a hex string. Files with a
+// checksum that already exists in the collection aren't uploaded.
+func WithBulkHashField(field string) BulkOption {
+	return func(c *bulkConfig) {
+		c.hashField = field
+	}
+}
+
+// WithBulkFields sets the function that returns the fields to upload
+// along with each file, such as its alt text. The path is relative to
+// the root of the directory.
+func WithBulkFields(fn func(path string) any) BulkOption {
+	return func(c *bulkConfig) {
+		c.fields = fn
+	}
+}
+
+// WithBulkFilter sets the function that reports whether a file should
+// be uploaded. By default, every regular file is uploaded.
+func WithBulkFilter(fn func(path string) bool) BulkOption {
+	return func(c *bulkConfig) {
+		c.filter = fn
+	}
+}
+
+// NewBulkUploader creates a new BulkUploader. The collection service is
+// used to look up existing checksums when a hash field is set.
+func NewBulkUploader(media MediaService, collections CollectionService, options ...BulkOption) *BulkUploader {
+	cfg := bulkConfig{
+		collection: "media",
+		workers:    DefaultBulkWorkers,
+	}
+	for _, opt := range options {
+		opt(&cfg)
+	}
+	if cfg.workers <= 0 {
+		cfg.workers = DefaultBulkWorkers
+	}
+	return &BulkUploader{
+		media:       media,
+		collections: collections,
+		config:      cfg,
+		hashes:      make(map[string]*bulkHash),
+	}
+}
+
+// BulkManifest maps the path of each file, relative to the root of the
+// directory, to the result of its upload.
+type BulkManifest map[string]BulkResult
+
+// BulkResult is the result of uploading a single file.
+type BulkResult struct {
+	// ID is the ID of the document in Payload, which is either the
+	// uploaded document or the existing document that was matched.
+	// Numeric IDs are a json.Number and MongoDB IDs a string.
+	ID any
+	// Hash is the SHA-256 checksum of the file, which is only set when
+	// a hash field is used.
+	Hash string
+	// Skipped reports whether the file wasn't uploaded, because a file
+	// with the same checksum already exists.
+	Skipped bool
+	// Err is the error uploading the file.
+	Err error
+}
+
+// Err returns the errors of the files that failed to upload, joined
+// and sorted by path, or nil if every file was uploaded.
+func (m BulkManifest) Err() error {
+	var paths []string
+	for p, r := range m {
+		if r.Err != nil {
+			paths = append(paths, p)
+		}
+	}
+	sort.Strings(paths)
+
+	errs := make([]error, 0, len(paths))
+	for _, p := range paths {
+		errs = append(errs, fmt.Errorf("%s: %w", p, m[p].Err))
+	}
+	return errors.Join(errs...)
+}
+
+// UploadDir uploads the files within the directory on disk.
+// See UploadFS.
+func (b *BulkUploader) UploadDir(ctx context.Context, dir string) (BulkManifest, error) {
+	return b.UploadFS(ctx, os.DirFS(dir))
+}
+
+// UploadFS uploads every file within fsys. Files that fail to upload are
+// recorded in the manifest with their error and don't stop the others,
+// the error returned is only for walking the file system or the context
+// being cancelled.
+func (b *BulkUploader) UploadFS(ctx context.Context, fsys fs.FS) (BulkManifest, error) {
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		manifest = make(BulkManifest)
+		paths    = make(chan string)
+	)
+
+	for range b.config.workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for p := range paths {
+				r := b.upload(ctx, fsys, p)
+				mu.Lock()
+				manifest[p] = r
+				mu.Unlock()
+			}
+		}()
+	}
+
+	err := fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		if b.config.filter != nil && !b.config.filter(p) {
+			return nil
+		}
+		select {
+		case paths <- p:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	})
+	close(paths)
+	wg.Wait()
+
+	return manifest, err
+}
+
+// upload uploads a single file, skipping it if its checksum exists.
+func (b *BulkUploader) upload(ctx context.Context, fsys fs.FS, p string) BulkResult {
+	if err := ctx.Err(); err != nil {
+		return BulkResult{Err: err}
+	}
+	if b.config.hashField == "" {
+		id, err := b.send(ctx, fsys, p, "")
+		return BulkResult{ID: id, Err: err}
+	}
+
+	hash, err := hashFile(fsys, p)
+	if err != nil {
+		return BulkResult{Err: err}
+	}
+
+	b.mu.Lock()
+	h, seen := b.hashes[hash]
+	if !seen {
+		h = &bulkHash{done: make(chan struct{})}
+		b.hashes[hash] = h
+	}
+	b.mu.Unlock()
+
+	// Another file with the same content is being uploaded by this run.
+	if seen {
+		select {
+		case <-h.done:
+		case <-ctx.Done():
+			return BulkResult{Hash: hash, Err: ctx.Err()}
+		}
+		return BulkResult{ID: h.id, Hash: hash, Skipped: h.err == nil, Err: h.err}
+	}
+
+	defer close(h.done)
+
+	var skipped bool
+	h.id, h.err = b.existing(ctx, hash)
+	if h.err == nil && h.id != nil {
+		skipped = true
+	} else if h.err == nil {
+		h.id, h.err = b.send(ctx, fsys, p, hash)
+	}
+
+	if h.err != nil {
+		// Let a later file with the same content try again.
+		b.mu.Lock()
+		delete(b.hashes, hash)
+		b.mu.Unlock()
+	}
+
+	return BulkResult{ID: h.id, Hash: hash, Skipped: skipped, Err: h.err}
+}
+
+// existing returns the ID of the document with the checksum, or nil if
+// there isn't one.
+func (b *BulkUploader) existing(ctx context.Context, hash string) (any, error) {
+	var list ListResponse[json.RawMessage]
+	_, err := b.collections.List(ctx, b.config.collection, ListParams{
+		Where: Query().Equals(b.config.hashField, hash),
+		Limit: 1,
+	}, &list, WithDepth(0))
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up checksum: %w", err)
+	}
+	if len(list.Docs) == 0 {
+		return nil, nil
+	}
+	return documentID(list.Docs[0])
+}
+
+// send uploads the file, storing the checksum in the hash field when
+// it's set.
+func (b *BulkUploader) send(ctx context.Context, fsys fs.FS, p, hash string) (any, error) {
+	fields, err := b.fields(p, hash)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp CreateResponse[json.RawMessage]
+	_, err = b.media.UploadFS(ctx, fsys, p, fields, &resp, MediaOptions{
+		Collection: b.config.collection,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := documentID(resp.Doc)
+	if err == nil && id == nil {
+		err = errors.New("uploaded document has no id")
+	}
+	return id, err
+}
+
+// fields returns the fields to upload with the file, adding the checksum
+// to the fields returned by WithBulkFields.
+func (b *BulkUploader) fields(p, hash string) (any, error) {
+	var fields any
+	if b.config.fields != nil {
+		fields = b.config.fields(p)
+	}
+	if hash == "" {
+		return fields, nil
+	}
+
+	m := make(map[string]any)
+	if fields != nil {
+		buf, err := json.Marshal(fields)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(buf, &m); err != nil {
+			return nil, fmt.Errorf("fields must be an object: %w", err)
+		}
+	}
+	m[b.config.hashField] = hash
+
+	return m, nil
+}
+
+// hashFile returns the hex encoded SHA-256 checksum of the file.
+func hashFile(fsys fs.FS, p string) (string, error) {
+	f, err := fsys.Open(p)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,212 @@
+package payloadcms
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// bulkServer is a media collection that stores the checksum of each
+// upload, recording the uploads and how many were sent at once.
+type bulkServer struct {
+	mu       sync.Mutex
+	hashes   map[string]int
+	uploads  []string
+	alts     []string
+	active   int
+	max      int
+	failName string
+}
+
+func (s *bulkServer) client(t *testing.T) (MediaService, CollectionService) {
+	t.Helper()
+
+	client, teardown := Setup(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			s.mu.Lock()
+			id, ok := s.hashes[r.URL.Query().Get("where[sha256][equals]")]
+			s.mu.Unlock()
+			if !ok {
+				_, _ = w.Write([]byte(`{"docs": []}`))
+				return
+			}
+			_, _ = fmt.Fprintf(w, `{"docs": [{"id": %d}]}`, id)
+			return
+		}
+
+		s.mu.Lock()
+		s.active++
+		s.max = max(s.max, s.active)
+		s.mu.Unlock()
+
+		time.Sleep(5 * time.Millisecond)
+
+		_, header, err := r.FormFile("file")
+		require.NoError(t, err)
+		var fields map[string]any
+		require.NoError(t, json.Unmarshal([]byte(r.FormValue("_payload")), &fields))
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.active--
+
+		if header.Filename == s.failName {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"errors": [{"message": "Invalid file"}]}`))
+			return
+		}
+
+		id := len(s.uploads) + 100
+		s.uploads = append(s.uploads, header.Filename)
+		if hash, ok := fields["sha256"].(string); ok {
+			s.hashes[hash] = id
+		}
+		if alt, ok := fields["alt"].(string); ok {
+			s.alts = append(s.alts, alt)
+		}
+		_, _ = fmt.Fprintf(w, `{"doc": {"id": %d}, "message": "Created"}`, id)
+	})
+	t.Cleanup(teardown)
+
+	return MediaServiceOp{Client: client}, CollectionServiceOp{Client: client}
+}
+
+func TestBulkUploader(t *testing.T) {
+	t.Parallel()
+
+	files := func() fstest.MapFS {
+		fsys := fstest.MapFS{}
+		for i := range 10 {
+			fsys[fmt.Sprintf("images/%d.txt", i)] = &fstest.MapFile{Data: []byte(fmt.Sprintf("file %d", i))}
+		}
+		return fsys
+	}
+
+	t.Run("Uploads All Files", func(t *testing.T) {
+		t.Parallel()
+
+		srv := &bulkServer{hashes: map[string]int{}}
+		media, collections := srv.client(t)
+
+		manifest, err := NewBulkUploader(media, collections, WithBulkWorkers(3)).UploadFS(context.TODO(), files())
+		require.NoError(t, err)
+		require.NoError(t, manifest.Err())
+		AssertEqual(t, 10, len(manifest))
+		AssertEqual(t, 10, len(srv.uploads))
+		assert.LessOrEqual(t, srv.max, 3)
+		assert.Greater(t, srv.max, 1)
+
+		for _, r := range manifest {
+			assert.IsType(t, json.Number(""), r.ID)
+			AssertEqual(t, "", r.Hash)
+			AssertEqual(t, false, r.Skipped)
+		}
+	})
+
+	t.Run("Deduplicates", func(t *testing.T) {
+		t.Parallel()
+
+		srv := &bulkServer{hashes: map[string]int{}}
+		media, collections := srv.client(t)
+
+		fsys := files()
+		// Uploaded by a previous run.
+		srv.hashes["4da94f7ab13842d92f35deb8983d2edfe02631ff460c4080f4733de155b57fd4"] = 1
+		// The same content as another file in this run.
+		fsys["copy.txt"] = &fstest.MapFile{Data: []byte("file 1")}
+
+		uploader := NewBulkUploader(media, collections, WithBulkHashField("sha256"))
+		manifest, err := uploader.UploadFS(context.TODO(), fsys)
+		require.NoError(t, err)
+		require.NoError(t, manifest.Err())
+		AssertEqual(t, 11, len(manifest))
+		AssertEqual(t, 9, len(srv.uploads))
+		AssertEqual(t, true, manifest["images/0.txt"].Skipped)
+		AssertEqual[any](t, json.Number("1"), manifest["images/0.txt"].ID)
+		AssertEqual(t, manifest["copy.txt"].ID, manifest["images/1.txt"].ID)
+		AssertEqual(t, manifest["copy.txt"].Hash, manifest["images/1.txt"].Hash)
+		AssertEqual(t, true, manifest["copy.txt"].Skipped || manifest["images/1.txt"].Skipped)
+
+		// Running again skips everything.
+		manifest, err = NewBulkUploader(media, collections, WithBulkHashField("sha256")).UploadFS(context.TODO(), fsys)
+		require.NoError(t, err)
+		AssertEqual(t, 9, len(srv.uploads))
+		for p, r := range manifest {
+			AssertEqual(t, true, r.Skipped)
+			assert.NotNil(t, r.ID, p)
+		}
+	})
+
+	t.Run("Fields And Filter", func(t *testing.T) {
+		t.Parallel()
+
+		srv := &bulkServer{hashes: map[string]int{}}
+		media, collections := srv.client(t)
+
+		uploader := NewBulkUploader(media, collections,
+			WithBulkHashField("sha256"),
+			WithBulkFilter(func(p string) bool {
+				return p == "images/3.txt"
+			}),
+			WithBulkFields(func(p string) any {
+				return map[string]string{"alt": "Alt for " + p}
+			}),
+		)
+		manifest, err := uploader.UploadFS(context.TODO(), files())
+		require.NoError(t, err)
+		AssertEqual(t, 1, len(manifest))
+		assert.Equal(t, []string{"3.txt"}, srv.uploads)
+		assert.Equal(t, []string{"Alt for images/3.txt"}, srv.alts)
+		AssertEqual(t, 64, len(manifest["images/3.txt"].Hash))
+	})
+
+	t.Run("Per File Errors", func(t *testing.T) {
+		t.Parallel()
+
+		srv := &bulkServer{hashes: map[string]int{}, failName: "4.txt"}
+		media, collections := srv.client(t)
+
+		manifest, err := NewBulkUploader(media, collections).UploadFS(context.TODO(), files())
+		require.NoError(t, err)
+		AssertEqual(t, 9, len(srv.uploads))
+		AssertError(t, manifest["images/4.txt"].Err)
+		assert.Nil(t, manifest["images/4.txt"].ID)
+
+		err = manifest.Err()
+		AssertError(t, err)
+		AssertContains(t, err.Error(), "images/4.txt: ")
+		AssertContains(t, err.Error(), "Invalid file")
+	})
+
+	t.Run("Context Cancelled", func(t *testing.T) {
+		t.Parallel()
+
+		srv := &bulkServer{hashes: map[string]int{}}
+		media, collections := srv.client(t)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := NewBulkUploader(media, collections).UploadFS(ctx, files())
+		assert.ErrorIs(t, err, context.Canceled)
+		AssertEqual(t, 0, len(srv.uploads))
+	})
+
+	t.Run("Directory", func(t *testing.T) {
+		t.Parallel()
+
+		srv := &bulkServer{hashes: map[string]int{}}
+		media, collections := srv.client(t)
+
+		_, err := NewBulkUploader(media, collections).UploadDir(context.TODO(), "does-not-exist")
+		AssertError(t, err)
+	})
+}
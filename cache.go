@@ -0,0 +1,316 @@
+package payloadcms
+
+import (
+	"container/list"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Cache defines the methods used by the Client to store the response
+// bodies of GET requests. Implementations must be safe for concurrent
+// use, the default is an in-memory LRU cache, see NewMemoryCache.
+type Cache interface {
+	// Get retrieves the value stored under key, the boolean
+	// reports if the key was found and has not expired.
+	Get(key string) ([]byte, bool)
+	// Set stores the value under key for the given TTL. A TTL of
+	// zero or less means the entry never expires, which the Client
+	// only uses for the validators of WithConditionalRequests.
+	Set(key string, value []byte, ttl time.Duration)
+	// Delete removes the value stored under key.
+	Delete(key string)
+	// DeletePrefix removes every value with a key that starts
+	// with the given prefix.
+	DeletePrefix(prefix string)
+}
+
+// MemoryCache is an in-memory, least recently used Cache implementation.
+// Once the capacity has been reached, the least recently used entry
+// is evicted to make room for a new one.
+type MemoryCache struct {
+	mu       sync.Mutex
+	capacity int
+	items    map[string]*list.Element
+	order    *list.List
+	now      func() time.Time
+}
+
+type memoryCacheEntry struct {
+	key     string
+	value   []byte
+	expires time.Time
+}
+
+var _ Cache = (*MemoryCache)(nil)
+
+// DefaultCacheCapacity is the amount of entries a MemoryCache holds when
+// no capacity has been passed to NewMemoryCache.
+const DefaultCacheCapacity = 1000
+
+// NewMemoryCache creates a new in-memory LRU cache that holds at most
+// capacity entries. If capacity is zero or less, DefaultCacheCapacity
+// is used.
+func NewMemoryCache(capacity int) *MemoryCache {
+	if capacity <= 0 {
+		capacity = DefaultCacheCapacity
+	}
+	return &MemoryCache{
+		capacity: capacity,
+		items:    make(map[string]*list.Element),
+		order:    list.New(),
+		now:      time.Now,
+	}
+}
+
+// Get retrieves a copy of the value stored under key.
+func (m *MemoryCache) Get(key string) ([]byte, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	el, ok := m.items[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry := el.Value.(*memoryCacheEntry)
+	if !entry.expires.IsZero() && m.now().After(entry.expires) {
+		m.remove(el)
+		return nil, false
+	}
+
+	m.order.MoveToFront(el)
+
+	return append([]byte(nil), entry.value...), true
+}
+
+// Set stores a copy of the value under key for the given TTL.
+// A TTL of zero or less stores the entry without an expiry.
+func (m *MemoryCache) Set(key string, value []byte, ttl time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var expires time.Time
+	if ttl > 0 {
+		expires = m.now().Add(ttl)
+	}
+
+	entry := &memoryCacheEntry{
+		key:     key,
+		value:   append([]byte(nil), value...),
+		expires: expires,
+	}
+
+	if el, ok := m.items[key]; ok {
+		el.Value = entry
+		m.order.MoveToFront(el)
+		return
+	}
+
+	m.items[key] = m.order.PushFront(entry)
+
+	for m.order.Len() > m.capacity {
+		m.remove(m.order.Back())
+	}
+}
+
+// Delete removes the value stored under key.
+func (m *MemoryCache) Delete(key string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if el, ok := m.items[key]; ok {
+		m.remove(el)
+	}
+}
+
+// DeletePrefix removes every value with a key that starts with prefix.
+func (m *MemoryCache) DeletePrefix(prefix string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for key, el := range m.items {
+		if strings.HasPrefix(key, prefix) {
+			m.remove(el)
+		}
+	}
+}
+
+// Len returns the number of entries currently held in the cache,
+// including any that have expired but not yet been evicted.
+func (m *MemoryCache) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.order.Len()
+}
+
+func (m *MemoryCache) remove(el *list.Element) {
+	m.order.Remove(el)
+	delete(m.items, el.Value.(*memoryCacheEntry).key)
+}
+
+// responseCache holds the cache configuration of the Client and
+// resolves the TTL of a request by its collection or global.
+type responseCache struct {
+	store       Cache
+	ttl         time.Duration
+	collections map[Collection]time.Duration
+	globals     map[Global]time.Duration
+}
+
+// cacheConfig returns the cache configuration of the client,
+// creating it if it does not exist yet.
+func (c *Client) cacheConfig() *responseCache {
+	if c.cache == nil {
+		c.cache = &responseCache{
+			collections: make(map[Collection]time.Duration),
+			globals:     make(map[Global]time.Duration),
+		}
+	}
+	return c.cache
+}
+
+// cacheable reports if the request is a candidate for caching, which
+// excludes requests made with WithNoCache.
+func (c *Client) cacheable(req *http.Request) bool {
+	return c.cache != nil && c.cache.store != nil && req.Method == http.MethodGet &&
+		req.Header.Get("Cache-Control") != "no-cache"
+}
+
+// cacheKey returns the key of the request relative to the base URL
+// of the client. The key includes the full, sorted query so that
+// depth, locale and where queries are cached separately.
+//
+// For example: /api/posts/1?depth=1&locale=en
+func (c *Client) cacheKey(req *http.Request) string {
+	p := strings.TrimPrefix(req.URL.Path, c.basePath())
+	return p + "?" + req.URL.Query().Encode()
+}
+
+// basePath returns the path of the base URL, without a trailing slash.
+func (c *Client) basePath() string {
+	u, err := url.Parse(c.baseURL)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSuffix(u.Path, "/")
+}
+
+// cachedResponse returns the cached response of the request if one exists.
+func (c *Client) cachedResponse(req *http.Request) (Response, bool) {
+	if !c.cacheable(req) {
+		return Response{}, false
+	}
+
+	buf, ok := c.cache.store.Get(c.cacheKey(req))
+	if !ok {
+		return Response{}, false
+	}
+
+	return Response{
+		Response: &http.Response{
+			Status:     "200 OK",
+			StatusCode: http.StatusOK,
+			Header:     make(http.Header),
+			Request:    req,
+		},
+		Content: buf,
+	}, true
+}
+
+// storeResponse stores the content of a successful response if the
+// TTL for the collection or global is greater than zero.
+func (c *Client) storeResponse(req *http.Request, r Response) {
+	if !c.cacheable(req) {
+		return
+	}
+
+	key := c.cacheKey(req)
+	ttl := c.cache.ttlFor(key)
+	if ttl <= 0 {
+		return
+	}
+
+	c.cache.store.Set(key, r.Content, ttl)
+}
+
+// ttlFor resolves the TTL of a cache key, falling back to the
+// default TTL when the collection or global has none.
+func (rc *responseCache) ttlFor(key string) time.Duration {
+	p := strings.SplitN(key, "?", 2)[0]
+	segments := strings.Split(strings.TrimPrefix(p, "/api/"), "/")
+
+	if segments[0] == "globals" && len(segments) > 1 {
+		if ttl, ok := rc.globals[Global(segments[1])]; ok {
+			return ttl
+		}
+		return rc.ttl
+	}
+
+	if ttl, ok := rc.collections[Collection(segments[0])]; ok {
+		return ttl
+	}
+
+	return rc.ttl
+}
+
+// InvalidateDocument removes the cached responses of a document, along
+// with the lists and slug lookups of its collection, for when it has been
+// changed outside of the client, such as in the Payload admin. It's a
+// no-op unless WithCache is used.
+//
+// Documents of other collections that embed the document through a
+// relationship aren't removed, use InvalidateCollection for those.
+func (c *Client) InvalidateDocument(collection Collection, id any) {
+	c.cache.invalidateDocument(collection, id)
+}
+
+// InvalidateCollection removes every cached response of the collection,
+// including its documents, lists and slug lookups.
+func (c *Client) InvalidateCollection(collection Collection) {
+	c.cache.invalidateCollection(collection)
+}
+
+// InvalidateGlobal removes the cached responses of the global.
+func (c *Client) InvalidateGlobal(global Global) {
+	c.cache.invalidateGlobal(global)
+}
+
+// invalidateDocument removes the cached responses of a single document,
+// along with lists and slug lookups of the collection it belongs to.
+func (rc *responseCache) invalidateDocument(collection Collection, id any) {
+	if rc == nil || rc.store == nil {
+		return
+	}
+	rc.store.DeletePrefix(fmt.Sprintf("/api/%s/%v?", collection, id))
+	rc.store.DeletePrefix(fmt.Sprintf("/api/%s/slug/", collection))
+	rc.store.DeletePrefix(fmt.Sprintf("/api/%s?", collection))
+}
+
+// invalidateLists removes the cached list responses of the collection.
+func (rc *responseCache) invalidateLists(collection Collection) {
+	if rc == nil || rc.store == nil {
+		return
+	}
+	rc.store.DeletePrefix(fmt.Sprintf("/api/%s?", collection))
+}
+
+// invalidateCollection removes every cached response of the collection.
+func (rc *responseCache) invalidateCollection(collection Collection) {
+	if rc == nil || rc.store == nil {
+		return
+	}
+	rc.store.DeletePrefix(fmt.Sprintf("/api/%s/", collection))
+	rc.store.DeletePrefix(fmt.Sprintf("/api/%s?", collection))
+}
+
+// invalidateGlobal removes every cached response of the global.
+func (rc *responseCache) invalidateGlobal(global Global) {
+	if rc == nil || rc.store == nil {
+		return
+	}
+	rc.store.DeletePrefix(fmt.Sprintf("/api/globals/%s?", global))
+}
@@ -0,0 +1,468 @@
+package payloadcms
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryCache(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Get and Set", func(t *testing.T) {
+		t.Parallel()
+
+		c := NewMemoryCache(10)
+		c.Set("key", []byte("value"), time.Minute)
+
+		got, ok := c.Get("key")
+		assert.True(t, ok)
+		assert.Equal(t, "value", string(got))
+
+		_, ok = c.Get("missing")
+		assert.False(t, ok)
+	})
+
+	t.Run("Default Capacity", func(t *testing.T) {
+		t.Parallel()
+		assert.Equal(t, DefaultCacheCapacity, NewMemoryCache(0).capacity)
+	})
+
+	t.Run("Evicts Least Recently Used", func(t *testing.T) {
+		t.Parallel()
+
+		c := NewMemoryCache(2)
+		c.Set("a", []byte("1"), 0)
+		c.Set("b", []byte("2"), 0)
+
+		// Touch a so that b becomes the least recently used.
+		_, ok := c.Get("a")
+		require.True(t, ok)
+
+		c.Set("c", []byte("3"), 0)
+
+		_, ok = c.Get("b")
+		assert.False(t, ok)
+		_, ok = c.Get("a")
+		assert.True(t, ok)
+		_, ok = c.Get("c")
+		assert.True(t, ok)
+		assert.Equal(t, 2, c.Len())
+	})
+
+	t.Run("Overwrites", func(t *testing.T) {
+		t.Parallel()
+
+		c := NewMemoryCache(2)
+		c.Set("a", []byte("1"), 0)
+		c.Set("a", []byte("2"), 0)
+
+		got, ok := c.Get("a")
+		assert.True(t, ok)
+		assert.Equal(t, "2", string(got))
+		assert.Equal(t, 1, c.Len())
+	})
+
+	t.Run("Expires", func(t *testing.T) {
+		t.Parallel()
+
+		now := time.Now()
+		c := NewMemoryCache(10)
+		c.now = func() time.Time { return now }
+		c.Set("key", []byte("value"), time.Second)
+
+		_, ok := c.Get("key")
+		assert.True(t, ok)
+
+		now = now.Add(2 * time.Second)
+		_, ok = c.Get("key")
+		assert.False(t, ok)
+		assert.Equal(t, 0, c.Len())
+	})
+
+	t.Run("Returns Copy", func(t *testing.T) {
+		t.Parallel()
+
+		c := NewMemoryCache(10)
+		c.Set("key", []byte("value"), 0)
+
+		got, _ := c.Get("key")
+		got[0] = 'X'
+
+		got, _ = c.Get("key")
+		assert.Equal(t, "value", string(got))
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		t.Parallel()
+
+		c := NewMemoryCache(10)
+		c.Set("key", []byte("value"), 0)
+		c.Delete("key")
+		c.Delete("missing")
+
+		_, ok := c.Get("key")
+		assert.False(t, ok)
+	})
+
+	t.Run("Delete Prefix", func(t *testing.T) {
+		t.Parallel()
+
+		c := NewMemoryCache(10)
+		c.Set("/api/posts/1?", []byte("1"), 0)
+		c.Set("/api/posts/1?depth=2", []byte("1"), 0)
+		c.Set("/api/posts/10?", []byte("10"), 0)
+		c.DeletePrefix("/api/posts/1?")
+
+		assert.Equal(t, 1, c.Len())
+		_, ok := c.Get("/api/posts/10?")
+		assert.True(t, ok)
+	})
+}
+
+func setupCachedClient(t *testing.T, opts ...ClientOption) (*Client, *atomic.Int32, func()) {
+	t.Helper()
+
+	hits := &atomic.Int32{}
+	client, teardown := Setup(t, func(w http.ResponseWriter, _ *http.Request) {
+		hits.Add(1)
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write(defaultBody)
+		AssertNoError(t, err)
+	})
+
+	for _, opt := range opts {
+		opt(client)
+	}
+	client.Collections = CollectionServiceOp{Client: client}
+	client.Globals = GlobalsServiceOp{Client: client}
+	client.Media = MediaServiceOp{Client: client}
+
+	return client, hits, teardown
+}
+
+func TestClient_Cache(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	t.Run("Caches GET Requests", func(t *testing.T) {
+		t.Parallel()
+
+		client, hits, teardown := setupCachedClient(t, WithCache(NewMemoryCache(10), time.Minute))
+		defer teardown()
+
+		for range 3 {
+			var r Resource
+			resp, err := client.Collections.FindByID(ctx, "posts", 1, &r)
+			require.NoError(t, err)
+			assert.Equal(t, defaultResource, r)
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+			assert.Equal(t, string(defaultBody), string(resp.Content))
+		}
+
+		assert.Equal(t, int32(1), hits.Load())
+	})
+
+	t.Run("Key Includes Depth Locale And Query", func(t *testing.T) {
+		t.Parallel()
+
+		client, hits, teardown := setupCachedClient(t, WithCache(NewMemoryCache(10), time.Minute))
+		defer teardown()
+
+		calls := []func() (Response, error){
+			func() (Response, error) { return client.Collections.FindByID(ctx, "posts", 1, nil) },
+			func() (Response, error) { return client.Collections.FindByID(ctx, "posts", 1, nil, WithDepth(2)) },
+			func() (Response, error) { return client.Collections.FindByID(ctx, "posts", 1, nil, WithLocale("fr")) },
+			func() (Response, error) {
+				return client.Collections.List(ctx, "posts", ListParams{Where: Query().Equals("title", "a")}, nil)
+			},
+			func() (Response, error) {
+				return client.Collections.List(ctx, "posts", ListParams{Where: Query().Equals("title", "b")}, nil)
+			},
+		}
+
+		for range 2 {
+			for _, call := range calls {
+				_, err := call()
+				require.NoError(t, err)
+			}
+		}
+
+		assert.Equal(t, int32(len(calls)), hits.Load())
+	})
+
+	t.Run("No Cache Option", func(t *testing.T) {
+		t.Parallel()
+
+		cache := NewMemoryCache(10)
+		client, hits, teardown := setupCachedClient(t, WithCache(cache, time.Minute))
+		defer teardown()
+
+		for range 2 {
+			_, err := client.Collections.FindByID(ctx, "posts", 1, nil, WithNoCache())
+			require.NoError(t, err)
+		}
+
+		assert.Equal(t, int32(2), hits.Load())
+		assert.Equal(t, 0, cache.Len())
+	})
+
+	t.Run("Does Not Cache Writes", func(t *testing.T) {
+		t.Parallel()
+
+		client, hits, teardown := setupCachedClient(t, WithCache(NewMemoryCache(10), time.Minute))
+		defer teardown()
+
+		for range 2 {
+			_, err := client.Collections.Create(ctx, "posts", defaultResource)
+			require.NoError(t, err)
+		}
+
+		assert.Equal(t, int32(2), hits.Load())
+	})
+
+	t.Run("Collection TTL Of Zero Disables Caching", func(t *testing.T) {
+		t.Parallel()
+
+		client, hits, teardown := setupCachedClient(t,
+			WithCollectionCacheTTL("posts", 0),
+			WithCache(NewMemoryCache(10), time.Minute),
+		)
+		defer teardown()
+
+		for range 2 {
+			_, err := client.Collections.FindByID(ctx, "posts", 1, nil)
+			require.NoError(t, err)
+		}
+
+		assert.Equal(t, int32(2), hits.Load())
+	})
+
+	t.Run("Global TTL Override", func(t *testing.T) {
+		t.Parallel()
+
+		client, hits, teardown := setupCachedClient(t,
+			WithCache(NewMemoryCache(10), 0),
+			WithGlobalCacheTTL("settings", time.Minute),
+		)
+		defer teardown()
+
+		for range 2 {
+			_, err := client.Globals.Get(ctx, "settings", nil)
+			require.NoError(t, err)
+			_, err = client.Collections.FindByID(ctx, "posts", 1, nil)
+			require.NoError(t, err)
+		}
+
+		// Globals are cached, collections fall back to the default TTL of 0.
+		assert.Equal(t, int32(3), hits.Load())
+	})
+
+	t.Run("Invalidates On Write", func(t *testing.T) {
+		t.Parallel()
+
+		tt := map[string]func(c *Client) error{
+			"UpdateByID": func(c *Client) error {
+				_, err := c.Collections.UpdateByID(ctx, "posts", 1, defaultResource)
+				return err
+			},
+			"DeleteByID": func(c *Client) error {
+				_, err := c.Collections.DeleteByID(ctx, "posts", 1)
+				return err
+			},
+			"Media Replace": func(c *Client) error {
+				_, err := c.Media.Replace(ctx, 1, strings.NewReader("file"), nil, nil, MediaOptions{
+					Collection: "posts",
+					FileName:   "file",
+				})
+				return err
+			},
+		}
+
+		for name, write := range tt {
+			t.Run(name, func(t *testing.T) {
+				t.Parallel()
+
+				cache := NewMemoryCache(10)
+				client, _, teardown := setupCachedClient(t, WithCache(cache, time.Minute))
+				defer teardown()
+
+				_, err := client.Collections.FindByID(ctx, "posts", 1, nil, WithDepth(2))
+				require.NoError(t, err)
+				_, err = client.Collections.FindByID(ctx, "posts", 10, nil)
+				require.NoError(t, err)
+				_, err = client.Collections.List(ctx, "posts", ListParams{}, nil)
+				require.NoError(t, err)
+				require.Equal(t, 3, cache.Len())
+
+				require.NoError(t, write(client))
+
+				_, ok := cache.Get("/api/posts/10?")
+				assert.True(t, ok)
+				assert.Equal(t, 1, cache.Len())
+			})
+		}
+	})
+
+	t.Run("Invalidates Lists On Upload", func(t *testing.T) {
+		t.Parallel()
+
+		cache := NewMemoryCache(10)
+		client, _, teardown := setupCachedClient(t, WithCache(cache, time.Minute))
+		defer teardown()
+
+		_, err := client.Collections.FindByID(ctx, "posts", 1, nil)
+		require.NoError(t, err)
+		_, err = client.Collections.List(ctx, "posts", ListParams{}, nil)
+		require.NoError(t, err)
+		require.Equal(t, 2, cache.Len())
+
+		_, err = client.Media.Upload(ctx, strings.NewReader("file"), nil, nil, MediaOptions{
+			Collection: "posts",
+			FileName:   "file",
+		})
+		require.NoError(t, err)
+
+		_, ok := cache.Get("/api/posts/1?")
+		assert.True(t, ok)
+		assert.Equal(t, 1, cache.Len())
+	})
+
+	t.Run("Invalidates Globals On Update", func(t *testing.T) {
+		t.Parallel()
+
+		cache := NewMemoryCache(10)
+		client, hits, teardown := setupCachedClient(t, WithCache(cache, time.Minute))
+		defer teardown()
+
+		_, err := client.Globals.Get(ctx, "settings", nil)
+		require.NoError(t, err)
+		_, err = client.Globals.Update(ctx, "settings", nil)
+		require.NoError(t, err)
+		_, err = client.Globals.Get(ctx, "settings", nil)
+		require.NoError(t, err)
+
+		assert.Equal(t, int32(3), hits.Load())
+	})
+}
+
+func TestClient_Invalidate(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	// fill caches a document and list of posts and pages, and the
+	// settings global.
+	fill := func(t *testing.T, client *Client) {
+		t.Helper()
+		for _, collection := range []Collection{"posts", "pages"} {
+			_, err := client.Collections.FindByID(ctx, collection, 1, nil)
+			require.NoError(t, err)
+			_, err = client.Collections.FindByID(ctx, collection, 2, nil, WithDepth(1))
+			require.NoError(t, err)
+			_, err = client.Collections.FindBySlug(ctx, collection, "home", nil)
+			require.NoError(t, err)
+			_, err = client.Collections.List(ctx, collection, ListParams{Limit: 5}, nil)
+			require.NoError(t, err)
+		}
+		_, err := client.Globals.Get(ctx, "settings", nil)
+		require.NoError(t, err)
+	}
+
+	tt := map[string]struct {
+		invalidate func(c *Client)
+		want       []string
+	}{
+		"Document": {
+			invalidate: func(c *Client) { c.InvalidateDocument("posts", 1) },
+			want: []string{
+				"/api/posts/2?depth=1",
+				"/api/pages/1?", "/api/pages/2?depth=1", "/api/pages/slug/home?", "/api/pages?limit=5",
+				"/api/globals/settings?",
+			},
+		},
+		"Collection": {
+			invalidate: func(c *Client) { c.InvalidateCollection("posts") },
+			want: []string{
+				"/api/pages/1?", "/api/pages/2?depth=1", "/api/pages/slug/home?", "/api/pages?limit=5",
+				"/api/globals/settings?",
+			},
+		},
+		"Global": {
+			invalidate: func(c *Client) { c.InvalidateGlobal("settings") },
+			want: []string{
+				"/api/posts/1?", "/api/posts/2?depth=1", "/api/posts/slug/home?", "/api/posts?limit=5",
+				"/api/pages/1?", "/api/pages/2?depth=1", "/api/pages/slug/home?", "/api/pages?limit=5",
+			},
+		},
+	}
+
+	for name, test := range tt {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			cache := NewMemoryCache(20)
+			client, _, teardown := setupCachedClient(t, WithCache(cache, time.Minute))
+			defer teardown()
+
+			fill(t, client)
+			require.Equal(t, 9, cache.Len())
+
+			test.invalidate(client)
+
+			assert.Equal(t, len(test.want), cache.Len())
+			for _, key := range test.want {
+				_, ok := cache.Get(key)
+				assert.True(t, ok, key)
+			}
+		})
+	}
+
+	t.Run("No Cache", func(t *testing.T) {
+		t.Parallel()
+
+		client, _, teardown := setupCachedClient(t)
+		defer teardown()
+
+		assert.NotPanics(t, func() {
+			client.InvalidateDocument("posts", 1)
+			client.InvalidateCollection("posts")
+			client.InvalidateGlobal("settings")
+		})
+	})
+}
+
+func TestResponseCache_TTLFor(t *testing.T) {
+	t.Parallel()
+
+	rc := &responseCache{
+		ttl:         time.Minute,
+		collections: map[Collection]time.Duration{"posts": time.Hour},
+		globals:     map[Global]time.Duration{"settings": time.Second},
+	}
+
+	tt := map[string]struct {
+		input string
+		want  time.Duration
+	}{
+		"Collection":         {input: "/api/posts/1?depth=1", want: time.Hour},
+		"Collection List":    {input: "/api/posts?limit=10", want: time.Hour},
+		"Default Collection": {input: "/api/users/1?", want: time.Minute},
+		"Global":             {input: "/api/globals/settings?", want: time.Second},
+		"Default Global":     {input: "/api/globals/header?", want: time.Minute},
+	}
+
+	for name, test := range tt {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, test.want, rc.ttlFor(test.input))
+		})
+	}
+}
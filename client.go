@@ -24,6 +24,7 @@ type Service interface {
 	Get(ctx context.Context, path string, v any, opts ...RequestOption) (Response, error)
 	Post(ctx context.Context, path string, in any, opts ...RequestOption) (Response, error)
 	Put(ctx context.Context, path string, in any, opts ...RequestOption) (Response, error)
+	Patch(ctx context.Context, path string, in any, opts ...RequestOption) (Response, error)
 	Delete(ctx context.Context, path string, v any, opts ...RequestOption) (Response, error)
 }
 
@@ -53,6 +54,10 @@ type Client struct {
 	apiKey      string
 	reader      func(io.Reader) ([]byte, error)
 	queryValues func(v any) (url.Values, error)
+	cache       *responseCache
+	validators  Cache
+	flights     *flightGroup
+	mediaCache  *mediaCache
 }
 
 var _ Service = (*Client)(nil)
@@ -245,6 +250,36 @@ func (c *Client) performRequest(req *http.Request, opts ...RequestOption) (Respo
 		opt(req)
 	}
 
+	if r, ok := c.cachedResponse(req); ok {
+		return r, nil
+	}
+
+	if c.flights != nil && req.Method == http.MethodGet {
+		return c.flights.do(req, c.fetch)
+	}
+
+	return c.fetch(req)
+}
+
+// fetch sends the request to Payload, revalidating and storing the
+// response when conditional requests or caching are enabled.
+func (c *Client) fetch(req *http.Request) (Response, error) {
+	c.conditionalHeaders(req)
+
+	r, err := c.roundTrip(req)
+	if err != nil {
+		return r, err
+	}
+
+	c.storeValidators(req, r)
+	c.storeResponse(req, r)
+
+	return r, nil
+}
+
+// roundTrip sends the request using the HTTP client and reads the
+// response body, returning an error if the status code is not 2xx.
+func (c *Client) roundTrip(req *http.Request) (Response, error) {
 	resp, err := c.client.Do(req)
 	if err != nil {
 		return Response{Response: &http.Response{}}, err
@@ -259,6 +294,15 @@ func (c *Client) performRequest(req *http.Request, opts ...RequestOption) (Respo
 	}
 	r.Content = buf
 
+	if resp.StatusCode == http.StatusNotModified {
+		if content, ok := c.notModified(req); ok {
+			r.Content = content
+			r.StatusCode = http.StatusOK
+			r.Status = "200 OK"
+			return r, nil
+		}
+	}
+
 	if !is2xx(resp.StatusCode) {
 		if string(buf) == "" {
 			return r, errors.New("received no body with status code: " + resp.Status)
@@ -0,0 +1,95 @@
+// Command payloadgen generates Go types from a Payload config.
+//
+// It reads either the JSON schema of the config or the payload-types.ts
+// file generated by "payload generate:types", and writes a Go file with a
+// struct for each collection and global, constants for their slugs and
+// field names, and a typed client. The output is deterministic, so it's
+// safe to commit and can be run from go generate:
+//
+//	//go:generate go run github.com/ainsleyclark/go-payloadcms/cmd/payloadgen -input ../dev/src/payload-types.ts -output models_gen.go -package models
+//
+// Usage:
+//
+//	payloadgen -input <file> [-output <file>] [-package <name>]
+//
+// Inputs ending in .ts are parsed as TypeScript, and everything else as
+// JSON schema. The output is written to stdout when -output is omitted.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ainsleyclark/go-payloadcms/internal/codegen"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "payloadgen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("payloadgen", flag.ContinueOnError)
+	input := fs.String("input", "", "path to the JSON schema or payload-types.ts file")
+	output := fs.String("output", "", "path of the generated Go file, defaults to stdout")
+	pkg := fs.String("package", "", "package name of the generated file, defaults to $GOPACKAGE or the output directory")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *input == "" {
+		return errors.New("-input is required")
+	}
+
+	buf, err := os.ReadFile(*input)
+	if err != nil {
+		return err
+	}
+
+	var src *codegen.Source
+	if strings.EqualFold(filepath.Ext(*input), ".ts") {
+		src, err = codegen.ParseTypeScript(buf)
+	} else {
+		src, err = codegen.ParseJSONSchema(buf)
+	}
+	if err != nil {
+		return fmt.Errorf("parsing %s: %w", *input, err)
+	}
+
+	out, err := codegen.Generate(src, codegen.Options{
+		Package: packageName(*pkg, *output),
+	})
+	if err != nil {
+		return err
+	}
+
+	if *output == "" {
+		_, err = stdout.Write(out)
+		return err
+	}
+	return os.WriteFile(*output, out, 0o644) //nolint:gosec
+}
+
+// packageName returns the name of the package to generate, which
+// defaults to the package go generate is run from, then the name of the
+// output directory.
+func packageName(flagValue, output string) string {
+	if flagValue != "" {
+		return flagValue
+	}
+	if env := os.Getenv("GOPACKAGE"); env != "" {
+		return env
+	}
+	if output != "" {
+		if abs, err := filepath.Abs(output); err == nil {
+			return filepath.Base(filepath.Dir(abs))
+		}
+	}
+	return "models"
+}
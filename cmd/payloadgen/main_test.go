@@ -0,0 +1,49 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun(t *testing.T) {
+	t.Run("Stdout", func(t *testing.T) {
+		var buf bytes.Buffer
+		err := run([]string{"-input", "../../internal/codegen/testdata/schema.json", "-package", "models"}, &buf)
+		require.NoError(t, err)
+
+		want, err := os.ReadFile("../../internal/codegen/testdata/schema.golden")
+		require.NoError(t, err)
+		assert.Equal(t, string(want), buf.String())
+	})
+
+	t.Run("Output File", func(t *testing.T) {
+		t.Setenv("GOPACKAGE", "")
+
+		dir := filepath.Join(t.TempDir(), "content")
+		require.NoError(t, os.Mkdir(dir, 0o755))
+		output := filepath.Join(dir, "models_gen.go")
+
+		err := run([]string{"-input", "../../internal/codegen/testdata/payload-types.ts", "-output", output}, &bytes.Buffer{})
+		require.NoError(t, err)
+
+		got, err := os.ReadFile(output)
+		require.NoError(t, err)
+		assert.Contains(t, string(got), "package content")
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		for name, args := range map[string][]string{
+			"No Input":   {},
+			"Bad Flag":   {"-nope"},
+			"No File":    {"-input", "missing.json"},
+			"Bad Schema": {"-input", "main.go"},
+		} {
+			assert.Error(t, run(args, &bytes.Buffer{}), name)
+		}
+	})
+}
@@ -0,0 +1,105 @@
+package payloadcms
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"sync"
+)
+
+// flightGroup deduplicates identical GET requests that are in flight at
+// the same time, so that only one HTTP call is made to Payload and the
+// result is shared between every caller.
+type flightGroup struct {
+	mu    sync.Mutex
+	calls map[string]*flight
+}
+
+// flight is a single in-flight request shared by one or more callers.
+type flight struct {
+	done    chan struct{}
+	resp    Response
+	err     error
+	waiters int
+	cancel  context.CancelFunc
+}
+
+func newFlightGroup() *flightGroup {
+	return &flightGroup{calls: make(map[string]*flight)}
+}
+
+// flightKey identifies identical requests by method, URL (including
+// the query) and authorization.
+func flightKey(req *http.Request) string {
+	return req.Method + " " + req.URL.String() + " " + req.Header.Get("Authorization")
+}
+
+// do executes fn once for every identical request that is in flight.
+//
+// The shared call runs with a context that is detached from the callers,
+// so cancelling one caller does not cancel the others. The shared call
+// is only cancelled once every caller has gone away.
+func (g *flightGroup) do(req *http.Request, fn func(*http.Request) (Response, error)) (Response, error) {
+	ctx := req.Context()
+	key := flightKey(req)
+
+	g.mu.Lock()
+	f, ok := g.calls[key]
+	if !ok {
+		fctx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		f = &flight{
+			done:   make(chan struct{}),
+			cancel: cancel,
+		}
+		g.calls[key] = f
+
+		go func() {
+			defer cancel()
+			f.resp, f.err = fn(req.WithContext(fctx))
+			g.forget(key, f)
+			close(f.done)
+		}()
+	}
+	f.waiters++
+	g.mu.Unlock()
+
+	select {
+	case <-f.done:
+		return f.response(), f.err
+	case <-ctx.Done():
+		g.mu.Lock()
+		f.waiters--
+		if f.waiters == 0 {
+			f.cancel()
+			if g.calls[key] == f {
+				delete(g.calls, key)
+			}
+		}
+		g.mu.Unlock()
+		return Response{Response: &http.Response{}}, ctx.Err()
+	}
+}
+
+// forget removes the flight from the group, if it has not been
+// replaced by a newer flight for the same key.
+func (g *flightGroup) forget(key string, f *flight) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.calls[key] == f {
+		delete(g.calls, key)
+	}
+}
+
+// response returns a copy of the shared response so that callers
+// can't modify each other's content or headers.
+func (f *flight) response() Response {
+	r := f.resp
+	if r.Response != nil {
+		hr := *r.Response
+		hr.Header = r.Response.Header.Clone()
+		r.Response = &hr
+	}
+	r.Content = slices.Clone(r.Content)
+	r.Errors = slices.Clone(r.Errors)
+	return r
+}
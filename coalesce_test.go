@@ -0,0 +1,169 @@
+package payloadcms
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// waitForWaiters blocks until the flight for the request has n callers.
+func waitForWaiters(t *testing.T, g *flightGroup, n int) {
+	t.Helper()
+
+	require.Eventually(t, func() bool {
+		g.mu.Lock()
+		defer g.mu.Unlock()
+		for _, f := range g.calls {
+			if f.waiters == n {
+				return true
+			}
+		}
+		return false
+	}, time.Second, time.Millisecond)
+}
+
+func TestClient_RequestCoalescing(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Shares One Call", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			calls   atomic.Int32
+			release = make(chan struct{})
+		)
+		client, teardown := Setup(t, func(w http.ResponseWriter, _ *http.Request) {
+			calls.Add(1)
+			<-release
+			w.WriteHeader(http.StatusOK)
+			_, err := w.Write(defaultBody)
+			AssertNoError(t, err)
+		})
+		defer teardown()
+		WithRequestCoalescing()(client)
+
+		const n = 10
+		var (
+			wg        sync.WaitGroup
+			resources = make([]Resource, n)
+			responses = make([]Response, n)
+		)
+		for i := range n {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				resp, err := client.Get(context.Background(), "/api/posts/1", &resources[i])
+				AssertNoError(t, err)
+				responses[i] = resp
+			}()
+		}
+
+		waitForWaiters(t, client.flights, n)
+		close(release)
+		wg.Wait()
+
+		assert.Equal(t, int32(1), calls.Load())
+		for i := range n {
+			assert.Equal(t, defaultResource, resources[i])
+			assert.Equal(t, string(defaultBody), string(responses[i].Content))
+		}
+
+		// Each caller has its own copy of the content.
+		responses[0].Content[0] = 'X'
+		assert.Equal(t, string(defaultBody), string(responses[1].Content))
+
+		// And its own copy of the headers.
+		responses[0].Header.Set("X-Test", "changed")
+		assert.Empty(t, responses[1].Header.Get("X-Test"))
+	})
+
+	t.Run("Different Queries Are Not Shared", func(t *testing.T) {
+		t.Parallel()
+
+		var calls atomic.Int32
+		client, teardown := Setup(t, func(w http.ResponseWriter, _ *http.Request) {
+			calls.Add(1)
+			w.WriteHeader(http.StatusOK)
+		})
+		defer teardown()
+		WithRequestCoalescing()(client)
+
+		_, err := client.Get(context.Background(), "/api/posts/1", nil, WithDepth(1))
+		require.NoError(t, err)
+		_, err = client.Get(context.Background(), "/api/posts/1", nil, WithDepth(2))
+		require.NoError(t, err)
+
+		assert.Equal(t, int32(2), calls.Load())
+	})
+
+	t.Run("Cancelling One Caller", func(t *testing.T) {
+		t.Parallel()
+
+		release := make(chan struct{})
+		client, teardown := Setup(t, func(w http.ResponseWriter, _ *http.Request) {
+			<-release
+			w.WriteHeader(http.StatusOK)
+			_, err := w.Write(defaultBody)
+			AssertNoError(t, err)
+		})
+		defer teardown()
+		WithRequestCoalescing()(client)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancelled := make(chan error, 1)
+		go func() {
+			_, err := client.Get(ctx, "/api/posts/1", nil)
+			cancelled <- err
+		}()
+		waitForWaiters(t, client.flights, 1)
+
+		result := make(chan Resource, 1)
+		go func() {
+			var r Resource
+			_, err := client.Get(context.Background(), "/api/posts/1", &r)
+			AssertNoError(t, err)
+			result <- r
+		}()
+		waitForWaiters(t, client.flights, 2)
+
+		cancel()
+		assert.ErrorIs(t, <-cancelled, context.Canceled)
+
+		close(release)
+		assert.Equal(t, defaultResource, <-result)
+	})
+
+	t.Run("Cancelling Every Caller", func(t *testing.T) {
+		t.Parallel()
+
+		release := make(chan struct{})
+		client, teardown := Setup(t, func(w http.ResponseWriter, _ *http.Request) {
+			<-release
+			w.WriteHeader(http.StatusOK)
+		})
+		defer teardown()
+		defer close(release)
+		WithRequestCoalescing()(client)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error, 1)
+		go func() {
+			_, err := client.Get(ctx, "/api/posts/1", nil)
+			done <- err
+		}()
+		waitForWaiters(t, client.flights, 1)
+
+		cancel()
+		assert.ErrorIs(t, <-done, context.Canceled)
+
+		client.flights.mu.Lock()
+		assert.Empty(t, client.flights.calls)
+		client.flights.mu.Unlock()
+	})
+}
@@ -95,17 +95,29 @@ func (s CollectionServiceOp) List(ctx context.Context, collection Collection, pa
 // Create creates a new collection entity.
 func (s CollectionServiceOp) Create(ctx context.Context, collection Collection, in any, opts ...RequestOption) (Response, error) {
 	path := fmt.Sprintf("/api/%s", collection)
-	return s.Client.Do(ctx, http.MethodPost, path, in, nil, opts...)
+	resp, err := s.Client.Do(ctx, http.MethodPost, path, in, nil, opts...)
+	if err == nil {
+		s.Client.cache.invalidateLists(collection)
+	}
+	return resp, err
 }
 
 // UpdateByID updates a collection entity by its ID.
 func (s CollectionServiceOp) UpdateByID(ctx context.Context, collection Collection, id any, in any, opts ...RequestOption) (Response, error) {
 	path := fmt.Sprintf("/api/%s/%v", collection, id)
-	return s.Client.Do(ctx, http.MethodPatch, path, in, nil, opts...)
+	resp, err := s.Client.Do(ctx, http.MethodPatch, path, in, nil, opts...)
+	if err == nil {
+		s.Client.cache.invalidateDocument(collection, id)
+	}
+	return resp, err
 }
 
 // DeleteByID deletes a collection entity by its ID.
 func (s CollectionServiceOp) DeleteByID(ctx context.Context, collection Collection, id any, opts ...RequestOption) (Response, error) {
 	path := fmt.Sprintf("/api/%s/%v", collection, id)
-	return s.Client.Do(ctx, http.MethodDelete, path, nil, nil, opts...)
+	resp, err := s.Client.Do(ctx, http.MethodDelete, path, nil, nil, opts...)
+	if err == nil {
+		s.Client.cache.invalidateDocument(collection, id)
+	}
+	return resp, err
 }
@@ -0,0 +1,98 @@
+package payloadcms
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// validatorEntry is the value stored for a GET request when conditional
+// requests are enabled. It holds the validators sent back by Payload
+// alongside the body, so it can be returned when a 304 is received.
+type validatorEntry struct {
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"lastModified,omitempty"`
+	Content      []byte `json:"content"`
+}
+
+// conditionalHeaders sets the If-None-Match and If-Modified-Since headers
+// on the request if validators have been stored from a previous response.
+// Headers that have already been set on the request are left untouched.
+func (c *Client) conditionalHeaders(req *http.Request) {
+	if c.validators == nil || req.Method != http.MethodGet {
+		return
+	}
+
+	entry, ok := c.validatorEntry(req)
+	if !ok {
+		return
+	}
+
+	if entry.ETag != "" && req.Header.Get("If-None-Match") == "" {
+		req.Header.Set("If-None-Match", entry.ETag)
+	}
+	if entry.LastModified != "" && req.Header.Get("If-Modified-Since") == "" {
+		req.Header.Set("If-Modified-Since", entry.LastModified)
+	}
+}
+
+// notModified returns the stored body of the request when Payload
+// responds with a 304 Not Modified.
+func (c *Client) notModified(req *http.Request) ([]byte, bool) {
+	if c.validators == nil || req.Method != http.MethodGet {
+		return nil, false
+	}
+
+	entry, ok := c.validatorEntry(req)
+	if !ok {
+		return nil, false
+	}
+
+	return entry.Content, true
+}
+
+// storeValidators stores the ETag and Last-Modified validators of a
+// successful response along with its body.
+func (c *Client) storeValidators(req *http.Request, r Response) {
+	if c.validators == nil || req.Method != http.MethodGet || r.Response == nil {
+		return
+	}
+
+	entry := validatorEntry{
+		ETag:         r.Header.Get("ETag"),
+		LastModified: r.Header.Get("Last-Modified"),
+		Content:      r.Content,
+	}
+	if entry.ETag == "" && entry.LastModified == "" {
+		return
+	}
+
+	buf, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+
+	// Validators are kept until they're evicted, as they're checked
+	// by Payload on every request.
+	c.validators.Set(c.validatorKey(req), buf, 0)
+}
+
+// validatorKey returns the key the validators of the request are stored
+// under. It's prefixed so that a store can be shared with WithCache
+// without the entries of one being read as the other.
+func (c *Client) validatorKey(req *http.Request) string {
+	return "validators:" + c.cacheKey(req)
+}
+
+func (c *Client) validatorEntry(req *http.Request) (validatorEntry, bool) {
+	buf, ok := c.validators.Get(c.validatorKey(req))
+	if !ok {
+		return validatorEntry{}, false
+	}
+
+	var entry validatorEntry
+	if err := json.Unmarshal(buf, &entry); err != nil {
+		return validatorEntry{}, false
+	}
+
+	return entry, true
+}
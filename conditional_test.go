@@ -0,0 +1,152 @@
+package payloadcms
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_ConditionalRequests(t *testing.T) {
+	t.Parallel()
+
+	const (
+		etag         = `"abc123"`
+		lastModified = "Wed, 21 Oct 2015 07:28:00 GMT"
+	)
+
+	ctx := context.Background()
+
+	t.Run("ETag", func(t *testing.T) {
+		t.Parallel()
+
+		var calls atomic.Int32
+		client, teardown := Setup(t, func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			if r.Header.Get("If-None-Match") == etag {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Header().Set("ETag", etag)
+			w.WriteHeader(http.StatusOK)
+			_, err := w.Write(defaultBody)
+			AssertNoError(t, err)
+		})
+		defer teardown()
+		WithConditionalRequests(nil)(client)
+
+		for range 2 {
+			var r Resource
+			resp, err := client.Get(ctx, "/api/posts/1", &r)
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+			assert.Equal(t, string(defaultBody), string(resp.Content))
+			assert.Equal(t, defaultResource, r)
+		}
+
+		assert.Equal(t, int32(2), calls.Load())
+	})
+
+	t.Run("Shared Store", func(t *testing.T) {
+		t.Parallel()
+
+		client, teardown := Setup(t, func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("If-None-Match") == etag {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Header().Set("ETag", etag)
+			w.WriteHeader(http.StatusOK)
+			_, err := w.Write(defaultBody)
+			AssertNoError(t, err)
+		})
+		defer teardown()
+
+		store := NewMemoryCache(10)
+		WithCache(store, 0)(client)
+		WithConditionalRequests(store)(client)
+		client.Globals = GlobalsServiceOp{Client: client}
+
+		for range 2 {
+			var r Resource
+			resp, err := client.Globals.Get(ctx, "settings", &r)
+			require.NoError(t, err)
+			assert.Equal(t, string(defaultBody), string(resp.Content))
+			assert.Equal(t, defaultResource, r)
+		}
+	})
+
+	t.Run("Last Modified", func(t *testing.T) {
+		t.Parallel()
+
+		client, teardown := Setup(t, func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("If-Modified-Since") == lastModified {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Header().Set("Last-Modified", lastModified)
+			w.WriteHeader(http.StatusOK)
+			_, err := w.Write(defaultBody)
+			AssertNoError(t, err)
+		})
+		defer teardown()
+		WithConditionalRequests(NewMemoryCache(10))(client)
+
+		for range 2 {
+			resp, err := client.Get(ctx, "/api/posts/1", nil)
+			require.NoError(t, err)
+			assert.Equal(t, string(defaultBody), string(resp.Content))
+		}
+	})
+
+	t.Run("Validators Are Stored Per Query", func(t *testing.T) {
+		t.Parallel()
+
+		client, teardown := Setup(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.Empty(t, r.Header.Get("If-None-Match"))
+			w.Header().Set("ETag", etag)
+			w.WriteHeader(http.StatusOK)
+			_, err := w.Write(defaultBody)
+			AssertNoError(t, err)
+		})
+		defer teardown()
+		WithConditionalRequests(nil)(client)
+
+		_, err := client.Get(ctx, "/api/posts/1", nil, WithDepth(1))
+		require.NoError(t, err)
+		_, err = client.Get(ctx, "/api/posts/1", nil, WithDepth(2))
+		require.NoError(t, err)
+	})
+
+	t.Run("Not Modified Without Stored Body", func(t *testing.T) {
+		t.Parallel()
+
+		client, teardown := Setup(t, func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusNotModified)
+		})
+		defer teardown()
+		WithConditionalRequests(nil)(client)
+
+		_, err := client.Get(ctx, "/api/posts/1", nil)
+		assert.Error(t, err)
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		t.Parallel()
+
+		client, teardown := Setup(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.Empty(t, r.Header.Get("If-None-Match"))
+			w.Header().Set("ETag", etag)
+			w.WriteHeader(http.StatusOK)
+		})
+		defer teardown()
+
+		for range 2 {
+			_, err := client.Get(ctx, "/api/posts/1", nil)
+			require.NoError(t, err)
+		}
+	})
+}
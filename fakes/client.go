@@ -9,64 +9,85 @@ import (
 
 // MockService is a mock implementation of the Service interface.
 type MockService struct {
-	DoFunc            func(ctx context.Context, method, path string, body any, v any) (payloadcms.Response, error)
-	DoWithRequestFunc func(ctx context.Context, req *http.Request, v any) (payloadcms.Response, error)
-	GetFunc           func(ctx context.Context, path string, v any) (payloadcms.Response, error)
-	PostFunc          func(ctx context.Context, path string, in any) (payloadcms.Response, error)
-	PutFunc           func(ctx context.Context, path string, in any) (payloadcms.Response, error)
-	DeleteFunc        func(ctx context.Context, path string, v any) (payloadcms.Response, error)
+	Recorder
+
+	DoFunc            func(ctx context.Context, method, path string, body any, v any, opts ...payloadcms.RequestOption) (payloadcms.Response, error)
+	DoWithRequestFunc func(ctx context.Context, req *http.Request, v any, opts ...payloadcms.RequestOption) (payloadcms.Response, error)
+	GetFunc           func(ctx context.Context, path string, v any, opts ...payloadcms.RequestOption) (payloadcms.Response, error)
+	PostFunc          func(ctx context.Context, path string, in any, opts ...payloadcms.RequestOption) (payloadcms.Response, error)
+	PutFunc           func(ctx context.Context, path string, in any, opts ...payloadcms.RequestOption) (payloadcms.Response, error)
+	PatchFunc         func(ctx context.Context, path string, in any, opts ...payloadcms.RequestOption) (payloadcms.Response, error)
+	DeleteFunc        func(ctx context.Context, path string, v any, opts ...payloadcms.RequestOption) (payloadcms.Response, error)
 }
 
+// Ensure MockService implements the Service interface.
+var _ payloadcms.Service = (*MockService)(nil)
+
 // NewMockService creates a new fake service stub.
 func NewMockService() *MockService {
 	return &MockService{
-		DoFunc: func(_ context.Context, _ string, _ string, _ any, _ any) (payloadcms.Response, error) {
+		DoFunc: func(_ context.Context, _ string, _ string, _ any, _ any, _ ...payloadcms.RequestOption) (payloadcms.Response, error) {
+			return payloadcms.Response{}, nil
+		},
+		DoWithRequestFunc: func(_ context.Context, _ *http.Request, _ any, _ ...payloadcms.RequestOption) (payloadcms.Response, error) {
 			return payloadcms.Response{}, nil
 		},
-		DoWithRequestFunc: func(_ context.Context, _ *http.Request, _ any) (payloadcms.Response, error) {
+		GetFunc: func(_ context.Context, _ string, _ any, _ ...payloadcms.RequestOption) (payloadcms.Response, error) {
 			return payloadcms.Response{}, nil
 		},
-		GetFunc: func(_ context.Context, _ string, _ any) (payloadcms.Response, error) {
+		PostFunc: func(_ context.Context, _ string, _ any, _ ...payloadcms.RequestOption) (payloadcms.Response, error) {
 			return payloadcms.Response{}, nil
 		},
-		PostFunc: func(_ context.Context, _ string, _ any) (payloadcms.Response, error) {
+		PutFunc: func(_ context.Context, _ string, _ any, _ ...payloadcms.RequestOption) (payloadcms.Response, error) {
 			return payloadcms.Response{}, nil
 		},
-		PutFunc: func(_ context.Context, _ string, _ any) (payloadcms.Response, error) {
+		PatchFunc: func(_ context.Context, _ string, _ any, _ ...payloadcms.RequestOption) (payloadcms.Response, error) {
 			return payloadcms.Response{}, nil
 		},
-		DeleteFunc: func(_ context.Context, _ string, _ any) (payloadcms.Response, error) {
+		DeleteFunc: func(_ context.Context, _ string, _ any, _ ...payloadcms.RequestOption) (payloadcms.Response, error) {
 			return payloadcms.Response{}, nil
 		},
 	}
 }
 
 // Do calls the mock implementation.
-func (m *MockService) Do(ctx context.Context, method, path string, body any, v any) (payloadcms.Response, error) {
-	return m.DoFunc(ctx, method, path, body, v)
+func (m *MockService) Do(ctx context.Context, method, path string, body any, v any, opts ...payloadcms.RequestOption) (payloadcms.Response, error) {
+	m.record("Do", opts, method, path, body, v)
+	return m.DoFunc(ctx, method, path, body, v, opts...)
 }
 
 // DoWithRequest calls the mock implementation.
-func (m *MockService) DoWithRequest(ctx context.Context, req *http.Request, v any) (payloadcms.Response, error) {
-	return m.DoWithRequestFunc(ctx, req, v)
+func (m *MockService) DoWithRequest(ctx context.Context, req *http.Request, v any, opts ...payloadcms.RequestOption) (payloadcms.Response, error) {
+	m.record("DoWithRequest", opts, req, v)
+	return m.DoWithRequestFunc(ctx, req, v, opts...)
 }
 
 // Get calls the mock implementation.
-func (m *MockService) Get(ctx context.Context, path string, v any) (payloadcms.Response, error) {
-	return m.GetFunc(ctx, path, v)
+func (m *MockService) Get(ctx context.Context, path string, v any, opts ...payloadcms.RequestOption) (payloadcms.Response, error) {
+	m.record("Get", opts, path, v)
+	return m.GetFunc(ctx, path, v, opts...)
 }
 
 // Post calls the mock implementation.
-func (m *MockService) Post(ctx context.Context, path string, in any) (payloadcms.Response, error) {
-	return m.PostFunc(ctx, path, in)
+func (m *MockService) Post(ctx context.Context, path string, in any, opts ...payloadcms.RequestOption) (payloadcms.Response, error) {
+	m.record("Post", opts, path, in)
+	return m.PostFunc(ctx, path, in, opts...)
 }
 
 // Put calls the mock implementation.
-func (m *MockService) Put(ctx context.Context, path string, in any) (payloadcms.Response, error) {
-	return m.PutFunc(ctx, path, in)
+func (m *MockService) Put(ctx context.Context, path string, in any, opts ...payloadcms.RequestOption) (payloadcms.Response, error) {
+	m.record("Put", opts, path, in)
+	return m.PutFunc(ctx, path, in, opts...)
+}
+
+// Patch calls the mock implementation.
+func (m *MockService) Patch(ctx context.Context, path string, in any, opts ...payloadcms.RequestOption) (payloadcms.Response, error) {
+	m.record("Patch", opts, path, in)
+	return m.PatchFunc(ctx, path, in, opts...)
 }
 
 // Delete calls the mock implementation.
-func (m *MockService) Delete(ctx context.Context, path string, v any) (payloadcms.Response, error) {
-	return m.DeleteFunc(ctx, path, v)
+func (m *MockService) Delete(ctx context.Context, path string, v any, opts ...payloadcms.RequestOption) (payloadcms.Response, error) {
+	m.record("Delete", opts, path, v)
+	return m.DeleteFunc(ctx, path, v, opts...)
 }
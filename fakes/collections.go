@@ -8,6 +8,8 @@ import (
 
 // MockCollectionService is a mock implementation of the CollectionService interface.
 type MockCollectionService struct {
+	Recorder
+
 	FindByIDFunc   func(ctx context.Context, collection payloadcms.Collection, id any, out any, opts ...payloadcms.RequestOption) (payloadcms.Response, error)
 	FindBySlugFunc func(ctx context.Context, collection payloadcms.Collection, slug string, out any, opts ...payloadcms.RequestOption) (payloadcms.Response, error)
 	ListFunc       func(ctx context.Context, collection payloadcms.Collection, params payloadcms.ListParams, out any, opts ...payloadcms.RequestOption) (payloadcms.Response, error)
@@ -16,6 +18,9 @@ type MockCollectionService struct {
 	DeleteByIDFunc func(ctx context.Context, collection payloadcms.Collection, id any, opts ...payloadcms.RequestOption) (payloadcms.Response, error)
 }
 
+// Ensure MockCollectionService implements the CollectionService interface.
+var _ payloadcms.CollectionService = (*MockCollectionService)(nil)
+
 // NewMockCollectionService creates a new fake collections stub.
 func NewMockCollectionService() *MockCollectionService {
 	return &MockCollectionService{
@@ -42,30 +47,36 @@ func NewMockCollectionService() *MockCollectionService {
 
 // FindByID calls the mock implementation.
 func (m *MockCollectionService) FindByID(ctx context.Context, collection payloadcms.Collection, id any, out any, opts ...payloadcms.RequestOption) (payloadcms.Response, error) {
+	m.record("FindByID", opts, collection, id, out)
 	return m.FindByIDFunc(ctx, collection, id, out, opts...)
 }
 
 // FindBySlug calls the mock implementation.
 func (m *MockCollectionService) FindBySlug(ctx context.Context, collection payloadcms.Collection, slug string, out any, opts ...payloadcms.RequestOption) (payloadcms.Response, error) {
+	m.record("FindBySlug", opts, collection, slug, out)
 	return m.FindBySlugFunc(ctx, collection, slug, out, opts...)
 }
 
 // List calls the mock implementation.
 func (m *MockCollectionService) List(ctx context.Context, collection payloadcms.Collection, params payloadcms.ListParams, out any, opts ...payloadcms.RequestOption) (payloadcms.Response, error) {
+	m.record("List", opts, collection, params, out)
 	return m.ListFunc(ctx, collection, params, out, opts...)
 }
 
 // Create calls the mock implementation.
 func (m *MockCollectionService) Create(ctx context.Context, collection payloadcms.Collection, in any, opts ...payloadcms.RequestOption) (payloadcms.Response, error) {
+	m.record("Create", opts, collection, in)
 	return m.CreateFunc(ctx, collection, in, opts...)
 }
 
 // UpdateByID calls the mock implementation.
 func (m *MockCollectionService) UpdateByID(ctx context.Context, collection payloadcms.Collection, id any, in any, opts ...payloadcms.RequestOption) (payloadcms.Response, error) {
+	m.record("UpdateByID", opts, collection, id, in)
 	return m.UpdateByIDFunc(ctx, collection, id, in, opts...)
 }
 
 // DeleteByID calls the mock implementation.
 func (m *MockCollectionService) DeleteByID(ctx context.Context, collection payloadcms.Collection, id any, opts ...payloadcms.RequestOption) (payloadcms.Response, error) {
+	m.record("DeleteByID", opts, collection, id)
 	return m.DeleteByIDFunc(ctx, collection, id, opts...)
 }
@@ -24,6 +24,13 @@ and a nil error.
 
 This allows you to fully control and assert the behavior of your code when interacting with the Payload CMS API.
 
+## Recording
+
+Every mock embeds a Recorder, which records each call made to it along with its
+arguments and the query parameters set by its request options, such as depth=2 for
+payloadcms.WithDepth(2). The calls can be inspected with Calls and CallsTo, or asserted
+on with AssertCalled, AssertNotCalled, AssertNumberOfCalls and AssertQuery.
+
 ## Example
 
 func TestPayload(t *testing.T) {
@@ -34,8 +41,9 @@ func TestPayload(t *testing.T) {
 	// Define the behavior of the FindByID method
 	mockCollectionService.FindByIDFunc = func(ctx context.Context,
 		collection payloadcms.Collection,
-		id int,
+		id any,
 		out any,
+		opts ...payloadcms.RequestOption,
 	) (payloadcms.Response, error) {
 	    // Custom logic for the mock implementation
 	    return payloadcms.Response{}, nil
@@ -44,6 +52,10 @@ func TestPayload(t *testing.T) {
 	// Use the mock collection service in your tests
 	myFunctionUsingCollectionService(mockCollectionService)
 
+	// Assert on the calls that were made
+	mockCollectionService.AssertCalled(t, "FindByID", payloadcms.Collection("posts"), 1)
+	mockCollectionService.AssertQuery(t, "FindByID", "depth", "2")
+
 }
 */
 package payloadfakes
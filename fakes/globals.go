@@ -8,10 +8,15 @@ import (
 
 // MockGlobalsService is a mock implementation of the GlobalsService interface.
 type MockGlobalsService struct {
+	Recorder
+
 	GetFunc    func(ctx context.Context, global payloadcms.Global, in any, opts ...payloadcms.RequestOption) (payloadcms.Response, error)
 	UpdateFunc func(ctx context.Context, global payloadcms.Global, in any, opts ...payloadcms.RequestOption) (payloadcms.Response, error)
 }
 
+// Ensure MockGlobalsService implements the GlobalsService interface.
+var _ payloadcms.GlobalsService = (*MockGlobalsService)(nil)
+
 // NewMockGlobalsService creates a new fake globals stub.
 func NewMockGlobalsService() *MockGlobalsService {
 	return &MockGlobalsService{
@@ -26,10 +31,12 @@ func NewMockGlobalsService() *MockGlobalsService {
 
 // Get calls the mock implementation.
 func (m *MockGlobalsService) Get(ctx context.Context, global payloadcms.Global, in any, opts ...payloadcms.RequestOption) (payloadcms.Response, error) {
+	m.record("Get", opts, global, in)
 	return m.GetFunc(ctx, global, in, opts...)
 }
 
 // Update calls the mock implementation.
 func (m *MockGlobalsService) Update(ctx context.Context, global payloadcms.Global, in any, opts ...payloadcms.RequestOption) (payloadcms.Response, error) {
+	m.record("Update", opts, global, in)
 	return m.UpdateFunc(ctx, global, in, opts...)
 }
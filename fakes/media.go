@@ -2,35 +2,84 @@ package payloadfakes
 
 import (
 	"context"
-	"os"
+	"io"
+	"io/fs"
+	"strings"
 
 	"github.com/ainsleyclark/go-payloadcms"
 )
 
 // MockMediaService is a mock implementation of the MediaService interface.
 type MockMediaService struct {
-	UploadFunc        func(ctx context.Context, f *os.File, in, out any, opts payloadcms.MediaOptions) (payloadcms.Response, error)
+	Recorder
+
+	UploadFunc        func(ctx context.Context, r io.Reader, in, out any, opts payloadcms.MediaOptions) (payloadcms.Response, error)
+	UploadFileFunc    func(ctx context.Context, name string, in, out any, opts payloadcms.MediaOptions) (payloadcms.Response, error)
+	UploadFSFunc      func(ctx context.Context, fsys fs.FS, name string, in, out any, opts payloadcms.MediaOptions) (payloadcms.Response, error)
 	UploadFromURLFunc func(ctx context.Context, url string, in, out any, opts payloadcms.MediaOptions) (payloadcms.Response, error)
+	ReplaceFunc       func(ctx context.Context, id any, r io.Reader, in, out any, opts payloadcms.MediaOptions) (payloadcms.Response, error)
+	DownloadFunc      func(ctx context.Context, collection payloadcms.Collection, id any, size string) (io.ReadCloser, error)
 }
 
+// Ensure MockMediaService implements the MediaService interface.
+var _ payloadcms.MediaService = (*MockMediaService)(nil)
+
 // NewMockMediaService creates a new fake media service stub.
 func NewMockMediaService() *MockMediaService {
 	return &MockMediaService{
-		UploadFunc: func(_ context.Context, _ *os.File, _ any, _ any, _ payloadcms.MediaOptions) (payloadcms.Response, error) {
+		UploadFunc: func(_ context.Context, _ io.Reader, _ any, _ any, _ payloadcms.MediaOptions) (payloadcms.Response, error) {
+			return payloadcms.Response{}, nil
+		},
+		UploadFileFunc: func(_ context.Context, _ string, _ any, _ any, _ payloadcms.MediaOptions) (payloadcms.Response, error) {
+			return payloadcms.Response{}, nil
+		},
+		UploadFSFunc: func(_ context.Context, _ fs.FS, _ string, _ any, _ any, _ payloadcms.MediaOptions) (payloadcms.Response, error) {
 			return payloadcms.Response{}, nil
 		},
 		UploadFromURLFunc: func(_ context.Context, _ string, _ any, _ any, _ payloadcms.MediaOptions) (payloadcms.Response, error) {
 			return payloadcms.Response{}, nil
 		},
+		ReplaceFunc: func(_ context.Context, _ any, _ io.Reader, _ any, _ any, _ payloadcms.MediaOptions) (payloadcms.Response, error) {
+			return payloadcms.Response{}, nil
+		},
+		DownloadFunc: func(_ context.Context, _ payloadcms.Collection, _ any, _ string) (io.ReadCloser, error) {
+			return io.NopCloser(strings.NewReader("")), nil
+		},
 	}
 }
 
 // Upload calls the mock implementation.
-func (m *MockMediaService) Upload(ctx context.Context, f *os.File, in, out any, opts payloadcms.MediaOptions) (payloadcms.Response, error) {
-	return m.UploadFunc(ctx, f, in, out, opts)
+func (m *MockMediaService) Upload(ctx context.Context, r io.Reader, in, out any, opts payloadcms.MediaOptions) (payloadcms.Response, error) {
+	m.record("Upload", nil, r, in, out, opts)
+	return m.UploadFunc(ctx, r, in, out, opts)
+}
+
+// UploadFile calls the mock implementation.
+func (m *MockMediaService) UploadFile(ctx context.Context, name string, in, out any, opts payloadcms.MediaOptions) (payloadcms.Response, error) {
+	m.record("UploadFile", nil, name, in, out, opts)
+	return m.UploadFileFunc(ctx, name, in, out, opts)
+}
+
+// UploadFS calls the mock implementation.
+func (m *MockMediaService) UploadFS(ctx context.Context, fsys fs.FS, name string, in, out any, opts payloadcms.MediaOptions) (payloadcms.Response, error) {
+	m.record("UploadFS", nil, fsys, name, in, out, opts)
+	return m.UploadFSFunc(ctx, fsys, name, in, out, opts)
 }
 
 // UploadFromURL calls the mock implementation.
 func (m *MockMediaService) UploadFromURL(ctx context.Context, url string, in, out any, opts payloadcms.MediaOptions) (payloadcms.Response, error) {
+	m.record("UploadFromURL", nil, url, in, out, opts)
 	return m.UploadFromURLFunc(ctx, url, in, out, opts)
 }
+
+// Replace calls the mock implementation.
+func (m *MockMediaService) Replace(ctx context.Context, id any, r io.Reader, in, out any, opts payloadcms.MediaOptions) (payloadcms.Response, error) {
+	m.record("Replace", nil, id, r, in, out, opts)
+	return m.ReplaceFunc(ctx, id, r, in, out, opts)
+}
+
+// Download calls the mock implementation.
+func (m *MockMediaService) Download(ctx context.Context, collection payloadcms.Collection, id any, size string) (io.ReadCloser, error) {
+	m.record("Download", nil, collection, id, size)
+	return m.DownloadFunc(ctx, collection, id, size)
+}
@@ -0,0 +1,192 @@
+package payloadfakes
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/ainsleyclark/go-payloadcms"
+)
+
+// Call is a call made to a mock.
+type Call struct {
+	// Method is the name of the method that was called, such as "FindByID".
+	Method string
+	// Args are the arguments of the call, excluding the context and the
+	// request options.
+	Args []any
+	// Query is the query string the request options of the call set,
+	// such as depth=2 for payloadcms.WithDepth(2).
+	Query url.Values
+}
+
+// Anything matches any argument in AssertCalled.
+const Anything = "payloadfakes.Anything"
+
+// TestingT is the subset of testing.TB used by the assertions.
+type TestingT interface {
+	Helper()
+	Errorf(format string, args ...any)
+}
+
+// Recorder records the calls made to a mock, it's embedded in every mock
+// so the calls can be inspected and asserted on. It's safe for
+// concurrent use.
+type Recorder struct {
+	mu    sync.Mutex
+	calls []Call
+}
+
+// record adds a call to the log, applying the request options to find
+// the query they set.
+func (r *Recorder) record(method string, opts []payloadcms.RequestOption, args ...any) {
+	req := &http.Request{URL: &url.URL{}, Header: http.Header{}}
+	for _, opt := range opts {
+		opt(req)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, Call{
+		Method: method,
+		Args:   args,
+		Query:  req.URL.Query(),
+	})
+}
+
+// Calls returns the calls made to the mock, in the order they were made.
+func (r *Recorder) Calls() []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.calls)
+}
+
+// CallsTo returns the calls made to the method, in the order they were
+// made.
+func (r *Recorder) CallsTo(method string) []Call {
+	var calls []Call
+	for _, c := range r.Calls() {
+		if c.Method == method {
+			calls = append(calls, c)
+		}
+	}
+	return calls
+}
+
+// Reset clears the recorded calls.
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = nil
+}
+
+// AssertCalled asserts that the method was called with arguments that
+// start with args, where Anything matches any argument. With no args, it
+// asserts that the method was called at all.
+//
+// Example:
+//
+//	mock.AssertCalled(t, "FindByID", payloadcms.Collection("posts"), 1)
+func (r *Recorder) AssertCalled(t TestingT, method string, args ...any) bool {
+	t.Helper()
+
+	calls := r.CallsTo(method)
+	for _, c := range calls {
+		if c.matches(args) {
+			return true
+		}
+	}
+
+	if len(calls) == 0 {
+		t.Errorf("expected %s to be called, but it wasn't", method)
+		return false
+	}
+	t.Errorf("expected %s to be called with %s, but it was called with:\n%s", method, formatArgs(args), formatCalls(calls))
+	return false
+}
+
+// AssertNotCalled asserts that the method wasn't called.
+func (r *Recorder) AssertNotCalled(t TestingT, method string) bool {
+	t.Helper()
+
+	if calls := r.CallsTo(method); len(calls) > 0 {
+		t.Errorf("expected %s not to be called, but it was called with:\n%s", method, formatCalls(calls))
+		return false
+	}
+	return true
+}
+
+// AssertNumberOfCalls asserts that the method was called n times.
+func (r *Recorder) AssertNumberOfCalls(t TestingT, method string, n int) bool {
+	t.Helper()
+
+	if got := len(r.CallsTo(method)); got != n {
+		t.Errorf("expected %s to be called %d times, but it was called %d times", method, n, got)
+		return false
+	}
+	return true
+}
+
+// AssertQuery asserts that the method was called with request options
+// that set the query parameter to the value.
+//
+// Example:
+//
+//	mock.AssertQuery(t, "FindByID", "depth", "2")
+func (r *Recorder) AssertQuery(t TestingT, method, key, value string) bool {
+	t.Helper()
+
+	calls := r.CallsTo(method)
+	for _, c := range calls {
+		if slices.Contains(c.Query[key], value) {
+			return true
+		}
+	}
+
+	if len(calls) == 0 {
+		t.Errorf("expected %s to be called, but it wasn't", method)
+		return false
+	}
+	var queries []string
+	for _, c := range calls {
+		queries = append(queries, "\t"+c.Query.Encode())
+	}
+	t.Errorf("expected %s to be called with %s=%s, but it was called with:\n%s", method, key, value, strings.Join(queries, "\n"))
+	return false
+}
+
+// matches reports whether the arguments of the call start with args.
+func (c Call) matches(args []any) bool {
+	if len(args) > len(c.Args) {
+		return false
+	}
+	for i, want := range args {
+		if want == Anything {
+			continue
+		}
+		if !reflect.DeepEqual(want, c.Args[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func formatArgs(args []any) string {
+	parts := make([]string, len(args))
+	for i, a := range args {
+		parts[i] = fmt.Sprintf("%#v", a)
+	}
+	return "(" + strings.Join(parts, ", ") + ")"
+}
+
+func formatCalls(calls []Call) string {
+	lines := make([]string, len(calls))
+	for i, c := range calls {
+		lines[i] = "\t" + formatArgs(c.Args)
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,177 @@
+package payloadfakes
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ainsleyclark/go-payloadcms"
+)
+
+// recordingT records the errors reported by the assertions.
+type recordingT struct {
+	errors []string
+}
+
+func (r *recordingT) Helper() {}
+
+func (r *recordingT) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestRecorder_Calls(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	m := NewMockCollectionService()
+
+	var wg sync.WaitGroup
+	for i := range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _ = m.FindByID(ctx, "posts", i, nil, payloadcms.WithDepth(i))
+		}()
+	}
+	wg.Wait()
+	_, _ = m.List(ctx, "posts", payloadcms.ListParams{Limit: 5}, nil)
+
+	assert.Len(t, m.Calls(), 11)
+	assert.Len(t, m.CallsTo("FindByID"), 10)
+
+	list := m.CallsTo("List")
+	require.Len(t, list, 1)
+	assert.Equal(t, []any{payloadcms.Collection("posts"), payloadcms.ListParams{Limit: 5}, nil}, list[0].Args)
+	assert.Empty(t, list[0].Query)
+
+	m.Reset()
+	assert.Empty(t, m.Calls())
+}
+
+func TestRecorder_AssertCalled(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	m := NewMockCollectionService()
+	_, _ = m.FindByID(ctx, "posts", 1, nil, payloadcms.WithDepth(2), payloadcms.WithLocale("fr"))
+
+	tt := map[string]struct {
+		assert    func(rt *recordingT) bool
+		wantError string
+	}{
+		"Called": {
+			assert: func(rt *recordingT) bool { return m.AssertCalled(rt, "FindByID") },
+		},
+		"Called With Args": {
+			assert: func(rt *recordingT) bool { return m.AssertCalled(rt, "FindByID", payloadcms.Collection("posts"), 1) },
+		},
+		"Called With Anything": {
+			assert: func(rt *recordingT) bool { return m.AssertCalled(rt, "FindByID", Anything, 1, nil) },
+		},
+		"Not Called": {
+			assert:    func(rt *recordingT) bool { return m.AssertCalled(rt, "DeleteByID") },
+			wantError: "expected DeleteByID to be called, but it wasn't",
+		},
+		"Wrong Args": {
+			assert:    func(rt *recordingT) bool { return m.AssertCalled(rt, "FindByID", payloadcms.Collection("posts"), 2) },
+			wantError: "expected FindByID to be called with (\"posts\", 2), but it was called with:\n\t(\"posts\", 1, <nil>)",
+		},
+		"Too Many Args": {
+			assert: func(rt *recordingT) bool {
+				return m.AssertCalled(rt, "FindByID", Anything, Anything, Anything, Anything)
+			},
+			wantError: "expected FindByID to be called with",
+		},
+		"Not Called Passes": {
+			assert: func(rt *recordingT) bool { return m.AssertNotCalled(rt, "Create") },
+		},
+		"Not Called Fails": {
+			assert:    func(rt *recordingT) bool { return m.AssertNotCalled(rt, "FindByID") },
+			wantError: "expected FindByID not to be called",
+		},
+		"Number Of Calls": {
+			assert: func(rt *recordingT) bool { return m.AssertNumberOfCalls(rt, "FindByID", 1) },
+		},
+		"Wrong Number Of Calls": {
+			assert:    func(rt *recordingT) bool { return m.AssertNumberOfCalls(rt, "FindByID", 2) },
+			wantError: "expected FindByID to be called 2 times, but it was called 1 times",
+		},
+		"Query": {
+			assert: func(rt *recordingT) bool { return m.AssertQuery(rt, "FindByID", "depth", "2") },
+		},
+		"Query Locale": {
+			assert: func(rt *recordingT) bool { return m.AssertQuery(rt, "FindByID", "locale", "fr") },
+		},
+		"Wrong Query": {
+			assert:    func(rt *recordingT) bool { return m.AssertQuery(rt, "FindByID", "depth", "1") },
+			wantError: "expected FindByID to be called with depth=1, but it was called with:\n\tdepth=2&locale=fr",
+		},
+		"Query Not Called": {
+			assert:    func(rt *recordingT) bool { return m.AssertQuery(rt, "List", "depth", "1") },
+			wantError: "expected List to be called, but it wasn't",
+		},
+	}
+
+	for name, test := range tt {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			rt := &recordingT{}
+			ok := test.assert(rt)
+			if test.wantError == "" {
+				assert.True(t, ok)
+				assert.Empty(t, rt.errors)
+				return
+			}
+			assert.False(t, ok)
+			require.Len(t, rt.errors, 1)
+			assert.Contains(t, rt.errors[0], test.wantError)
+		})
+	}
+}
+
+func TestMocks_Record(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	t.Run("Service", func(t *testing.T) {
+		t.Parallel()
+
+		m := NewMockService()
+		_, _ = m.Patch(ctx, "/api/posts/1", map[string]any{"title": "New"}, payloadcms.WithDepth(0))
+		_, _ = m.Do(ctx, "GET", "/api/posts", nil, nil, payloadcms.WithQueryParam("draft", "true"))
+
+		m.AssertCalled(t, "Patch", "/api/posts/1", map[string]any{"title": "New"})
+		m.AssertQuery(t, "Patch", "depth", "0")
+		m.AssertCalled(t, "Do", "GET", "/api/posts")
+		m.AssertQuery(t, "Do", "draft", "true")
+	})
+
+	t.Run("Globals", func(t *testing.T) {
+		t.Parallel()
+
+		m := NewMockGlobalsService()
+		_, _ = m.Get(ctx, "settings", nil, payloadcms.WithLocale("de"))
+
+		m.AssertCalled(t, "Get", payloadcms.Global("settings"))
+		m.AssertQuery(t, "Get", "locale", "de")
+	})
+
+	t.Run("Media", func(t *testing.T) {
+		t.Parallel()
+
+		m := NewMockMediaService()
+		r := strings.NewReader("file")
+		opts := payloadcms.MediaOptions{FileName: "file"}
+		_, _ = m.Upload(ctx, r, nil, nil, opts)
+
+		m.AssertCalled(t, "Upload", r, nil, nil, opts)
+		m.AssertNotCalled(t, "Download")
+	})
+}
@@ -33,5 +33,9 @@ func (s GlobalsServiceOp) Get(ctx context.Context, global Global, in any, opts .
 // Update updates a global by its slug.
 func (s GlobalsServiceOp) Update(ctx context.Context, global Global, in any, opts ...RequestOption) (Response, error) {
 	path := fmt.Sprintf("/api/globals/%s", global)
-	return s.Client.Do(ctx, http.MethodPost, path, in, nil, opts...)
+	resp, err := s.Client.Do(ctx, http.MethodPost, path, in, nil, opts...)
+	if err == nil {
+		s.Client.cache.invalidateGlobal(global)
+	}
+	return resp, err
 }
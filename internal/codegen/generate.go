@@ -0,0 +1,678 @@
+package codegen
+
+import (
+	"errors"
+	"fmt"
+	"go/format"
+	"go/token"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Options configures the generated code.
+type Options struct {
+	// Package is the name of the package the code is generated into.
+	Package string
+}
+
+const (
+	payloadImport  = "github.com/ainsleyclark/go-payloadcms"
+	richtextImport = "github.com/ainsleyclark/go-payloadcms/richtext"
+)
+
+// Generate generates a Go file containing a struct for each collection
+// and global of the source, along with the types of their fields,
+// constants for their slugs and field names, and a Client with a typed
+// accessor for each of them. The output is gofmt'd and deterministic, so
+// the same source always generates the same code.
+func Generate(src *Source, opts Options) ([]byte, error) {
+	if !token.IsIdentifier(opts.Package) {
+		return nil, fmt.Errorf("invalid package name: %q", opts.Package)
+	}
+	if src == nil {
+		return nil, errors.New("source is nil")
+	}
+
+	g := &generator{
+		src:     src,
+		imports: make(map[string]bool),
+		taken:   make(map[string]bool),
+		shapes:  make(map[string]*Schema),
+		defs:    make(map[string]string),
+		docs:    make(map[string]string),
+	}
+
+	var slugs strings.Builder
+	collections := g.slugConsts(&slugs, src.Collections, "Collection", "collection")
+	globals := g.slugConsts(&slugs, src.Globals, "Global", "global")
+
+	isCollection := make(map[string]bool, len(src.Collections))
+	for _, e := range src.Collections {
+		isCollection[e.Definition] = true
+	}
+
+	for len(g.pending) > 0 {
+		key := g.pending[0]
+		g.pending = g.pending[1:]
+
+		idx := len(g.decls)
+		g.emitStruct(g.defs[key], src.Definitions[key], g.docs[key])
+		if isCollection[key] {
+			g.insertDecl(idx+1, g.fieldConsts(g.defs[key], src.Definitions[key]))
+		}
+	}
+
+	g.emitClient(collections, globals)
+
+	return g.file(opts.Package, slugs.String())
+}
+
+type generator struct {
+	src     *Source
+	imports map[string]bool
+	decls   []string
+	blocks  []string
+	// taken are the identifiers declared within the file.
+	taken map[string]bool
+	// shapes are the schemas of declared types, so that identical
+	// types such as a block used by more than one field are reused.
+	shapes map[string]*Schema
+	// defs are the type names of the definitions that have been
+	// referenced, which are declared in the order of pending.
+	defs    map[string]string
+	docs    map[string]string
+	pending []string
+}
+
+// slugConst is the constant declared for the slug of a collection or
+// global.
+type slugConst struct {
+	entity Entity
+	name   string
+}
+
+// goType is the Go type of a schema.
+type goType struct {
+	expr string
+	// pointer is true if the type is made a pointer when the field is
+	// optional or nullable.
+	pointer bool
+}
+
+// typeCtx describes the field a type is declared for, which is used to
+// name and document it.
+type typeCtx struct {
+	owner string
+	field string
+	row   bool
+}
+
+func (c typeCtx) name() string {
+	return c.owner + exportName(c.field)
+}
+
+func (g *generator) slugConsts(b *strings.Builder, entities []Entity, prefix, kind string) []slugConst {
+	if len(entities) == 0 {
+		return nil
+	}
+
+	consts := make([]slugConst, 0, len(entities))
+	fmt.Fprintf(b, "// %s slugs of the Payload config.\nconst (\n", prefix)
+	for _, e := range entities {
+		name, _ := g.claim(prefix+exportName(e.Slug), nil)
+		fmt.Fprintf(b, "\t%s payloadcms.%s = %q\n", name, prefix, e.Slug)
+		consts = append(consts, slugConst{entity: e, name: name})
+
+		g.docs[e.Definition] = fmt.Sprintf("defines the %s %s.", e.Slug, kind)
+		g.ref(e.Definition)
+	}
+	b.WriteString(")\n\n")
+	g.imports[payloadImport] = true
+
+	return consts
+}
+
+// claim declares an identifier, adding a numeric suffix when the name
+// is taken. A type with the same schema as an existing type of that
+// name reuses it, in which case isNew is false.
+func (g *generator) claim(name string, s *Schema) (string, bool) {
+	if existing, ok := g.shapes[name]; ok && s != nil && reflect.DeepEqual(existing, s) {
+		return name, false
+	}
+
+	candidate := name
+	for i := 2; g.taken[candidate]; i++ {
+		candidate = name + strconv.Itoa(i)
+	}
+	g.taken[candidate] = true
+	if s != nil {
+		g.shapes[candidate] = s
+	}
+
+	return candidate, true
+}
+
+// ref returns the type name of a definition, queuing it to be declared
+// the first time it's referenced.
+func (g *generator) ref(key string) (string, bool) {
+	s, ok := g.src.Definitions[key]
+	if !ok {
+		return "", false
+	}
+	if name, ok := g.defs[key]; ok {
+		return name, true
+	}
+
+	name := s.Title
+	if name == "" {
+		name = key
+	}
+	name, isNew := g.claim(exportName(name), s)
+	g.defs[key] = name
+	if isNew {
+		g.pending = append(g.pending, key)
+	}
+
+	return name, true
+}
+
+// declare reserves the position of a declaration, so that the types of
+// its fields are declared after it.
+func (g *generator) declare() int {
+	g.decls = append(g.decls, "")
+	return len(g.decls) - 1
+}
+
+// insertDecl inserts a declaration at the index.
+func (g *generator) insertDecl(idx int, decl string) {
+	if decl == "" {
+		return
+	}
+	g.decls = append(g.decls[:idx], append([]string{decl}, g.decls[idx:]...)...)
+}
+
+// fieldConsts declares a constant for the name of each field of a
+// collection, including the fields of groups and arrays using dot
+// notation, so that field names used within queries are checked at
+// compile time.
+func (g *generator) fieldConsts(name string, s *Schema) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "// Field names of %s, for use with payloadcms.QueryBuilder\n// and payloadcms.ListParams.Sort.\nconst (\n", name)
+
+	var walk func(s *Schema, path []string)
+	walk = func(s *Schema, path []string) {
+		for _, p := range s.Properties {
+			fieldPath := append(append([]string(nil), path...), p.Name)
+
+			var words strings.Builder
+			for _, part := range fieldPath {
+				words.WriteString(exportName(part))
+			}
+			constName, _ := g.claim(name+"Field"+words.String(), nil)
+			fmt.Fprintf(&b, "\t%s = %q\n", constName, strings.Join(fieldPath, "."))
+
+			if nested := g.nestedFields(p.Schema); nested != nil {
+				walk(nested, fieldPath)
+			}
+		}
+	}
+	walk(s, nil)
+	b.WriteString(")\n")
+
+	return b.String()
+}
+
+// nestedFields returns the object schema of a group or array field,
+// whose fields can be queried with dot notation, or nil if the field
+// has no nested fields.
+func (g *generator) nestedFields(s *Schema) *Schema {
+	if s.Ref != "" || len(s.variants()) > 0 {
+		return nil
+	}
+	types := s.Type.nonNull()
+	if len(types) != 1 {
+		return nil
+	}
+
+	switch types[0] {
+	case "array":
+		if s.Items == nil || len(g.blockSchemas(s.Items)) > 0 {
+			return nil
+		}
+		return g.nestedFields(s.Items)
+	case "object":
+		if len(s.Properties) == 0 || isPoly(s) || s.Properties.Get("root") != nil {
+			return nil
+		}
+		return s
+	}
+
+	return nil
+}
+
+// emitClient declares a client with an accessor for each collection
+// and global that returns a TypedCollection or TypedGlobal.
+func (g *generator) emitClient(collections, globals []slugConst) {
+	if len(collections) == 0 && len(globals) == 0 {
+		return
+	}
+
+	name, _ := g.claim("Client", nil)
+	ctor, _ := g.claim("New"+name, nil)
+	methods := map[string]bool{"Payload": true}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "// %s provides typed access to the collections and globals of\n// the Payload config.\n", name)
+	fmt.Fprintf(&b, "type %s struct {\n\tclient *payloadcms.Client\n}\n\n", name)
+	fmt.Fprintf(&b, "// %s creates a %s that sends requests with c.\n", ctor, name)
+	fmt.Fprintf(&b, "func %s(c *payloadcms.Client) *%s {\n\treturn &%s{client: c}\n}\n\n", ctor, name, name)
+	fmt.Fprintf(&b, "// Payload returns the underlying client.\n")
+	fmt.Fprintf(&b, "func (c *%s) Payload() *payloadcms.Client {\n\treturn c.client\n}\n", name)
+
+	accessor := func(sc slugConst, kind, wrapper, service string) {
+		method := exportName(sc.entity.Slug)
+		for i := 2; methods[method]; i++ {
+			method = exportName(sc.entity.Slug) + strconv.Itoa(i)
+		}
+		methods[method] = true
+
+		typ := g.defs[sc.entity.Definition]
+		fmt.Fprintf(&b, "\n// %s returns a typed client for the %s %s.\n", method, sc.entity.Slug, kind)
+		fmt.Fprintf(&b, "func (c *%s) %s() payloadcms.%s[%s] {\n", name, method, wrapper, typ)
+		fmt.Fprintf(&b, "\treturn payloadcms.New%s[%s](c.client.%s, %s)\n}\n", wrapper, typ, service, sc.name)
+	}
+	for _, sc := range collections {
+		accessor(sc, "collection", "TypedCollection", "Collections")
+	}
+	for _, sc := range globals {
+		accessor(sc, "global", "TypedGlobal", "Globals")
+	}
+
+	g.decls = append(g.decls, b.String())
+}
+
+func (g *generator) emitStruct(name string, s *Schema, doc string) {
+	idx := g.declare()
+
+	var b strings.Builder
+	writeComment(&b, name+" "+doc, s.Description)
+
+	blockType, isBlock := blockTypeOf(s)
+	required := make(map[string]bool, len(s.Required))
+	for _, r := range s.Required {
+		required[r] = true
+	}
+	fields := make(map[string]bool, len(s.Properties))
+
+	fmt.Fprintf(&b, "type %s struct {\n", name)
+	for _, p := range s.Properties {
+		if isBlock && p.Name == "blockType" {
+			continue
+		}
+
+		field := exportName(p.Name)
+		for i := 2; fields[field]; i++ {
+			field = exportName(p.Name) + strconv.Itoa(i)
+		}
+		fields[field] = true
+
+		t := g.goType(p.Schema, typeCtx{owner: name, field: p.Name})
+		if p.Name == "id" && t.expr == "float64" {
+			t.expr = "int"
+		}
+
+		// Fields set by Payload are omitted when empty, so that the
+		// struct can be used to create documents.
+		system := p.Name == "id" || p.Name == "createdAt" || p.Name == "updatedAt"
+		omitEmpty := !required[p.Name] || system
+		optional := !required[p.Name] || p.Schema.nullable()
+		if system && (t.expr == "int" || t.expr == "string") {
+			optional = false
+		}
+		if t.pointer && optional {
+			t.expr = "*" + t.expr
+		}
+
+		tag := p.Name
+		if omitEmpty {
+			tag += ",omitempty"
+		}
+
+		writeComment(&b, "", p.Schema.Description)
+		fmt.Fprintf(&b, "\t%s %s `json:%q`\n", field, t.expr, tag)
+	}
+	b.WriteString("}\n")
+
+	if isBlock {
+		fmt.Fprintf(&b, "\n// BlockType implements payloadcms.Block.\nfunc (%s) BlockType() string {\n\treturn %q\n}\n", name, blockType)
+		g.blocks = append(g.blocks, name)
+		g.imports[payloadImport] = true
+	}
+
+	g.decls[idx] = b.String()
+}
+
+func (g *generator) emitEnum(name string, values []string, doc string) {
+	idx := g.declare()
+
+	var b strings.Builder
+	writeComment(&b, name+" "+doc, "")
+	fmt.Fprintf(&b, "type %s string\n\n// Values of %s.\nconst (\n", name, name)
+	for _, v := range values {
+		suffix := exportName(v)
+		if v == "" {
+			suffix = "Empty"
+		}
+		constName, _ := g.claim(name+suffix, nil)
+		fmt.Fprintf(&b, "\t%s %s = %q\n", constName, name, v)
+	}
+	b.WriteString(")\n")
+
+	g.decls[idx] = b.String()
+}
+
+func (g *generator) goType(s *Schema, ctx typeCtx) goType {
+	if s.Ref != "" {
+		if name, ok := g.ref(refName(s.Ref)); ok {
+			return goType{expr: name, pointer: true}
+		}
+		return g.raw()
+	}
+	if len(s.AllOf) == 1 {
+		return g.goType(s.AllOf[0], ctx)
+	}
+	if len(s.variants()) > 0 {
+		return g.variantType(s, ctx)
+	}
+	if values, ok := enumValues(s); ok {
+		return g.enumType(s, values, ctx)
+	}
+
+	types := s.Type.nonNull()
+	if len(types) != 1 {
+		return g.raw()
+	}
+
+	switch types[0] {
+	case "string":
+		if s.Format == "date-time" {
+			g.imports["time"] = true
+			return goType{expr: "time.Time", pointer: true}
+		}
+		return goType{expr: "string", pointer: true}
+	case "number":
+		return goType{expr: "float64", pointer: true}
+	case "integer":
+		return goType{expr: "int", pointer: true}
+	case "boolean":
+		return goType{expr: "bool", pointer: true}
+	case "object":
+		return g.objectType(s, ctx)
+	case "array":
+		return g.arrayType(s, ctx)
+	}
+
+	return g.raw()
+}
+
+func (g *generator) raw() goType {
+	g.imports["encoding/json"] = true
+	return goType{expr: "json.RawMessage"}
+}
+
+// variantType returns the type of a oneOf or anyOf schema, which Payload
+// uses for relationship and upload fields.
+func (g *generator) variantType(s *Schema, ctx typeCtx) goType {
+	var variants []*Schema
+	for _, v := range s.variants() {
+		if !v.isNull() {
+			variants = append(variants, v)
+		}
+	}
+
+	if len(variants) == 1 {
+		return g.goType(variants[0], ctx)
+	}
+
+	// Relationships are either the ID or the referenced document.
+	if len(variants) == 2 {
+		for i, v := range variants {
+			other := variants[1-i]
+			if v.Ref == "" || other.Ref != "" || !isID(other) {
+				continue
+			}
+			if name, ok := g.ref(refName(v.Ref)); ok {
+				g.imports[payloadImport] = true
+				return goType{expr: "payloadcms.Relation[" + name + "]"}
+			}
+		}
+	}
+
+	poly := len(variants) > 0
+	for _, v := range variants {
+		poly = poly && isPoly(v)
+	}
+	if poly {
+		g.imports[payloadImport] = true
+		return goType{expr: "payloadcms.PolyRelation"}
+	}
+
+	return g.raw()
+}
+
+func (g *generator) objectType(s *Schema, ctx typeCtx) goType {
+	if root := s.Properties.Get("root"); root != nil && root.Properties.Get("children") != nil {
+		g.imports[richtextImport] = true
+		return goType{expr: "richtext.Lexical"}
+	}
+	if isPoly(s) {
+		g.imports[payloadImport] = true
+		return goType{expr: "payloadcms.PolyRelation"}
+	}
+	if len(s.Properties) == 0 {
+		return g.raw()
+	}
+
+	doc := fmt.Sprintf("is the %s field of %s.", ctx.field, ctx.owner)
+	if ctx.row {
+		doc = fmt.Sprintf("is a row of the %s field of %s.", ctx.field, ctx.owner)
+	}
+
+	name, isNew := g.claim(ctx.name(), s)
+	if isNew {
+		g.emitStruct(name, s, doc)
+	}
+
+	return goType{expr: name, pointer: true}
+}
+
+func (g *generator) arrayType(s *Schema, ctx typeCtx) goType {
+	items := s.Items
+	if items == nil {
+		g.raw()
+		return goType{expr: "[]json.RawMessage"}
+	}
+
+	if blocks := g.blockSchemas(items); len(blocks) > 0 {
+		for _, b := range blocks {
+			g.blockType(b)
+		}
+		g.imports[payloadImport] = true
+		return goType{expr: "payloadcms.Blocks"}
+	}
+
+	// Slate stores rich text as an array of untyped nodes.
+	if items.Ref == "" && len(items.variants()) == 0 && len(items.Properties) == 0 &&
+		len(items.Type.nonNull()) == 1 && items.Type.Has("object") {
+		g.imports[richtextImport] = true
+		return goType{expr: "richtext.Slate"}
+	}
+
+	ctx.row = true
+	return goType{expr: "[]" + g.goType(items, ctx).expr}
+}
+
+// blockType declares the type of a block, either from its definition or
+// named after its blockType.
+func (g *generator) blockType(s *Schema) {
+	if s.Ref != "" {
+		g.ref(refName(s.Ref))
+		return
+	}
+
+	blockType, _ := blockTypeOf(s)
+	name, isNew := g.claim(exportName(blockType)+"Block", s)
+	if isNew {
+		g.emitStruct(name, s, fmt.Sprintf("is the %s block.", blockType))
+	}
+}
+
+func (g *generator) enumType(s *Schema, values []string, ctx typeCtx) goType {
+	name, isNew := g.claim(ctx.name(), s)
+	if isNew {
+		g.emitEnum(name, values, fmt.Sprintf("is a value of the %s field of %s.", ctx.field, ctx.owner))
+	}
+	return goType{expr: name, pointer: true}
+}
+
+// blockSchemas returns the blocks an array of blocks can contain, or nil
+// if the items aren't blocks.
+func (g *generator) blockSchemas(items *Schema) []*Schema {
+	candidates := []*Schema{items}
+	if len(items.variants()) > 0 {
+		candidates = nil
+		for _, v := range items.variants() {
+			if !v.isNull() {
+				candidates = append(candidates, v)
+			}
+		}
+	}
+	for _, c := range candidates {
+		if _, ok := blockTypeOf(g.resolve(c)); !ok {
+			return nil
+		}
+	}
+	return candidates
+}
+
+// resolve returns the definition a schema references, or the schema
+// itself if it isn't a reference.
+func (g *generator) resolve(s *Schema) *Schema {
+	if s.Ref != "" {
+		if def, ok := g.src.Definitions[refName(s.Ref)]; ok {
+			return def
+		}
+	}
+	return s
+}
+
+func (g *generator) file(pkg, slugs string) ([]byte, error) {
+	var b strings.Builder
+	b.WriteString("// Code generated by payloadgen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&b, "package %s\n\n", pkg)
+
+	var std, third []string
+	for _, path := range []string{"encoding/json", "time", payloadImport, richtextImport} {
+		if !g.imports[path] {
+			continue
+		}
+		if strings.Contains(path, ".") {
+			third = append(third, path)
+		} else {
+			std = append(std, path)
+		}
+	}
+	if len(std)+len(third) > 0 {
+		b.WriteString("import (\n")
+		for _, path := range std {
+			fmt.Fprintf(&b, "\t%q\n", path)
+		}
+		if len(std) > 0 && len(third) > 0 {
+			b.WriteString("\n")
+		}
+		for _, path := range third {
+			fmt.Fprintf(&b, "\t%q\n", path)
+		}
+		b.WriteString(")\n\n")
+	}
+
+	b.WriteString(slugs)
+	for _, decl := range g.decls {
+		b.WriteString(decl)
+		b.WriteString("\n")
+	}
+
+	if len(g.blocks) > 0 {
+		// Blocks aren't registered in an init function, as types of
+		// another package with the same blockType would silently
+		// replace them depending on the import order.
+		b.WriteString("// RegisterBlocks registers the blocks with the registry, such as\n")
+		b.WriteString("// payloadcms.DefaultBlockRegistry.\n")
+		b.WriteString("func RegisterBlocks(r *payloadcms.BlockRegistry) {\n\tr.Register(\n")
+		for _, name := range g.blocks {
+			fmt.Fprintf(&b, "\t\t%s{},\n", name)
+		}
+		b.WriteString("\t)\n}\n")
+	}
+
+	out, err := format.Source([]byte(b.String()))
+	if err != nil {
+		return nil, fmt.Errorf("formatting generated code: %w", err)
+	}
+
+	return out, nil
+}
+
+// writeComment writes a doc comment made of the summary and the
+// description, separated by an empty comment line.
+func writeComment(b *strings.Builder, summary, description string) {
+	var lines []string
+	if summary != "" {
+		lines = append(lines, summary)
+	}
+	if description = strings.TrimSpace(description); description != "" {
+		if summary != "" {
+			lines = append(lines, "")
+		}
+		lines = append(lines, strings.Split(description, "\n")...)
+	}
+	for _, line := range lines {
+		b.WriteString(strings.TrimRight("// "+line, " ") + "\n")
+	}
+}
+
+// blockTypeOf returns the blockType of an object schema that is a block.
+func blockTypeOf(s *Schema) (string, bool) {
+	bt := s.Properties.Get("blockType")
+	if bt == nil {
+		return "", false
+	}
+	return bt.constString()
+}
+
+// isID reports whether the schema is the ID of a relationship.
+func isID(s *Schema) bool {
+	types := s.Type.nonNull()
+	return len(types) == 1 && (types[0] == "string" || types[0] == "number" || types[0] == "integer")
+}
+
+// isPoly reports whether the schema is a polymorphic relationship.
+func isPoly(s *Schema) bool {
+	return s.Properties.Get("relationTo") != nil && s.Properties.Get("value") != nil
+}
+
+// enumValues returns the values of a select or radio field.
+func enumValues(s *Schema) ([]string, bool) {
+	var values []string
+	for _, v := range s.Enum {
+		switch v := v.(type) {
+		case nil:
+		case string:
+			values = append(values, v)
+		default:
+			return nil, false
+		}
+	}
+	return values, len(values) > 0
+}
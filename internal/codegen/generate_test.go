@@ -0,0 +1,142 @@
+package codegen
+
+import (
+	"flag"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+func TestGenerate(t *testing.T) {
+	t.Parallel()
+
+	tt := map[string]struct {
+		input  string
+		parse  func([]byte) (*Source, error)
+		golden string
+	}{
+		"JSON Schema": {
+			input:  "testdata/schema.json",
+			parse:  ParseJSONSchema,
+			golden: "testdata/schema.golden",
+		},
+		"TypeScript": {
+			input:  "testdata/payload-types.ts",
+			parse:  ParseTypeScript,
+			golden: "testdata/payload-types.golden",
+		},
+	}
+
+	for name, test := range tt {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			buf, err := os.ReadFile(test.input)
+			require.NoError(t, err)
+
+			src, err := test.parse(buf)
+			require.NoError(t, err)
+
+			got, err := Generate(src, Options{Package: "models"})
+			require.NoError(t, err)
+
+			if *update {
+				require.NoError(t, os.WriteFile(test.golden, got, 0o644))
+			}
+
+			want, err := os.ReadFile(test.golden)
+			require.NoError(t, err)
+			assert.Equal(t, string(want), string(got))
+
+			// The output must not depend on map iteration order.
+			for i := 0; i < 10; i++ {
+				src, err := test.parse(buf)
+				require.NoError(t, err)
+				again, err := Generate(src, Options{Package: "models"})
+				require.NoError(t, err)
+				assert.Equal(t, string(got), string(again))
+			}
+		})
+	}
+}
+
+func TestGenerate_Naming(t *testing.T) {
+	t.Parallel()
+
+	src, err := ParseTypeScript([]byte(`
+		export interface Config {
+			collections: { pages: Page; 'page-links': PageLink; clients: Client };
+			globals: { pages: Page };
+		}
+		export interface Page {
+			id: string;
+			status: 'draft' | 'published';
+			state?: 'draft' | 'archived' | '';
+			links?: ({ url: string; blockType: 'link' } | { label: string; blockType: 'button' })[];
+			other?: { url: string; blockType: 'link' }[];
+			Status?: string;
+		}
+		export interface PageLink {
+			id: string;
+		}
+		export interface Client {
+			id: string;
+		}
+	`))
+	require.NoError(t, err)
+
+	got, err := Generate(src, Options{Package: "models"})
+	require.NoError(t, err)
+
+	for _, want := range []string{
+		"CollectionPageLinks payloadcms.Collection = \"page-links\"",
+		"Status2 *string",
+		"PageStateEmpty    PageState = \"\"",
+		"type LinkBlock struct",
+		"type ButtonBlock struct",
+		"func (ButtonBlock) BlockType() string",
+		"Other   payloadcms.Blocks",
+		"PageFieldStatus2 = \"Status\"",
+		"type Client struct",
+		"type Client2 struct",
+		"func NewClient2(c *payloadcms.Client) *Client2",
+		"func (c *Client2) Clients() payloadcms.TypedCollection[Client]",
+		"func (c *Client2) Pages2() payloadcms.TypedGlobal[Page]",
+	} {
+		assert.Contains(t, string(got), want)
+	}
+	assert.Equal(t, 1, strings.Count(string(got), "type LinkBlock struct"), "identical blocks are declared once")
+}
+
+func TestGenerate_Errors(t *testing.T) {
+	t.Parallel()
+
+	src := &Source{}
+	_, err := Generate(src, Options{Package: "not-valid"})
+	assert.Error(t, err)
+
+	_, err = Generate(nil, Options{Package: "models"})
+	assert.Error(t, err)
+}
+
+func TestParseJSONSchema_Errors(t *testing.T) {
+	t.Parallel()
+
+	for name, input := range map[string]string{
+		"Invalid JSON":       `{`,
+		"Bad Properties":     `{"properties": []}`,
+		"Bad Type":           `{"type": 1}`,
+		"Bad Items":          `{"items": [1]}`,
+		"No Entities":        `{"properties": {}}`,
+		"Not A Reference":    `{"properties": {"collections": {"properties": {"posts": {"type": "object"}}}}}`,
+		"Missing Definition": `{"properties": {"globals": {"properties": {"settings": {"$ref": "#/definitions/settings"}}}}}`,
+	} {
+		_, err := ParseJSONSchema([]byte(input))
+		assert.Error(t, err, name)
+	}
+}
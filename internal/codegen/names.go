@@ -0,0 +1,75 @@
+package codegen
+
+import (
+	"strings"
+	"unicode"
+)
+
+// initialisms are the words that are kept in upper case within Go
+// identifiers, as recommended by the Go code review comments.
+var initialisms = map[string]bool{
+	"ACL": true, "API": true, "ASCII": true, "CPU": true, "CSS": true,
+	"DNS": true, "EOF": true, "GUID": true, "HTML": true, "HTTP": true,
+	"HTTPS": true, "ID": true, "IP": true, "JSON": true, "QPS": true,
+	"RAM": true, "RPC": true, "SLA": true, "SMTP": true, "SQL": true,
+	"SSH": true, "TCP": true, "TLS": true, "TTL": true, "UDP": true,
+	"UI": true, "UID": true, "UUID": true, "URI": true, "URL": true,
+	"UTF8": true, "VM": true, "XML": true, "XSRF": true, "XSS": true,
+}
+
+// exportName converts a slug or field name into an exported Go
+// identifier, for example "siteName" returns "SiteName", "_status"
+// returns "Status" and "apiKey" returns "APIKey".
+func exportName(s string) string {
+	var b strings.Builder
+	for _, word := range splitWords(s) {
+		if upper := strings.ToUpper(word); initialisms[upper] {
+			b.WriteString(upper)
+			continue
+		}
+		r := []rune(word)
+		b.WriteRune(unicode.ToUpper(r[0]))
+		b.WriteString(string(r[1:]))
+	}
+
+	name := b.String()
+	if name == "" || unicode.IsDigit([]rune(name)[0]) {
+		name = "X" + name
+	}
+	return name
+}
+
+// splitWords splits s into words on any character that isn't a letter
+// or digit, and on changes of case, keeping runs of upper case letters
+// such as "API" in "enableAPIKey" together.
+func splitWords(s string) []string {
+	var (
+		words []string
+		word  []rune
+	)
+	flush := func() {
+		if len(word) > 0 {
+			words = append(words, string(word))
+			word = nil
+		}
+	}
+
+	r := []rune(s)
+	for i, c := range r {
+		if !unicode.IsLetter(c) && !unicode.IsDigit(c) {
+			flush()
+			continue
+		}
+		if unicode.IsUpper(c) && len(word) > 0 {
+			prev := word[len(word)-1]
+			nextLower := i+1 < len(r) && unicode.IsLower(r[i+1])
+			if !unicode.IsUpper(prev) || nextLower {
+				flush()
+			}
+		}
+		word = append(word, c)
+	}
+	flush()
+
+	return words
+}
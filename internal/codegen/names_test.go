@@ -0,0 +1,30 @@
+package codegen
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExportName(t *testing.T) {
+	t.Parallel()
+
+	tt := map[string]string{
+		"posts":               "Posts",
+		"siteName":            "SiteName",
+		"_status":             "Status",
+		"apiKey":              "APIKey",
+		"enableAPIKey":        "EnableAPIKey",
+		"payload-preferences": "PayloadPreferences",
+		"how-to":              "HowTo",
+		"h1":                  "H1",
+		"url":                 "URL",
+		"userId":              "UserID",
+		"2fa":                 "X2fa",
+		"":                    "X",
+	}
+
+	for input, want := range tt {
+		assert.Equal(t, want, exportName(input), input)
+	}
+}
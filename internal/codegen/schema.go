@@ -0,0 +1,294 @@
+// Package codegen generates Go types from a Payload config, described
+// either by Payload's JSON schema or by the payload-types.ts file that
+// Payload generates from it.
+package codegen
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Schema is the subset of JSON Schema that Payload generates for a
+// config. The TypeScript parser also produces a Schema, so both inputs
+// share the same generator.
+type Schema struct {
+	Ref         string             `json:"$ref"`
+	Type        TypeList           `json:"type"`
+	Format      string             `json:"format"`
+	Title       string             `json:"title"`
+	Description string             `json:"description"`
+	Enum        []any              `json:"enum"`
+	Const       any                `json:"const"`
+	Properties  Properties         `json:"properties"`
+	Required    []string           `json:"required"`
+	Items       *Schema            `json:"-"`
+	OneOf       []*Schema          `json:"oneOf"`
+	AnyOf       []*Schema          `json:"anyOf"`
+	AllOf       []*Schema          `json:"allOf"`
+	Definitions map[string]*Schema `json:"definitions"`
+	Defs        map[string]*Schema `json:"$defs"` //nolint:tagliatelle
+}
+
+// TypeList is the type keyword of a schema, which is either a single
+// type or a list of types.
+type TypeList []string
+
+// Property is a single named property of an object schema.
+type Property struct {
+	Name   string
+	Schema *Schema
+}
+
+// Properties are the properties of an object schema, in the order they
+// are defined so that struct fields follow the Payload config.
+type Properties []Property
+
+// Source is a Payload config to generate types from.
+type Source struct {
+	// Collections are the collections of the config, sorted by slug.
+	Collections []Entity
+	// Globals are the globals of the config, sorted by slug.
+	Globals []Entity
+	// Definitions are the schemas that references point to.
+	Definitions map[string]*Schema
+}
+
+// Entity is a collection or global of the config.
+type Entity struct {
+	// Slug is the slug of the collection or global.
+	Slug string
+	// Definition is the key of its schema within Source.Definitions.
+	Definition string
+}
+
+// ParseJSONSchema decodes the JSON schema of a Payload config.
+//
+// See: https://payloadcms.com/docs/typescript/generating-types
+func ParseJSONSchema(data []byte) (*Source, error) {
+	var root Schema
+	if err := json.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("decoding json schema: %w", err)
+	}
+	return newSource(&root)
+}
+
+// newSource builds a Source from the root schema of a config, whose
+// collections and globals properties map each slug to a definition.
+func newSource(root *Schema) (*Source, error) {
+	src := &Source{Definitions: make(map[string]*Schema)}
+	for k, v := range root.Definitions {
+		src.Definitions[k] = v
+	}
+	for k, v := range root.Defs {
+		src.Definitions[k] = v
+	}
+
+	var err error
+	if src.Collections, err = entities(root, "collections"); err != nil {
+		return nil, err
+	}
+	if src.Globals, err = entities(root, "globals"); err != nil {
+		return nil, err
+	}
+	if len(src.Collections) == 0 && len(src.Globals) == 0 {
+		return nil, errors.New("no collections or globals found")
+	}
+
+	for _, e := range append(append([]Entity(nil), src.Collections...), src.Globals...) {
+		if _, ok := src.Definitions[e.Definition]; !ok {
+			return nil, fmt.Errorf("no definition found for %q", e.Slug)
+		}
+	}
+
+	return src, nil
+}
+
+func entities(root *Schema, key string) ([]Entity, error) {
+	s := root.Properties.Get(key)
+	if s == nil {
+		return nil, nil
+	}
+
+	var out []Entity
+	for _, p := range s.Properties {
+		if p.Schema.Ref == "" {
+			return nil, fmt.Errorf("%s.%s is not a reference to a definition", key, p.Name)
+		}
+		out = append(out, Entity{Slug: p.Name, Definition: refName(p.Schema.Ref)})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].Slug < out[j].Slug
+	})
+
+	return out, nil
+}
+
+// refName returns the definition key of a reference, for example
+// "#/definitions/posts" returns "posts".
+func refName(ref string) string {
+	return ref[strings.LastIndex(ref, "/")+1:]
+}
+
+// Get returns the schema of the named property, or nil if the property
+// doesn't exist.
+func (p Properties) Get(name string) *Schema {
+	for _, prop := range p {
+		if prop.Name == name {
+			return prop.Schema
+		}
+	}
+	return nil
+}
+
+// UnmarshalJSON decodes the properties, keeping the order they are
+// defined in.
+func (p *Properties) UnmarshalJSON(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if t, err := dec.Token(); err != nil {
+		return err
+	} else if t != json.Delim('{') {
+		return errors.New("properties must be an object")
+	}
+
+	*p = nil
+	for dec.More() {
+		t, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		name, _ := t.(string)
+
+		var s Schema
+		if err := dec.Decode(&s); err != nil {
+			return fmt.Errorf("decoding property %q: %w", name, err)
+		}
+		*p = append(*p, Property{Name: name, Schema: &s})
+	}
+
+	return nil
+}
+
+// UnmarshalJSON decodes either a single type or a list of types.
+func (t *TypeList) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*t = TypeList{single}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	*t = list
+	return nil
+}
+
+// UnmarshalJSON decodes the schema. Items may be a single schema or, for
+// tuples such as point fields, a list of schemas of which the first is
+// used.
+func (s *Schema) UnmarshalJSON(data []byte) error {
+	type schema Schema
+	var v struct {
+		schema
+		Items json.RawMessage `json:"items"`
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*s = Schema(v.schema)
+
+	items := bytes.TrimSpace(v.Items)
+	switch {
+	case len(items) == 0:
+	case items[0] == '{':
+		s.Items = &Schema{}
+		if err := json.Unmarshal(items, s.Items); err != nil {
+			return err
+		}
+	case items[0] == '[':
+		var tuple []*Schema
+		if err := json.Unmarshal(items, &tuple); err != nil {
+			return err
+		}
+		if len(tuple) > 0 {
+			s.Items = tuple[0]
+		}
+	}
+
+	return nil
+}
+
+// Has reports whether the list contains the type.
+func (t TypeList) Has(typ string) bool {
+	for _, v := range t {
+		if v == typ {
+			return true
+		}
+	}
+	return false
+}
+
+// nonNull returns the types other than null.
+func (t TypeList) nonNull() []string {
+	var out []string
+	for _, v := range t {
+		if v != "null" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// isNull reports whether the schema only allows null.
+func (s *Schema) isNull() bool {
+	return len(s.Type) == 1 && s.Type[0] == "null" && s.Ref == ""
+}
+
+// nullable reports whether the schema allows null.
+func (s *Schema) nullable() bool {
+	if s.Type.Has("null") {
+		return true
+	}
+	for _, v := range s.Enum {
+		if v == nil {
+			return true
+		}
+	}
+	for _, m := range s.variants() {
+		if m.isNull() {
+			return true
+		}
+	}
+	return false
+}
+
+// variants returns the members of oneOf or anyOf.
+func (s *Schema) variants() []*Schema {
+	if len(s.OneOf) > 0 {
+		return s.OneOf
+	}
+	return s.AnyOf
+}
+
+// constString returns the single string value the schema allows, which
+// Payload uses for the blockType of blocks and relationTo of
+// polymorphic relationships.
+func (s *Schema) constString() (string, bool) {
+	if v, ok := s.Const.(string); ok {
+		return v, true
+	}
+	var values []string
+	for _, e := range s.Enum {
+		if v, ok := e.(string); ok {
+			values = append(values, v)
+		}
+	}
+	if len(values) == 1 {
+		return values[0], true
+	}
+	return "", false
+}
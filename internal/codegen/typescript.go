@@ -0,0 +1,628 @@
+package codegen
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ParseTypeScript decodes the payload-types.ts file that Payload
+// generates. Only the subset of TypeScript that Payload emits is
+// supported: interfaces, type aliases, unions, arrays, literals and
+// inline objects. The Config interface maps the slugs of collections
+// and globals to their interfaces.
+//
+// TypeScript has no date type, so date fields are generated as strings.
+// Use the JSON schema when this matters.
+func ParseTypeScript(src []byte) (*Source, error) {
+	p := &tsParser{lex: newTSLexer(string(src))}
+	defs, err := p.parse()
+	if err != nil {
+		return nil, err
+	}
+
+	config, ok := defs["Config"]
+	if !ok {
+		return nil, errors.New("no Config interface found")
+	}
+
+	return newSource(&Schema{
+		Properties:  config.Properties,
+		Definitions: defs,
+	})
+}
+
+type tsTokenKind int
+
+const (
+	tsEOF tsTokenKind = iota
+	tsIdent
+	tsString
+	tsNumber
+	tsPunct
+)
+
+type tsToken struct {
+	kind tsTokenKind
+	text string
+	line int
+}
+
+func (t tsToken) is(punct string) bool {
+	return t.kind == tsPunct && t.text == punct
+}
+
+func (t tsToken) isIdent(name string) bool {
+	return t.kind == tsIdent && t.text == name
+}
+
+type tsLexer struct {
+	src    string
+	pos    int
+	line   int
+	peeked *tsToken
+}
+
+func newTSLexer(src string) *tsLexer {
+	return &tsLexer{src: src, line: 1}
+}
+
+func (l *tsLexer) peek() (tsToken, error) {
+	if l.peeked == nil {
+		t, err := l.scan()
+		if err != nil {
+			return tsToken{}, err
+		}
+		l.peeked = &t
+	}
+	return *l.peeked, nil
+}
+
+func (l *tsLexer) next() (tsToken, error) {
+	t, err := l.peek()
+	l.peeked = nil
+	return t, err
+}
+
+func (l *tsLexer) scan() (tsToken, error) {
+	l.skipSpace()
+	if l.pos >= len(l.src) {
+		return tsToken{kind: tsEOF, line: l.line}, nil
+	}
+
+	start, c := l.pos, l.src[l.pos]
+	switch {
+	case c == '\'' || c == '"':
+		return l.scanString(c)
+	case c >= '0' && c <= '9' || c == '-' && l.pos+1 < len(l.src) && l.src[l.pos+1] >= '0' && l.src[l.pos+1] <= '9':
+		l.pos++
+		for l.pos < len(l.src) && (l.src[l.pos] >= '0' && l.src[l.pos] <= '9' || l.src[l.pos] == '.') {
+			l.pos++
+		}
+		return tsToken{kind: tsNumber, text: l.src[start:l.pos], line: l.line}, nil
+	case isIdentRune(rune(c)):
+		for l.pos < len(l.src) && (isIdentRune(rune(l.src[l.pos])) || l.src[l.pos] >= '0' && l.src[l.pos] <= '9') {
+			l.pos++
+		}
+		return tsToken{kind: tsIdent, text: l.src[start:l.pos], line: l.line}, nil
+	case strings.ContainsRune("{}()[]<>:;,|&?=.", rune(c)):
+		l.pos++
+		return tsToken{kind: tsPunct, text: string(c), line: l.line}, nil
+	}
+
+	return tsToken{}, fmt.Errorf("line %d: unexpected character %q", l.line, c)
+}
+
+func (l *tsLexer) scanString(quote byte) (tsToken, error) {
+	start := l.pos
+	l.pos++
+	for l.pos < len(l.src) && l.src[l.pos] != quote {
+		if l.src[l.pos] == '\\' {
+			l.pos++
+		}
+		if l.pos < len(l.src) && l.src[l.pos] == '\n' {
+			return tsToken{}, fmt.Errorf("line %d: unterminated string", l.line)
+		}
+		l.pos++
+	}
+	if l.pos >= len(l.src) {
+		return tsToken{}, fmt.Errorf("line %d: unterminated string", l.line)
+	}
+	l.pos++
+
+	raw := l.src[start:l.pos]
+	if quote == '\'' {
+		raw = `"` + strings.ReplaceAll(strings.ReplaceAll(raw[1:len(raw)-1], `\'`, `'`), `"`, `\"`) + `"`
+	}
+	s, err := strconv.Unquote(raw)
+	if err != nil {
+		return tsToken{}, fmt.Errorf("line %d: invalid string %s", l.line, l.src[start:l.pos])
+	}
+
+	return tsToken{kind: tsString, text: s, line: l.line}, nil
+}
+
+// skipSpace skips whitespace and comments.
+func (l *tsLexer) skipSpace() {
+	for l.pos < len(l.src) {
+		switch {
+		case l.src[l.pos] == '\n':
+			l.line++
+			l.pos++
+		case unicode.IsSpace(rune(l.src[l.pos])):
+			l.pos++
+		case strings.HasPrefix(l.src[l.pos:], "//"):
+			for l.pos < len(l.src) && l.src[l.pos] != '\n' {
+				l.pos++
+			}
+		case strings.HasPrefix(l.src[l.pos:], "/*"):
+			end := strings.Index(l.src[l.pos+2:], "*/")
+			if end < 0 {
+				end = len(l.src) - l.pos - 2
+			}
+			l.line += strings.Count(l.src[l.pos:l.pos+2+end], "\n")
+			l.pos = min(l.pos+end+4, len(l.src))
+		default:
+			return
+		}
+	}
+}
+
+func isIdentRune(r rune) bool {
+	return r == '_' || r == '$' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z'
+}
+
+type tsParser struct {
+	lex *tsLexer
+}
+
+// parse parses the top level declarations of the file, returning the
+// interfaces and type aliases keyed by name.
+func (p *tsParser) parse() (map[string]*Schema, error) {
+	defs := make(map[string]*Schema)
+	for {
+		t, err := p.lex.next()
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case t.kind == tsEOF:
+			return defs, nil
+		case t.isIdent("import"):
+			if err := p.skipStatement(); err != nil {
+				return nil, err
+			}
+		case t.isIdent("interface"):
+			name, s, err := p.parseInterface()
+			if err != nil {
+				return nil, err
+			}
+			defs[name] = s
+		case t.isIdent("type"):
+			name, s, err := p.parseTypeAlias()
+			if err != nil {
+				return nil, err
+			}
+			defs[name] = s
+		case t.is("{"):
+			// Skips declare module and declare global blocks.
+			if err := p.skipBalanced("{", "}"); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+func (p *tsParser) parseInterface() (string, *Schema, error) {
+	name, err := p.expectIdent()
+	if err != nil {
+		return "", nil, err
+	}
+	if err := p.skipTypeParams(); err != nil {
+		return "", nil, err
+	}
+
+	// Inherited members aren't resolved, only the declared ones are used.
+	for {
+		t, err := p.lex.next()
+		if err != nil {
+			return "", nil, err
+		}
+		if t.is("{") {
+			break
+		}
+		if t.kind == tsEOF {
+			return "", nil, fmt.Errorf("line %d: expected { after interface %s", t.line, name)
+		}
+	}
+
+	s, err := p.parseMembers()
+	if err != nil {
+		return "", nil, fmt.Errorf("interface %s: %w", name, err)
+	}
+
+	return name, s, nil
+}
+
+func (p *tsParser) parseTypeAlias() (string, *Schema, error) {
+	name, err := p.expectIdent()
+	if err != nil {
+		return "", nil, err
+	}
+	if err := p.skipTypeParams(); err != nil {
+		return "", nil, err
+	}
+	if err := p.expect("="); err != nil {
+		return "", nil, err
+	}
+
+	s, err := p.parseType()
+	if err != nil {
+		return "", nil, fmt.Errorf("type %s: %w", name, err)
+	}
+	if t, err := p.lex.peek(); err == nil && t.is(";") {
+		_, _ = p.lex.next()
+	}
+
+	return name, s, nil
+}
+
+// parseMembers parses the members of an object type up to and including
+// the closing brace.
+func (p *tsParser) parseMembers() (*Schema, error) {
+	s := &Schema{Type: TypeList{"object"}}
+	for {
+		t, err := p.lex.next()
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case t.is("}"):
+			return s, nil
+		case t.is(";") || t.is(","):
+			continue
+		case t.is("["):
+			// Index signatures such as [k: string]: unknown.
+			if err := p.skipBalanced("[", "]"); err != nil {
+				return nil, err
+			}
+			if err := p.expect(":"); err != nil {
+				return nil, err
+			}
+			if _, err := p.parseType(); err != nil {
+				return nil, err
+			}
+		case t.kind == tsIdent || t.kind == tsString:
+			name := t.text
+			if t.isIdent("readonly") {
+				if next, err := p.lex.peek(); err == nil && (next.kind == tsIdent || next.kind == tsString) {
+					name = next.text
+					_, _ = p.lex.next()
+				}
+			}
+
+			optional := false
+			if next, err := p.lex.peek(); err == nil && next.is("?") {
+				optional = true
+				_, _ = p.lex.next()
+			}
+			if err := p.expect(":"); err != nil {
+				return nil, err
+			}
+
+			typ, err := p.parseType()
+			if err != nil {
+				return nil, fmt.Errorf("property %s: %w", name, err)
+			}
+			s.Properties = append(s.Properties, Property{Name: name, Schema: typ})
+			if !optional {
+				s.Required = append(s.Required, name)
+			}
+		default:
+			return nil, fmt.Errorf("line %d: unexpected %q in object type", t.line, t.text)
+		}
+	}
+}
+
+// parseType parses a union of types.
+func (p *tsParser) parseType() (*Schema, error) {
+	if t, err := p.lex.peek(); err == nil && t.is("|") {
+		_, _ = p.lex.next()
+	}
+
+	var members []*Schema
+	for {
+		s, err := p.parseIntersection()
+		if err != nil {
+			return nil, err
+		}
+		members = append(members, s)
+
+		t, err := p.lex.peek()
+		if err != nil {
+			return nil, err
+		}
+		if !t.is("|") {
+			break
+		}
+		_, _ = p.lex.next()
+	}
+
+	return union(members), nil
+}
+
+// parseIntersection parses an intersection of types, of which only the
+// first is kept, e.g. User & { collection: 'users' } is a User.
+func (p *tsParser) parseIntersection() (*Schema, error) {
+	s, err := p.parseArray()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t, err := p.lex.peek()
+		if err != nil {
+			return nil, err
+		}
+		if !t.is("&") {
+			return s, nil
+		}
+		_, _ = p.lex.next()
+		if _, err := p.parseArray(); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (p *tsParser) parseArray() (*Schema, error) {
+	s, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t, err := p.lex.peek()
+		if err != nil {
+			return nil, err
+		}
+		if !t.is("[") {
+			return s, nil
+		}
+		_, _ = p.lex.next()
+		if err := p.expect("]"); err != nil {
+			return nil, err
+		}
+		s = &Schema{Type: TypeList{"array"}, Items: s}
+	}
+}
+
+func (p *tsParser) parsePrimary() (*Schema, error) {
+	t, err := p.lex.next()
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case t.is("("):
+		s, err := p.parseType()
+		if err != nil {
+			return nil, err
+		}
+		return s, p.expect(")")
+	case t.is("{"):
+		return p.parseMembers()
+	case t.is("["):
+		return p.parseTuple()
+	case t.kind == tsString:
+		return &Schema{Type: TypeList{"string"}, Const: t.text}, nil
+	case t.kind == tsNumber:
+		return &Schema{Type: TypeList{"number"}}, nil
+	case t.kind == tsIdent:
+		return p.parseNamed(t.text)
+	}
+
+	return nil, fmt.Errorf("line %d: unexpected %q in type", t.line, t.text)
+}
+
+func (p *tsParser) parseTuple() (*Schema, error) {
+	s := &Schema{Type: TypeList{"array"}}
+	for {
+		t, err := p.lex.peek()
+		if err != nil {
+			return nil, err
+		}
+		if t.is("]") {
+			_, _ = p.lex.next()
+			return s, nil
+		}
+		if t.is(",") {
+			_, _ = p.lex.next()
+			continue
+		}
+
+		item, err := p.parseType()
+		if err != nil {
+			return nil, err
+		}
+		if s.Items == nil {
+			s.Items = item
+		}
+	}
+}
+
+func (p *tsParser) parseNamed(name string) (*Schema, error) {
+	switch name {
+	case "string":
+		return &Schema{Type: TypeList{"string"}}, nil
+	case "number":
+		return &Schema{Type: TypeList{"number"}}, nil
+	case "boolean", "true", "false":
+		return &Schema{Type: TypeList{"boolean"}}, nil
+	case "null", "undefined":
+		return &Schema{Type: TypeList{"null"}}, nil
+	case "unknown", "any", "never", "object":
+		return &Schema{}, nil
+	}
+
+	// Qualified names and type arguments aren't resolved.
+	for {
+		t, err := p.lex.peek()
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case t.is("."):
+			_, _ = p.lex.next()
+			next, err := p.expectIdent()
+			if err != nil {
+				return nil, err
+			}
+			name = next
+		case t.is("<"):
+			if err := p.skipTypeParams(); err != nil {
+				return nil, err
+			}
+		default:
+			return &Schema{Ref: "#/definitions/" + name}, nil
+		}
+	}
+}
+
+// union builds the schema of a union of types. Unions of string literals
+// become an enum, and a union with null becomes nullable.
+func union(members []*Schema) *Schema {
+	if len(members) == 1 {
+		return members[0]
+	}
+
+	var (
+		flat     []*Schema
+		nullable bool
+	)
+	for _, m := range members {
+		switch {
+		case m.isNull():
+			nullable = true
+		case len(m.OneOf) > 0:
+			for _, v := range m.OneOf {
+				if v.isNull() {
+					nullable = true
+				} else {
+					flat = append(flat, v)
+				}
+			}
+		default:
+			if m.Type.Has("null") {
+				nullable = true
+				m.Type = m.Type.nonNull()
+			}
+			flat = append(flat, m)
+		}
+	}
+
+	if enum, ok := stringEnum(flat); ok {
+		s := &Schema{Type: TypeList{"string"}, Enum: enum}
+		if nullable {
+			s.Type = append(s.Type, "null")
+		}
+		return s
+	}
+
+	if len(flat) == 1 && flat[0].Ref == "" && len(flat[0].Type) > 0 {
+		s := flat[0]
+		if nullable {
+			s.Type = append(s.Type, "null")
+		}
+		return s
+	}
+
+	if nullable {
+		flat = append(flat, &Schema{Type: TypeList{"null"}})
+	}
+	return &Schema{OneOf: flat}
+}
+
+// stringEnum returns the values of a union of string literals.
+func stringEnum(members []*Schema) ([]any, bool) {
+	if len(members) < 2 {
+		return nil, false
+	}
+	var values []any
+	for _, m := range members {
+		if len(m.Enum) > 0 && m.Type.Has("string") {
+			values = append(values, m.Enum...)
+			continue
+		}
+		v, ok := m.Const.(string)
+		if !ok {
+			return nil, false
+		}
+		values = append(values, v)
+	}
+	return values, true
+}
+
+func (p *tsParser) expect(punct string) error {
+	t, err := p.lex.next()
+	if err != nil {
+		return err
+	}
+	if !t.is(punct) {
+		return fmt.Errorf("line %d: expected %q, got %q", t.line, punct, t.text)
+	}
+	return nil
+}
+
+func (p *tsParser) expectIdent() (string, error) {
+	t, err := p.lex.next()
+	if err != nil {
+		return "", err
+	}
+	if t.kind != tsIdent {
+		return "", fmt.Errorf("line %d: expected identifier, got %q", t.line, t.text)
+	}
+	return t.text, nil
+}
+
+// skipStatement skips tokens up to and including the next semicolon.
+func (p *tsParser) skipStatement() error {
+	for {
+		t, err := p.lex.next()
+		if err != nil || t.kind == tsEOF || t.is(";") {
+			return err
+		}
+	}
+}
+
+// skipTypeParams skips type parameters or arguments, e.g. <T = true>.
+func (p *tsParser) skipTypeParams() error {
+	t, err := p.lex.peek()
+	if err != nil || !t.is("<") {
+		return err
+	}
+	_, _ = p.lex.next()
+	return p.skipBalanced("<", ">")
+}
+
+// skipBalanced skips tokens up to and including the close that matches
+// an open token that has already been consumed.
+func (p *tsParser) skipBalanced(open, closing string) error {
+	depth := 1
+	for depth > 0 {
+		t, err := p.lex.next()
+		if err != nil {
+			return err
+		}
+		switch {
+		case t.kind == tsEOF:
+			return fmt.Errorf("line %d: expected %q", t.line, closing)
+		case t.is(open):
+			depth++
+		case t.is(closing):
+			depth--
+		}
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package codegen
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTypeScript(t *testing.T) {
+	t.Parallel()
+
+	src, err := ParseTypeScript([]byte(`
+		import type { Foo } from 'bar';
+
+		// A line comment.
+		export interface Config {
+			collections: {
+				posts: Post;
+			};
+			globals: {};
+			user: User & { collection: 'users' };
+		}
+		export interface Post {
+			readonly id: number;
+			'quoted-name'?: string;
+			status?: ('a' | 'b') | null;
+			tags: string[][];
+			point?: [number, number];
+			select?: PostsSelect<true>;
+			ns?: Payload.Thing;
+		}
+		export interface PostsSelect<T extends boolean = true> {
+			id?: T;
+		}
+		export type Alias = 'x' | 'y';
+		declare module 'payload' {
+			export interface GeneratedTypes extends Config {}
+		}
+	`))
+	require.NoError(t, err)
+
+	require.Len(t, src.Collections, 1)
+	assert.Equal(t, Entity{Slug: "posts", Definition: "Post"}, src.Collections[0])
+	assert.Empty(t, src.Globals)
+
+	post := src.Definitions["Post"]
+	require.NotNil(t, post)
+	assert.Equal(t, []string{"id", "tags"}, post.Required)
+
+	names := make([]string, 0, len(post.Properties))
+	for _, p := range post.Properties {
+		names = append(names, p.Name)
+	}
+	assert.Equal(t, []string{"id", "quoted-name", "status", "tags", "point", "select", "ns"}, names)
+
+	status := post.Properties.Get("status")
+	assert.Equal(t, TypeList{"string", "null"}, status.Type)
+	assert.Equal(t, []any{"a", "b"}, status.Enum)
+
+	tags := post.Properties.Get("tags")
+	assert.Equal(t, TypeList{"array"}, tags.Type)
+	assert.Equal(t, TypeList{"string"}, tags.Items.Items.Type)
+
+	assert.Equal(t, TypeList{"number"}, post.Properties.Get("point").Items.Type)
+	assert.Equal(t, "#/definitions/PostsSelect", post.Properties.Get("select").Ref)
+	assert.Equal(t, "#/definitions/Thing", post.Properties.Get("ns").Ref)
+	assert.Equal(t, []any{"x", "y"}, src.Definitions["Alias"].Enum)
+	assert.Equal(t, "#/definitions/User", src.Definitions["Config"].Properties.Get("user").Ref)
+}
+
+func TestParseTypeScript_Errors(t *testing.T) {
+	t.Parallel()
+
+	for name, input := range map[string]string{
+		"No Config":           `export interface Post { id: number; }`,
+		"No Entities":         `export interface Config { collections: {}; }`,
+		"Unexpected Char":     `export interface Config { collections: { posts: Post # } }`,
+		"Unterminated String": `export interface Config { 'collections: {} }`,
+		"Unterminated Object": `export interface Config { collections: {`,
+		"Missing Colon":       `export interface Config { collections {} }`,
+		"Bad Member":          `export interface Config { ( }`,
+		"Bad Type":            `export interface Config { collections: ; }`,
+		"Bad Array":           `export interface Config { collections: string[number]; }`,
+		"Missing Definition":  `export interface Config { collections: { posts: Post; }; }`,
+		"Bad Alias":           `export type = string;`,
+		"Interface No Body":   `export interface Config`,
+	} {
+		_, err := ParseTypeScript([]byte(input))
+		assert.Error(t, err, name)
+	}
+}
@@ -0,0 +1,269 @@
+package payloadcms
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+// ErrNotFound is returned when a document could not be found in Payload.
+var ErrNotFound = errors.New("document not found")
+
+// Loader batches FindByID calls for a single collection. Calls made within
+// a short window are collected and sent to Payload as one List request
+// using a where[id][in] query, instead of one request per document.
+//
+// This is useful when resolving relationships at depth 0, for example
+// within a GraphQL gateway, where N documents would otherwise result
+// in N separate requests.
+type Loader[T any] struct {
+	service    CollectionService
+	collection Collection
+	config     loaderConfig
+
+	mu    sync.Mutex
+	batch *loaderBatch[T]
+}
+
+// LoaderOption is a functional option type that allows us to configure a Loader.
+type LoaderOption func(*loaderConfig)
+
+type loaderConfig struct {
+	wait     time.Duration
+	maxBatch int
+	timeout  time.Duration
+	opts     []RequestOption
+}
+
+// loaderBatch holds the IDs collected within a single window.
+type loaderBatch[T any] struct {
+	ctx context.Context
+	// deadline is the latest deadline of the callers, unbounded is set
+	// when one of them has no deadline.
+	deadline  time.Time
+	unbounded bool
+	ids       []string
+	seen      map[string]struct{}
+	once      sync.Once
+	done      chan struct{}
+	docs      map[string]T
+	err       error
+}
+
+const (
+	// DefaultLoaderWait is the default window in which FindByID calls are collected.
+	DefaultLoaderWait = 2 * time.Millisecond
+	// DefaultLoaderMaxBatch is the default maximum amount of IDs sent in a single request.
+	DefaultLoaderMaxBatch = 100
+	// DefaultLoaderTimeout is the default time a batch may take when a
+	// caller has no deadline.
+	DefaultLoaderTimeout = 30 * time.Second
+)
+
+// WithLoaderWait sets the window in which calls are collected before
+// the batch is sent to Payload.
+func WithLoaderWait(wait time.Duration) LoaderOption {
+	return func(c *loaderConfig) {
+		c.wait = wait
+	}
+}
+
+// WithLoaderMaxBatch sets the maximum amount of IDs sent in a single request.
+// Once reached, the batch is sent immediately.
+func WithLoaderMaxBatch(n int) LoaderOption {
+	return func(c *loaderConfig) {
+		c.maxBatch = n
+	}
+}
+
+// WithLoaderTimeout sets the time a batch may take when one of its
+// callers has no deadline. Otherwise, the batch runs until the latest
+// deadline of its callers, so that it's never left running if Payload
+// stops responding.
+func WithLoaderTimeout(timeout time.Duration) LoaderOption {
+	return func(c *loaderConfig) {
+		c.timeout = timeout
+	}
+}
+
+// WithLoaderRequestOptions sets the request options that are passed to
+// every List request, for example WithDepth(0). WithNoCache is always
+// passed as well, so batches aren't served from the response cache.
+func WithLoaderRequestOptions(opts ...RequestOption) LoaderOption {
+	return func(c *loaderConfig) {
+		c.opts = opts
+	}
+}
+
+// NewLoader creates a new Loader for the given collection.
+func NewLoader[T any](service CollectionService, collection Collection, options ...LoaderOption) *Loader[T] {
+	cfg := loaderConfig{
+		wait:     DefaultLoaderWait,
+		maxBatch: DefaultLoaderMaxBatch,
+		timeout:  DefaultLoaderTimeout,
+	}
+	for _, opt := range options {
+		opt(&cfg)
+	}
+	if cfg.maxBatch <= 0 {
+		cfg.maxBatch = DefaultLoaderMaxBatch
+	}
+	if cfg.timeout <= 0 {
+		cfg.timeout = DefaultLoaderTimeout
+	}
+	// Batches are keyed by their IDs, so cached responses would rarely
+	// be reused and could only be invalidated as lists.
+	cfg.opts = append(slices.Clone(cfg.opts), WithNoCache())
+	return &Loader[T]{
+		service:    service,
+		collection: collection,
+		config:     cfg,
+	}
+}
+
+// Load finds a single document by its ID. The call is batched with any
+// other Load calls made within the window. If the document does not
+// exist, an error wrapping ErrNotFound is returned.
+func (l *Loader[T]) Load(ctx context.Context, id any) (T, error) {
+	var zero T
+	key := fmt.Sprint(id)
+	b := l.enqueue(ctx, key)
+
+	select {
+	case <-b.done:
+	case <-ctx.Done():
+		return zero, ctx.Err()
+	}
+
+	if b.err != nil {
+		return zero, b.err
+	}
+
+	doc, ok := b.docs[key]
+	if !ok {
+		return zero, fmt.Errorf("%w: %s with id %s", ErrNotFound, l.collection, key)
+	}
+
+	return doc, nil
+}
+
+// LoadMany finds multiple documents by their IDs. The returned documents
+// and errors are in the same order as the IDs that were passed.
+func (l *Loader[T]) LoadMany(ctx context.Context, ids []any) ([]T, []error) {
+	var (
+		wg   sync.WaitGroup
+		docs = make([]T, len(ids))
+		errs = make([]error, len(ids))
+	)
+	for i, id := range ids {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			docs[i], errs[i] = l.Load(ctx, id)
+		}()
+	}
+	wg.Wait()
+	return docs, errs
+}
+
+// enqueue adds the ID to the current batch, creating a new batch if one
+// is not being collected.
+func (l *Loader[T]) enqueue(ctx context.Context, key string) *loaderBatch[T] {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b := l.batch
+	if b == nil {
+		b = &loaderBatch[T]{
+			ctx:  context.WithoutCancel(ctx),
+			seen: make(map[string]struct{}),
+			done: make(chan struct{}),
+		}
+		l.batch = b
+		time.AfterFunc(l.config.wait, func() {
+			l.dispatch(b)
+		})
+	}
+
+	if deadline, ok := ctx.Deadline(); !ok {
+		b.unbounded = true
+	} else if deadline.After(b.deadline) {
+		b.deadline = deadline
+	}
+
+	if _, ok := b.seen[key]; !ok {
+		b.seen[key] = struct{}{}
+		b.ids = append(b.ids, key)
+	}
+
+	if len(b.ids) >= l.config.maxBatch {
+		l.batch = nil
+		go l.dispatch(b)
+	}
+
+	return b
+}
+
+// dispatch sends the batch to Payload, it's only ever performed once
+// per batch.
+func (l *Loader[T]) dispatch(b *loaderBatch[T]) {
+	b.once.Do(func() {
+		l.mu.Lock()
+		if l.batch == b {
+			l.batch = nil
+		}
+		ctx, cancel := l.batchContext(b)
+		l.mu.Unlock()
+		defer cancel()
+
+		b.docs, b.err = l.fetch(ctx, b.ids)
+		close(b.done)
+	})
+}
+
+// batchContext returns the context the batch is fetched with. The batch
+// outlives the cancellation of any single caller, but not the latest
+// deadline of its callers, or the timeout if one has no deadline.
+func (l *Loader[T]) batchContext(b *loaderBatch[T]) (context.Context, context.CancelFunc) {
+	if b.unbounded {
+		return context.WithTimeout(b.ctx, l.config.timeout)
+	}
+	return context.WithDeadline(b.ctx, b.deadline)
+}
+
+// fetch lists the documents with the given IDs and maps them by ID.
+func (l *Loader[T]) fetch(ctx context.Context, ids []string) (map[string]T, error) {
+	var list ListResponse[json.RawMessage]
+	_, err := l.service.List(ctx, l.collection, ListParams{
+		Where: Query().In("id", ids),
+		Limit: len(ids),
+	}, &list, l.config.opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	docs := make(map[string]T, len(list.Docs))
+	for _, raw := range list.Docs {
+		var ref struct {
+			ID any `json:"id"`
+		}
+		dec := json.NewDecoder(bytes.NewReader(raw))
+		dec.UseNumber()
+		if err := dec.Decode(&ref); err != nil {
+			return nil, err
+		}
+
+		var doc T
+		if err := json.Unmarshal(raw, &doc); err != nil {
+			return nil, err
+		}
+		docs[fmt.Sprint(ref.ID)] = doc
+	}
+
+	return docs, nil
+}
@@ -0,0 +1,249 @@
+package payloadcms
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupLoaderServer returns a client that responds to List requests with
+// a document for every requested ID, except for IDs over 100.
+func setupLoaderServer(t *testing.T) (CollectionService, *atomic.Int32, func()) {
+	t.Helper()
+
+	calls := &atomic.Int32{}
+	client, teardown := Setup(t, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		assert.Equal(t, "/api/posts", r.URL.Path)
+
+		ids := strings.Split(r.URL.Query().Get("where[id][in]"), ",")
+		assert.Equal(t, strconv.Itoa(len(ids)), r.URL.Query().Get("limit"))
+
+		list := ListResponse[Resource]{}
+		// Respond in reverse order to ensure docs are matched by ID.
+		for i := len(ids) - 1; i >= 0; i-- {
+			id, err := strconv.Atoi(ids[i])
+			require.NoError(t, err)
+			if id > 100 {
+				continue
+			}
+			list.Docs = append(list.Docs, Resource{ID: id, Name: fmt.Sprintf("Post %d", id)})
+		}
+
+		w.WriteHeader(http.StatusOK)
+		require.NoError(t, json.NewEncoder(w).Encode(list))
+	})
+
+	return CollectionServiceOp{Client: client}, calls, teardown
+}
+
+func TestLoader_Load(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Batches Calls", func(t *testing.T) {
+		t.Parallel()
+
+		service, calls, teardown := setupLoaderServer(t)
+		defer teardown()
+
+		loader := NewLoader[Resource](service, "posts", WithLoaderWait(20*time.Millisecond))
+
+		var wg sync.WaitGroup
+		for i := 1; i <= 5; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				got, err := loader.Load(context.Background(), i)
+				AssertNoError(t, err)
+				AssertEqual(t, Resource{ID: i, Name: fmt.Sprintf("Post %d", i)}, got)
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("Deduplicates IDs", func(t *testing.T) {
+		t.Parallel()
+
+		service, calls, teardown := setupLoaderServer(t)
+		defer teardown()
+
+		loader := NewLoader[Resource](service, "posts")
+		docs, errs := loader.LoadMany(context.Background(), []any{1, "1", 1})
+
+		for i := range docs {
+			require.NoError(t, errs[i])
+			assert.Equal(t, 1, docs[i].ID)
+		}
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		t.Parallel()
+
+		service, _, teardown := setupLoaderServer(t)
+		defer teardown()
+
+		loader := NewLoader[Resource](service, "posts")
+		_, err := loader.Load(context.Background(), 404)
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("Max Batch", func(t *testing.T) {
+		t.Parallel()
+
+		service, calls, teardown := setupLoaderServer(t)
+		defer teardown()
+
+		loader := NewLoader[Resource](service, "posts",
+			WithLoaderWait(time.Hour),
+			WithLoaderMaxBatch(2),
+		)
+		docs, errs := loader.LoadMany(context.Background(), []any{1, 2, 3, 4})
+
+		for i := range docs {
+			require.NoError(t, errs[i])
+			assert.Equal(t, i+1, docs[i].ID)
+		}
+		assert.Equal(t, int32(2), calls.Load())
+	})
+
+	t.Run("Bypasses Cache", func(t *testing.T) {
+		t.Parallel()
+
+		service, calls, teardown := setupLoaderServer(t)
+		defer teardown()
+
+		cache := NewMemoryCache(10)
+		WithCache(cache, time.Hour)(service.(CollectionServiceOp).Client)
+
+		loader := NewLoader[Resource](service, "posts")
+		for range 2 {
+			_, err := loader.Load(context.Background(), 1)
+			require.NoError(t, err)
+		}
+
+		assert.Equal(t, int32(2), calls.Load())
+		assert.Equal(t, 0, cache.Len())
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		t.Parallel()
+
+		client, teardown := Setup(t, func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		})
+		defer teardown()
+
+		loader := NewLoader[Resource](CollectionServiceOp{Client: client}, "posts")
+		_, err := loader.Load(context.Background(), 1)
+		assert.Error(t, err)
+		t.Log("DEBUG", err)
+		assert.False(t, errors.Is(err, ErrNotFound))
+	})
+
+	t.Run("Context Cancelled", func(t *testing.T) {
+		t.Parallel()
+
+		service, _, teardown := setupLoaderServer(t)
+		defer teardown()
+
+		loader := NewLoader[Resource](service, "posts", WithLoaderWait(time.Hour))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := loader.Load(ctx, 1)
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+// hangingService is a CollectionService whose List calls block until
+// their context is done, sending the time it happened on abandoned.
+type hangingService struct {
+	CollectionService
+	abandoned chan time.Time
+}
+
+func (s hangingService) List(ctx context.Context, _ Collection, _ ListParams, _ any, _ ...RequestOption) (Response, error) {
+	<-ctx.Done()
+	s.abandoned <- time.Now()
+	return Response{}, ctx.Err()
+}
+
+func TestLoader_Timeout(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Loader Timeout", func(t *testing.T) {
+		t.Parallel()
+
+		service := hangingService{abandoned: make(chan time.Time, 1)}
+		loader := NewLoader[Resource](service, "posts", WithLoaderTimeout(20*time.Millisecond))
+
+		_, err := loader.Load(context.Background(), 1)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Len(t, service.abandoned, 1)
+	})
+
+	t.Run("Latest Caller Deadline", func(t *testing.T) {
+		t.Parallel()
+
+		service := hangingService{abandoned: make(chan time.Time, 1)}
+		loader := NewLoader[Resource](service, "posts",
+			WithLoaderTimeout(time.Hour),
+			WithLoaderWait(50*time.Millisecond),
+		)
+
+		short, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		long, cancel := context.WithTimeout(context.Background(), 150*time.Millisecond)
+		defer cancel()
+		deadline, _ := long.Deadline()
+
+		var wg sync.WaitGroup
+		for _, ctx := range []context.Context{short, long} {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := loader.Load(ctx, 1)
+				assert.ErrorIs(t, err, context.DeadlineExceeded)
+			}()
+		}
+		wg.Wait()
+
+		select {
+		case at := <-service.abandoned:
+			assert.False(t, at.Before(deadline))
+		case <-time.After(time.Second):
+			t.Fatal("batch was not abandoned")
+		}
+	})
+}
+
+func TestLoader_LoadMany(t *testing.T) {
+	t.Parallel()
+
+	service, calls, teardown := setupLoaderServer(t)
+	defer teardown()
+
+	loader := NewLoader[Resource](service, "posts")
+	docs, errs := loader.LoadMany(context.Background(), []any{3, 101, 1, 2})
+
+	assert.Equal(t, int32(1), calls.Load())
+	assert.Equal(t, 3, docs[0].ID)
+	assert.ErrorIs(t, errs[1], ErrNotFound)
+	assert.Equal(t, 1, docs[2].ID)
+	assert.Equal(t, 2, docs[3].ID)
+}
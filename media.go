@@ -1,17 +1,23 @@
 package payloadcms
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"io/fs"
+	"mime"
 	"mime/multipart"
 	"net/http"
 	"net/textproto"
+	"net/url"
+	"os"
 	"path"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/gabriel-vasile/mimetype"
@@ -23,7 +29,11 @@ import (
 // See: https://payloadcms.com/docs/upload/overview
 type MediaService interface {
 	Upload(ctx context.Context, r io.Reader, in, out any, opts MediaOptions) (Response, error)
-	UploadFromURL(ctx context.Context, url string, in, out any, opts MediaOptions) (Response, error)
+	UploadFile(ctx context.Context, name string, in, out any, opts MediaOptions) (Response, error)
+	UploadFS(ctx context.Context, fsys fs.FS, name string, in, out any, opts MediaOptions) (Response, error)
+	UploadFromURL(ctx context.Context, rawURL string, in, out any, opts MediaOptions) (Response, error)
+	Replace(ctx context.Context, id any, r io.Reader, in, out any, opts MediaOptions) (Response, error)
+	Download(ctx context.Context, collection Collection, id any, size string) (io.ReadCloser, error)
 }
 
 // MediaServiceOp represents a service for managing media within Payload.
@@ -39,6 +49,20 @@ type MediaOptions struct {
 	// extension here.
 	// Note, this will not change the file extension.
 	FileName string
+	// ContentType is the MIME type of the file, such as "image/svg+xml".
+	// When set, the type isn't detected from the content of the file, and
+	// FileName may include its extension, which is kept as it is.
+	ContentType string
+	// Progress is called as the upload is sent, with the number of bytes
+	// sent so far and the total size of the request body, which is -1
+	// when the size of the file isn't known. It's called from the
+	// goroutine sending the request, one call at a time.
+	Progress func(sent, total int64)
+	// Download configures the download of the file by UploadFromURL.
+	Download DownloadOptions
+	// Constraints are checked against the file before it's sent, returning
+	// a *ValidationError if the file breaks one of them.
+	Constraints UploadConstraints
 }
 
 // Upload uploads a file to the media endpoint.
@@ -47,157 +71,686 @@ func (s MediaServiceOp) Upload(ctx context.Context, r io.Reader, in, out any, op
 	if err != nil {
 		return Response{}, err
 	}
-	return s.uploadFile(ctx, values, out, opts)
+	return s.uploadFile(ctx, http.MethodPost, "", values, out, opts)
 }
 
-func (s MediaServiceOp) UploadFromURL(ctx context.Context, url string, in, out any, opts MediaOptions) (Response, error) {
+// UploadFile uploads the file on disk at the path given. See UploadFS
+// for how the filename and MIME type are set.
+func (s MediaServiceOp) UploadFile(ctx context.Context, name string, in, out any, opts MediaOptions) (Response, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return Response{}, err
+	}
+	defer f.Close()
+	r, opts, err := fileOptions(f, name, opts)
+	if err != nil {
+		return Response{}, err
+	}
+	return s.Upload(ctx, r, in, out, opts)
+}
+
+// UploadFS uploads the named file from fsys.
+//
+// Unless set in the options, the FileName is the base name of the file
+// and the ContentType is detected from its content. The extension of the
+// file is kept when it matches the detected type, otherwise it's replaced
+// with the extension of the type as with Upload.
+func (s MediaServiceOp) UploadFS(ctx context.Context, fsys fs.FS, name string, in, out any, opts MediaOptions) (Response, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return Response{}, err
+	}
+	defer f.Close()
+	r, opts, err := fileOptions(f, name, opts)
+	if err != nil {
+		return Response{}, err
+	}
+	return s.Upload(ctx, r, in, out, opts)
+}
+
+// fileOptions sets the FileName and ContentType of the options from the
+// name and content of a file, if they aren't already set. The start of
+// f is read to detect the MIME type, so the returned reader must be
+// uploaded in its place.
+func fileOptions(f io.Reader, name string, opts MediaOptions) (io.Reader, MediaOptions, error) {
+	if opts.FileName == "" {
+		opts.FileName = filepath.Base(name)
+	}
+	if opts.ContentType != "" {
+		return f, opts, nil
+	}
+
+	size := readerSize(f)
+	head := make([]byte, sniffLen)
+	n, err := io.ReadFull(f, head)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil, opts, fmt.Errorf("failed to detect mime type: %w", err)
+	}
+	head = head[:n]
+
+	ext := filepath.Ext(opts.FileName)
+	if detected := mimetype.Detect(head); strings.EqualFold(detected.Extension(), ext) {
+		opts.ContentType = detected.String()
+	} else {
+		// The detected extension is added to the filename.
+		opts.FileName = strings.TrimSuffix(opts.FileName, ext)
+	}
+
+	return &sizedReader{Reader: io.MultiReader(bytes.NewReader(head), f), size: size}, opts, nil
+}
+
+// Replace replaces the file of an existing media document, keeping its ID
+// and any relations to it. The fields of in are updated along with the
+// file, and may be nil to only replace the file.
+func (s MediaServiceOp) Replace(ctx context.Context, id any, r io.Reader, in, out any, opts MediaOptions) (Response, error) {
+	if opts.Collection == "" {
+		opts.Collection = "media"
+	}
+	values, err := fileUploadValues(r, in)
+	if err != nil {
+		return Response{}, err
+	}
+	if in == nil {
+		delete(values, "_payload")
+	}
+	resp, err := s.uploadFile(ctx, http.MethodPatch, fmt.Sprintf("/%v", id), values, out, opts)
+	if err == nil {
+		s.Client.cache.invalidateDocument(opts.Collection, id)
+	}
+	return resp, err
+}
+
+// ErrSizeNotFound is returned by Download when the media document has
+// no image size with the name given.
+var ErrSizeNotFound = errors.New("image size not found")
+
+// Download streams the file of a media document, or one of its image
+// sizes when size isn't empty. The file is requested with the API key of
+// the client when it's served by Payload. The caller must close the
+// reader returned.
+//
+// When the client was created with WithMediaCache, the file is read from
+// the cache if it's already been downloaded and hasn't changed.
+func (s MediaServiceOp) Download(ctx context.Context, collection Collection, id any, size string) (io.ReadCloser, error) {
+	var doc MediaDoc
+	path := fmt.Sprintf("/api/%s/%v", collection, id)
+	if _, err := s.Client.Get(ctx, path, &doc, WithDepth(0)); err != nil {
+		return nil, err
+	}
+
+	file := doc.size(size)
+	if size != "" {
+		if _, ok := doc.Sizes[size]; !ok {
+			return nil, fmt.Errorf("%w: %q", ErrSizeNotFound, size)
+		}
+	}
+	if file.URL == "" {
+		return nil, fmt.Errorf("media %v has no url", id)
+	}
+
+	req, err := s.Client.newFileRequest(ctx, file.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	cache := s.Client.mediaCache
+	entry, cached := mediaCacheEntry{}, false
+	if cache != nil {
+		entry, cached = cache.lookup(file.URL)
+		cached = cached && entry.Filesize == file.Filesize
+		if cached && entry.ETag == "" {
+			return cache.open(entry)
+		}
+		if cached {
+			req.Header.Set("If-None-Match", entry.ETag)
+		}
+	}
+
+	resp, err := s.Client.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case cached && resp.StatusCode == http.StatusNotModified:
+		resp.Body.Close()
+		return cache.open(entry)
+	case !is2xx(resp.StatusCode):
+		resp.Body.Close()
+		return nil, fmt.Errorf("failed to download file: status code %d", resp.StatusCode)
+	case cache == nil:
+		return resp.Body, nil
+	}
+
+	defer resp.Body.Close()
+	entry, err = cache.store(file.URL, resp.Header.Get("ETag"), resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to cache download: %w", err)
+	}
+	return cache.open(entry)
+}
+
+// UploadFromURL downloads the file at the URL and uploads it to Payload,
+// streaming it from one to the other. The download is configured with
+// MediaOptions.Download, which can limit its size and MIME type.
+//
+// When no FileName is set, the name is taken from the Content-Disposition
+// header of the download, then from the path of the URL.
+func (s MediaServiceOp) UploadFromURL(ctx context.Context, rawURL string, in, out any, opts MediaOptions) (Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return Response{}, err
+	}
+
+	client := opts.Download.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
 	// Download the file from the URL
-	resp, err := s.Client.client.Get(url)
+	resp, err := client.Do(req)
 	if err != nil {
 		return Response{}, err
 	}
 	defer resp.Body.Close()
 
-	// Check if the response status is OK
-	if resp.StatusCode != http.StatusOK {
+	if !is2xx(resp.StatusCode) {
 		return Response{}, fmt.Errorf("failed to download file: status code %d", resp.StatusCode)
 	}
 
-	// If filename not provided in options, try to get it from URL
+	body, err := opts.Download.check(resp)
+	if err != nil {
+		return Response{}, err
+	}
+
+	// If filename not provided in options, try to get it from the response
 	if opts.FileName == "" {
-		filename := fileNameFromURL(url)
+		filename := fileNameFromDisposition(resp.Header.Get("Content-Disposition"))
+		if filename == "" {
+			filename = fileNameFromURL(rawURL)
+		}
 		if filename == "" {
 			return Response{}, errors.New("no filename provided and couldn't extract from URL")
 		}
-		// Strip the extension as handleReaderUpload will add the correct one
+		// Strip the extension as the upload will add the detected one
 		opts.FileName = strings.TrimSuffix(filename, filepath.Ext(filename))
 	}
 
-	values, err := fileUploadValues(resp.Body, in)
+	values, err := fileUploadValues(body, in)
 	if err != nil {
 		return Response{}, err
 	}
 
-	return s.uploadFile(ctx, values, out, opts)
+	return s.uploadFile(ctx, http.MethodPost, "", values, out, opts)
 }
 
-// uploadFile prepares a multipart form and performs the upload request
-//   - Takes the context, collection name, map of form values (including the file), and optional output struct
-//   - Returns a Response object and any errors encountered
-func (s MediaServiceOp) uploadFile(ctx context.Context, values map[string]io.Reader, out any, opts MediaOptions) (Response, error) {
-	if opts.Collection == "" {
-		opts.Collection = "media"
+var (
+	// ErrDownloadTooLarge is returned by UploadFromURL when the file is
+	// larger than DownloadOptions.MaxBytes.
+	ErrDownloadTooLarge = errors.New("download exceeds the maximum size")
+	// ErrDownloadTypeNotAllowed is returned by UploadFromURL when the file
+	// isn't one of DownloadOptions.AllowedTypes.
+	ErrDownloadTypeNotAllowed = errors.New("download type is not allowed")
+)
+
+// DownloadOptions configures how UploadFromURL downloads a file.
+type DownloadOptions struct {
+	// Client is the HTTP client used for the download, which defaults
+	// to http.DefaultClient. The client used for Payload isn't used, so
+	// that its settings aren't applied to third party hosts.
+	Client *http.Client
+	// MaxBytes is the maximum size of the file in bytes, there's no
+	// limit when zero.
+	MaxBytes int64
+	// AllowedTypes are the MIME types the file may be, detected from its
+	// content. Wildcards such as "image/*" are supported, and any type
+	// is allowed when empty.
+	AllowedTypes []string
+}
+
+// check returns the body of the download, limited to MaxBytes, after
+// checking its size and MIME type.
+func (o DownloadOptions) check(resp *http.Response) (io.Reader, error) {
+	if o.MaxBytes > 0 && resp.ContentLength > o.MaxBytes {
+		return nil, fmt.Errorf("%w: %d bytes is over the limit of %d", ErrDownloadTooLarge, resp.ContentLength, o.MaxBytes)
 	}
 
-	// Prepare a multipart form
-	var b bytes.Buffer
-	w := multipart.NewWriter(&b)
-	for key, r := range values {
-		if key == "file" {
-			if err := handleReaderUpload(w, key, r, opts.FileName); err != nil {
-				return Response{}, err
-			}
-		} else {
-			fw, err := w.CreateFormField(key)
-			if err != nil {
-				return Response{}, err
-			}
-			if _, err := io.Copy(fw, r); err != nil {
-				return Response{}, err
+	var body io.Reader = resp.Body
+	if o.MaxBytes > 0 {
+		body = &maxBytesReader{
+			r:         body,
+			remaining: o.MaxBytes,
+			err:       fmt.Errorf("%w: over the limit of %d bytes", ErrDownloadTooLarge, o.MaxBytes),
+		}
+	}
+
+	if len(o.AllowedTypes) > 0 {
+		br := bufio.NewReaderSize(body, sniffLen)
+		head, err := br.Peek(sniffLen)
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("failed to detect mime type: %w", err)
+		}
+		detected := mimetype.Detect(head)
+		if !mimeAllowed(detected.String(), o.AllowedTypes) {
+			return nil, fmt.Errorf("%w: %s", ErrDownloadTypeNotAllowed, detected.String())
+		}
+		body = br
+	}
+
+	// The size is known when the download isn't compressed or chunked.
+	if resp.ContentLength >= 0 {
+		body = &sizedReader{Reader: body, size: resp.ContentLength}
+	}
+
+	return body, nil
+}
+
+// mimeAllowed reports whether the MIME type matches one of the allowed
+// types, which may end with a wildcard subtype such as "image/*".
+func mimeAllowed(mimeType string, allowed []string) bool {
+	base := strings.ToLower(strings.TrimSpace(strings.SplitN(mimeType, ";", 2)[0]))
+	known := mimetype.Lookup(base)
+	for _, a := range allowed {
+		if prefix, ok := strings.CutSuffix(a, "/*"); ok {
+			if strings.HasPrefix(base, strings.ToLower(prefix)+"/") {
+				return true
 			}
+			continue
+		}
+		if strings.EqualFold(base, a) || (known != nil && known.Is(a)) {
+			return true
 		}
 	}
+	return false
+}
+
+// maxBytesReader returns err once more than remaining bytes have been
+// read from r.
+type maxBytesReader struct {
+	r         io.Reader
+	remaining int64
+	err       error
+}
+
+func (m *maxBytesReader) Read(p []byte) (int, error) {
+	// Reading one byte over the limit distinguishes a file that's
+	// exactly the limit from one that's larger.
+	if int64(len(p)) > m.remaining+1 {
+		p = p[:m.remaining+1]
+	}
+	n, err := m.r.Read(p)
+	if int64(n) > m.remaining {
+		n = int(m.remaining)
+		m.remaining = 0
+		return n, m.err
+	}
+	m.remaining -= int64(n)
+	return n, err
+}
+
+// sizedReader is a reader of a known size, such as a download with a
+// Content-Length, so the size of the upload can be set.
+type sizedReader struct {
+	io.Reader
+	size int64
+}
+
+// uploadFile streams a multipart form containing the values to Payload,
+// sending it to the path of the collection followed by suffix. The form
+// is written to the request body through a pipe as it's sent, so the
+// file is never held in memory in full.
+func (s MediaServiceOp) uploadFile(ctx context.Context, method, suffix string, values map[string]io.Reader, out any, opts MediaOptions) (Response, error) {
+	if opts.Collection == "" {
+		opts.Collection = "media"
+	}
 
-	// Close the multipart writer
-	if err := w.Close(); err != nil {
-		return Response{}, fmt.Errorf("failed to close multipart writer: %v", err)
+	fields, file, err := multipartValues(values, opts.FileName, opts.ContentType)
+	if err != nil {
+		return Response{}, err
+	}
+	if err := opts.Constraints.check(file); err != nil {
+		return Response{}, err
 	}
 
-	p := fmt.Sprintf("/api/%s", opts.Collection)
-	req, err := s.Client.NewFormRequest(ctx, http.MethodPost, p, &b, w.FormDataContentType())
+	boundary := multipart.NewWriter(io.Discard).Boundary()
+	size, err := multipartSize(boundary, fields, file)
 	if err != nil {
 		return Response{}, err
 	}
 
-	return s.Client.DoWithRequest(ctx, req, out)
-}
+	pr, pw := io.Pipe()
+	w := multipart.NewWriter(pw)
+	if err := w.SetBoundary(boundary); err != nil {
+		return Response{}, err
+	}
 
-func fileUploadValues(r io.Reader, v any) (map[string]io.Reader, error) {
-	if r == nil {
-		return nil, fmt.Errorf("file is required")
+	var body io.Reader = pr
+	if opts.Progress != nil {
+		body = &progressReader{r: pr, total: size, fn: opts.Progress}
 	}
 
-	// Marshal the `in` struct to JSON for the _payload field
-	payloadJSON, err := json.Marshal(v)
+	p := fmt.Sprintf("/api/%s%s", opts.Collection, suffix)
+	req, err := s.Client.NewFormRequest(ctx, method, p, body, w.FormDataContentType())
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal input struct: %v", err)
+		return Response{}, err
+	}
+	req.ContentLength = size
+
+	written := make(chan error, 1)
+	go func() {
+		err := writeMultipart(w, fields, file)
+		pw.CloseWithError(err)
+		written <- err
+	}()
+
+	// Documents of upload collections are unwrapped from the response.
+	doc, unwrap := out.(mediaDocument)
+	if unwrap {
+		out = nil
 	}
 
-	values := map[string]io.Reader{
-		"file":     r,
-		"_payload": strings.NewReader(string(payloadJSON)), // The Payload CMS structure
+	resp, err := s.Client.DoWithRequest(ctx, req, out)
+	if err == nil && unwrap {
+		err = decodeMediaDoc(resp.Content, doc)
 	}
 
-	return values, nil
+	// Closing the reader unblocks the writer if the request finished
+	// before the body was read in full.
+	_ = pr.Close()
+
+	var werr error
+	select {
+	case werr = <-written:
+	case <-ctx.Done():
+		// The writer may be blocked reading the file, so it's left to
+		// finish in the background.
+	}
+
+	switch {
+	case err == nil:
+		if method == http.MethodPost {
+			s.Client.cache.invalidateLists(opts.Collection)
+		}
+		return resp, nil
+	case ctx.Err() != nil:
+		return resp, fmt.Errorf("%w: %w", ErrUploadCanceled, ctx.Err())
+	case werr != nil && !errors.Is(werr, io.ErrClosedPipe):
+		return resp, fmt.Errorf("failed to write upload: %w", werr)
+	}
+
+	return resp, err
 }
 
-// / handleReaderUpload adds a reader's content to the multipart writer with proper MIME type detection
-func handleReaderUpload(w *multipart.Writer, key string, r io.Reader, fileName string) error {
-	// Buffer the beginning of the file for MIME detection
-	var buf bytes.Buffer
-	tee := io.TeeReader(r, &buf)
+// ErrUploadCanceled is returned when the context of an upload is canceled
+// or times out before the upload completes. The error returned also wraps
+// the error of the context.
+var ErrUploadCanceled = errors.New("upload canceled")
+
+// progressReader reports the number of bytes read from r.
+type progressReader struct {
+	r     io.Reader
+	sent  int64
+	total int64
+	fn    func(sent, total int64)
+}
 
-	// Detect MIME type
-	mime, err := mimetype.DetectReader(tee)
-	if err != nil {
-		return fmt.Errorf("failed to detect mime type: %v", err)
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	if n > 0 {
+		p.sent += int64(n)
+		p.fn(p.sent, p.total)
 	}
+	return n, err
+}
+
+// sniffLen is the number of bytes read from the start of a file to
+// detect its MIME type, which is the limit mimetype reads by default.
+const sniffLen = 3072
+
+// formField is a non file field of a multipart upload.
+type formField struct {
+	name  string
+	value []byte
+}
+
+// filePart is the file of a multipart upload. The start of the file has
+// been read into head to detect its MIME type, and the remainder is
+// read from rest.
+type filePart struct {
+	name string
+	mime string
+	head []byte
+	rest io.Reader
+	// size is the size of the file in bytes, or -1 if unknown.
+	size int64
+}
 
+// multipartValues splits the values of an upload into its form fields,
+// sorted by name, and the file.
+func multipartValues(values map[string]io.Reader, fileName, contentType string) ([]formField, *filePart, error) {
+	var (
+		fields []formField
+		file   *filePart
+	)
+	for key, r := range values {
+		if key == "file" {
+			f, err := newFilePart(r, fileName, contentType)
+			if err != nil {
+				return nil, nil, err
+			}
+			file = f
+			continue
+		}
+
+		value, err := io.ReadAll(r)
+		if err != nil {
+			return nil, nil, err
+		}
+		fields = append(fields, formField{name: key, value: value})
+	}
+	if file == nil {
+		return nil, nil, errors.New("file is required")
+	}
+
+	sort.Slice(fields, func(i, j int) bool {
+		return fields[i].name < fields[j].name
+	})
+
+	return fields, file, nil
+}
+
+// newFilePart reads the start of r to detect the MIME type, which is used
+// for the extension of the filename. When the content type is given, the
+// type isn't detected and the filename is used as it is, adding the
+// extension of the type only if it has none.
+func newFilePart(r io.Reader, fileName, contentType string) (*filePart, error) {
 	// If no filename is provided, generate one with the correct extension
 	if fileName == "" {
-		return errors.New("no filename provided")
+		return nil, errors.New("no filename provided")
+	}
+
+	size := readerSize(r)
+
+	if contentType != "" {
+		if filepath.Ext(fileName) == "" {
+			if known := mimetype.Lookup(contentType); known != nil {
+				fileName += known.Extension()
+			}
+		}
+		return &filePart{
+			name: fileName,
+			mime: contentType,
+			rest: r,
+			size: size,
+		}, nil
 	}
 
 	// Check if the filename already has an extension
 	if ext := filepath.Ext(fileName); ext != "" {
-		return fmt.Errorf("filename should not include extension, got: %s", ext)
+		return nil, fmt.Errorf("filename should not include extension unless ContentType is set, got: %s", ext)
+	}
+
+	head := make([]byte, sniffLen)
+	n, err := io.ReadFull(r, head)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil, fmt.Errorf("failed to detect mime type: %w", err)
+	}
+	head = head[:n]
+	mime := mimetype.Detect(head)
+
+	return &filePart{
+		name: fileName + mime.Extension(),
+		mime: mime.String(),
+		head: head,
+		rest: r,
+		size: size,
+	}, nil
+}
+
+// readerSize returns the number of bytes remaining in r, or -1 if it
+// can't be determined without reading it.
+func readerSize(r io.Reader) int64 {
+	switch v := r.(type) {
+	case *sizedReader:
+		return v.size
+	case interface{ Len() int }:
+		return int64(v.Len())
+	case io.Seeker:
+		cur, err := v.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return -1
+		}
+		end, err := v.Seek(0, io.SeekEnd)
+		if err != nil {
+			return -1
+		}
+		if _, err := v.Seek(cur, io.SeekStart); err != nil {
+			return -1
+		}
+		return end - cur
 	}
+	return -1
+}
 
-	fileName = fileName + mime.Extension()
+// writeMultipart writes the form fields followed by the file to w, and
+// closes it.
+func writeMultipart(w *multipart.Writer, fields []formField, file *filePart) error {
+	for _, f := range fields {
+		fw, err := w.CreateFormField(f.name)
+		if err != nil {
+			return err
+		}
+		if _, err := fw.Write(f.value); err != nil {
+			return err
+		}
+	}
 
 	// Create the form part with the detected MIME type
 	h := textproto.MIMEHeader{}
-	h.Set("Content-Type", mime.String())
-	h.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`, key, fileName))
+	h.Set("Content-Type", file.mime)
+	h.Set("Content-Disposition", fmt.Sprintf(`form-data; name="file"; filename="%s"`, file.name))
 
 	fw, err := w.CreatePart(h)
 	if err != nil {
 		return err
 	}
 
-	// Write the buffered content first
-	if _, err := io.Copy(fw, &buf); err != nil {
+	// Write the buffered content first, then the rest of the file
+	if _, err := fw.Write(file.head); err != nil {
 		return err
 	}
+	if file.rest != nil {
+		if _, err := io.Copy(fw, file.rest); err != nil {
+			return err
+		}
+	}
 
-	// Then write the rest of the content
-	_, err = io.Copy(fw, r)
-	return err
+	return w.Close()
 }
 
-func fileNameFromURL(url string) string {
-	parts := strings.Split(url, "/")
+// multipartSize returns the length of the multipart body, by writing the
+// form without the content of the file, or -1 if the size of the file
+// is unknown.
+func multipartSize(boundary string, fields []formField, file *filePart) (int64, error) {
+	if file.size < 0 {
+		return -1, nil
+	}
+
+	var c countWriter
+	w := multipart.NewWriter(&c)
+	if err := w.SetBoundary(boundary); err != nil {
+		return 0, err
+	}
+	empty := *file
+	empty.head, empty.rest = nil, nil
+	if err := writeMultipart(w, fields, &empty); err != nil {
+		return 0, err
+	}
+
+	return c.n + file.size, nil
+}
+
+// countWriter counts the bytes written to it.
+type countWriter struct {
+	n int64
+}
+
+func (c *countWriter) Write(p []byte) (int, error) {
+	c.n += int64(len(p))
+	return len(p), nil
+}
+
+func fileUploadValues(r io.Reader, v any) (map[string]io.Reader, error) {
+	if r == nil {
+		return nil, fmt.Errorf("file is required")
+	}
+
+	// Marshal the `in` struct to JSON for the _payload field
+	payloadJSON, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal input struct: %v", err)
+	}
+
+	values := map[string]io.Reader{
+		"file":     r,
+		"_payload": strings.NewReader(string(payloadJSON)), // The Payload CMS structure
+	}
+
+	return values, nil
+}
+
+// fileNameFromDisposition returns the filename of a Content-Disposition
+// header, without any directories.
+func fileNameFromDisposition(header string) string {
+	if header == "" {
+		return ""
+	}
+	_, params, err := mime.ParseMediaType(header)
+	if err != nil {
+		return ""
+	}
+	name := path.Base(strings.ReplaceAll(params["filename"], `\`, "/"))
+	if name == "." || name == "/" {
+		return ""
+	}
+	return name
+}
+
+// fileNameFromURL returns the last segment of the path of the URL if it
+// contains an extension, ignoring any query string or fragment.
+func fileNameFromURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
 
-	// Get the last part of the URL which contains the filename
-	filenameWithExtension := parts[len(parts)-1]
+	// Get the last part of the path which contains the filename
+	name := path.Base(u.Path)
 
 	// Check if the last part contains a dot indicating an extension
-	if strings.Contains(filenameWithExtension, ".") {
-		// Extract the filename from filenameWithExtension
-		filename := path.Base(filenameWithExtension)
-		return filename
+	if name == "." || !strings.Contains(name, ".") {
+		return ""
 	}
 
-	// If no dot is found, return an empty string
-	return ""
+	return name
 }
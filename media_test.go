@@ -5,12 +5,22 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
+	"io/fs"
+	"mime"
 	"mime/multipart"
 	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 type mediaFields struct {
@@ -147,9 +157,305 @@ func TestMediaService_Upload(t *testing.T) {
 	})
 }
 
+// uploadedFile returns a client that records the name, content type and
+// content of each uploaded file.
+func uploadedFile(t *testing.T) (*Client, func() (name, contentType, content string)) {
+	t.Helper()
+
+	var (
+		mu                         sync.Mutex
+		name, contentType, content string
+	)
+	client, teardown := Setup(t, func(w http.ResponseWriter, r *http.Request) {
+		file, header, err := r.FormFile("file")
+		require.NoError(t, err)
+		defer file.Close()
+		buf, err := io.ReadAll(file)
+		require.NoError(t, err)
+
+		mu.Lock()
+		name, contentType, content = header.Filename, header.Header.Get("Content-Type"), string(buf)
+		mu.Unlock()
+
+		_, err = w.Write(defaultBody)
+		AssertNoError(t, err)
+	})
+	t.Cleanup(teardown)
+
+	return client, func() (string, string, string) {
+		mu.Lock()
+		defer mu.Unlock()
+		return name, contentType, content
+	}
+}
+
+func TestMediaService_ContentType(t *testing.T) {
+	t.Parallel()
+
+	svg := `<svg xmlns="http://www.w3.org/2000/svg" width="10" height="10"></svg>`
+
+	tt := map[string]struct {
+		opts            MediaOptions
+		wantName        string
+		wantContentType string
+	}{
+		"Keeps Extension": {
+			opts:            MediaOptions{FileName: "logo.svg", ContentType: "image/svg+xml"},
+			wantName:        "logo.svg",
+			wantContentType: "image/svg+xml",
+		},
+		"Adds Extension": {
+			opts:            MediaOptions{FileName: "logo", ContentType: "image/svg+xml"},
+			wantName:        "logo.svg",
+			wantContentType: "image/svg+xml",
+		},
+		"Unknown Type": {
+			opts:            MediaOptions{FileName: "logo", ContentType: "application/x-custom"},
+			wantName:        "logo",
+			wantContentType: "application/x-custom",
+		},
+		"Overrides Detected Type": {
+			opts:            MediaOptions{FileName: "data.csv", ContentType: "text/csv"},
+			wantName:        "data.csv",
+			wantContentType: "text/csv",
+		},
+	}
+
+	for name, test := range tt {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			client, uploaded := uploadedFile(t)
+			m := MediaServiceOp{Client: client}
+			_, err := m.Upload(context.TODO(), strings.NewReader(svg), nil, nil, test.opts)
+			require.NoError(t, err)
+
+			gotName, gotType, gotContent := uploaded()
+			AssertEqual(t, test.wantName, gotName)
+			AssertEqual(t, test.wantContentType, gotType)
+			AssertEqual(t, svg, gotContent)
+		})
+	}
+}
+
+func TestMediaService_UploadFile(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "data.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"a": 1}`), 0o600))
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+
+		client, uploaded := uploadedFile(t)
+		m := MediaServiceOp{Client: client}
+		_, err := m.UploadFile(context.TODO(), path, mediaData, nil, MediaOptions{})
+		require.NoError(t, err)
+
+		name, contentType, content := uploaded()
+		AssertEqual(t, "data.json", name)
+		AssertEqual(t, "application/json", contentType)
+		AssertEqual(t, `{"a": 1}`, content)
+	})
+
+	t.Run("FileName", func(t *testing.T) {
+		t.Parallel()
+
+		client, uploaded := uploadedFile(t)
+		m := MediaServiceOp{Client: client}
+		_, err := m.UploadFile(context.TODO(), path, mediaData, nil, MediaOptions{FileName: "settings"})
+		require.NoError(t, err)
+
+		name, contentType, _ := uploaded()
+		AssertEqual(t, "settings.json", name)
+		AssertEqual(t, "application/json", contentType)
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		t.Parallel()
+
+		m := MediaServiceOp{Client: noUpload(t)}
+		_, err := m.UploadFile(context.TODO(), filepath.Join(dir, "missing.png"), mediaData, nil, MediaOptions{})
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	})
+}
+
+func TestMediaService_UploadFS(t *testing.T) {
+	t.Parallel()
+
+	fsys := fstest.MapFS{
+		"images/logo.svg": {Data: []byte(`<svg xmlns="http://www.w3.org/2000/svg"></svg>`)},
+		"docs/notes.zzz":  {Data: []byte("Payload File")},
+		"docs/data.csv":   {Data: []byte("id,title\n1,Payload\n2,CMS\n")},
+		"docs/data.json":  {Data: []byte(`{"title":"Payload"}`)},
+		"images/logo.jpg": {Data: []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")},
+	}
+
+	tt := map[string]struct {
+		name            string
+		opts            MediaOptions
+		wantName        string
+		wantContentType string
+	}{
+		"Type From Extension": {
+			name:            "images/logo.svg",
+			wantName:        "logo.svg",
+			wantContentType: "image/svg+xml",
+		},
+		"CSV": {
+			name:            "docs/data.csv",
+			wantName:        "data.csv",
+			wantContentType: "text/csv",
+		},
+		"JSON": {
+			name:            "docs/data.json",
+			wantName:        "data.json",
+			wantContentType: "application/json",
+		},
+		"Mismatched Extension Detected": {
+			name:            "images/logo.jpg",
+			wantName:        "logo.png",
+			wantContentType: "image/png",
+		},
+		"Unknown Extension Detected": {
+			name:            "docs/notes.zzz",
+			wantName:        "notes.txt",
+			wantContentType: "text/plain; charset=utf-8",
+		},
+		"Explicit Content Type": {
+			name:            "docs/notes.zzz",
+			opts:            MediaOptions{ContentType: "text/markdown"},
+			wantName:        "notes.zzz",
+			wantContentType: "text/markdown",
+		},
+	}
+
+	for name, test := range tt {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			client, uploaded := uploadedFile(t)
+			m := MediaServiceOp{Client: client}
+			_, err := m.UploadFS(context.TODO(), fsys, test.name, nil, nil, test.opts)
+			require.NoError(t, err)
+
+			gotName, gotType, _ := uploaded()
+			AssertEqual(t, test.wantName, gotName)
+			AssertEqual(t, test.wantContentType, gotType)
+		})
+	}
+
+	t.Run("Not Found", func(t *testing.T) {
+		t.Parallel()
+
+		m := MediaServiceOp{Client: noUpload(t)}
+		_, err := m.UploadFS(context.TODO(), fsys, "missing.png", nil, nil, MediaOptions{})
+		assert.ErrorIs(t, err, fs.ErrNotExist)
+	})
+}
+
+func TestMediaService_Replace(t *testing.T) {
+	t.Parallel()
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+
+		client, teardown := Setup(t, func(w http.ResponseWriter, r *http.Request) {
+			AssertEqual(t, http.MethodPatch, r.Method)
+			AssertEqual(t, "/api/uploads/123", r.URL.Path)
+
+			file, header, err := r.FormFile("file")
+			require.NoError(t, err)
+			defer file.Close()
+			content, err := io.ReadAll(file)
+			require.NoError(t, err)
+
+			AssertEqual(t, "filename.txt", header.Filename)
+			AssertEqual(t, "Payload File", string(content))
+			AssertEqual(t, `{"alt":"John Doe","caption":"Hello World","NoTag":"No Tag"}`, r.FormValue("_payload"))
+
+			_, err = w.Write(defaultBody)
+			AssertNoError(t, err)
+		})
+		defer teardown()
+
+		m := MediaServiceOp{Client: client}
+		r, err := m.Replace(context.TODO(), 123, strings.NewReader("Payload File"), mediaData, nil, MediaOptions{
+			Collection: "uploads",
+			FileName:   "filename",
+		})
+		AssertNoError(t, err)
+		AssertEqual(t, string(defaultBody), string(r.Content))
+	})
+
+	t.Run("File Only", func(t *testing.T) {
+		t.Parallel()
+
+		client, teardown := Setup(t, func(w http.ResponseWriter, r *http.Request) {
+			AssertEqual(t, "/api/media/abc", r.URL.Path)
+			require.NoError(t, r.ParseMultipartForm(1<<20))
+			_, ok := r.MultipartForm.Value["_payload"]
+			AssertEqual(t, false, ok)
+			_, err := w.Write(defaultBody)
+			AssertNoError(t, err)
+		})
+		defer teardown()
+
+		m := MediaServiceOp{Client: client}
+		_, err := m.Replace(context.TODO(), "abc", strings.NewReader("Payload File"), nil, nil, MediaOptions{
+			FileName: "filename",
+		})
+		AssertNoError(t, err)
+	})
+
+	t.Run("File Required", func(t *testing.T) {
+		t.Parallel()
+
+		m := MediaServiceOp{Client: noUpload(t)}
+		_, err := m.Replace(context.TODO(), 1, nil, mediaData, nil, MediaOptions{FileName: "filename"})
+		AssertError(t, err)
+	})
+
+	t.Run("Client Error", func(t *testing.T) {
+		t.Parallel()
+
+		client, teardown := Setup(t, func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		})
+		defer teardown()
+
+		m := MediaServiceOp{Client: client}
+		_, err := m.Replace(context.TODO(), 1, strings.NewReader("Payload File"), mediaData, nil, MediaOptions{
+			FileName: "filename",
+		})
+		AssertError(t, err)
+	})
+}
+
+// downloadServer serves files for UploadFromURL to download.
+func downloadServer(t *testing.T, handler http.HandlerFunc) string {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return server.URL
+}
+
+// noUpload returns a client that fails the test if anything is uploaded.
+func noUpload(t *testing.T) *Client {
+	t.Helper()
+	client, teardown := Setup(t, func(_ http.ResponseWriter, _ *http.Request) {
+		t.Error("unexpected upload")
+	})
+	t.Cleanup(teardown)
+	return client
+}
+
 func TestMediaService_UploadFromURL(t *testing.T) {
 	t.Parallel()
 
+	png := []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
 	t.Run("Client Error", func(t *testing.T) {
 		t.Parallel()
 
@@ -158,28 +464,276 @@ func TestMediaService_UploadFromURL(t *testing.T) {
 		})
 		defer teardown()
 
+		url := downloadServer(t, func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte("Payload File"))
+		})
+
 		m := MediaServiceOp{Client: client}
-		_, err := m.UploadFromURL(context.TODO(), "https://example.com", nil, nil, MediaOptions{})
+		_, err := m.UploadFromURL(context.TODO(), url+"/file.txt", nil, nil, MediaOptions{})
 		AssertError(t, err)
 	})
 
 	t.Run("OK", func(t *testing.T) {
 		t.Parallel()
 
-		client, teardown := Setup(t, func(w http.ResponseWriter, _ *http.Request) {
-			w.WriteHeader(http.StatusOK)
-			_, err := w.Write(defaultBody)
-			AssertNoError(t, err)
+		client, uploaded := uploadedFile(t)
+
+		url := downloadServer(t, func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte("Payload File"))
 		})
-		defer teardown()
 
 		m := MediaServiceOp{Client: client}
-		r, err := m.UploadFromURL(context.TODO(), "https://example.com", nil, nil, MediaOptions{
+		r, err := m.UploadFromURL(context.TODO(), url, nil, nil, MediaOptions{
 			FileName: "filename",
 		})
 		AssertNoError(t, err)
-		AssertEqual(t, string(r.Content), string(defaultBody))
+		AssertEqual(t, string(defaultBody), string(r.Content))
+		name, _, content := uploaded()
+		AssertEqual(t, "filename.txt", name)
+		AssertEqual(t, "Payload File", content)
+	})
+
+	t.Run("Any 2xx Status", func(t *testing.T) {
+		t.Parallel()
+
+		client, _ := uploadedFile(t)
+
+		url := downloadServer(t, func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusNonAuthoritativeInfo)
+			_, _ = w.Write([]byte("Payload File"))
+		})
+
+		m := MediaServiceOp{Client: client}
+		_, err := m.UploadFromURL(context.TODO(), url+"/file.txt", nil, nil, MediaOptions{})
+		AssertNoError(t, err)
+	})
+
+	t.Run("Download Status Error", func(t *testing.T) {
+		t.Parallel()
+
+		url := downloadServer(t, func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		})
+
+		m := MediaServiceOp{Client: noUpload(t)}
+		_, err := m.UploadFromURL(context.TODO(), url+"/file.txt", nil, nil, MediaOptions{})
+		AssertError(t, err)
+		AssertContains(t, err.Error(), "status code 404")
+	})
+
+	t.Run("Context", func(t *testing.T) {
+		t.Parallel()
+
+		url := downloadServer(t, func(_ http.ResponseWriter, _ *http.Request) {
+			t.Error("unexpected download")
+		})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		m := MediaServiceOp{Client: noUpload(t)}
+		_, err := m.UploadFromURL(ctx, url+"/file.txt", nil, nil, MediaOptions{})
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("Download Client", func(t *testing.T) {
+		t.Parallel()
+
+		client, _ := uploadedFile(t)
+
+		var called bool
+		url := downloadServer(t, func(w http.ResponseWriter, r *http.Request) {
+			AssertEqual(t, "downloader", r.Header.Get("User-Agent"))
+			_, _ = w.Write([]byte("Payload File"))
+		})
+
+		m := MediaServiceOp{Client: client}
+		_, err := m.UploadFromURL(context.TODO(), url+"/file.txt", nil, nil, MediaOptions{
+			Download: DownloadOptions{
+				Client: &http.Client{Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+					called = true
+					r.Header.Set("User-Agent", "downloader")
+					return http.DefaultTransport.RoundTrip(r)
+				})},
+			},
+		})
+		AssertNoError(t, err)
+		AssertEqual(t, true, called)
+	})
+
+	t.Run("Filename", func(t *testing.T) {
+		t.Parallel()
+
+		tt := map[string]struct {
+			path        string
+			disposition string
+			want        string
+		}{
+			"URL Path": {
+				path: "/images/photo.png",
+				want: "photo.png",
+			},
+			"Query Ignored": {
+				path: "/images/photo.png?w=100&name=other.jpg",
+				want: "photo.png",
+			},
+			"Content-Disposition": {
+				path:        "/download?id=1",
+				disposition: `attachment; filename="report.png"`,
+				want:        "report.png",
+			},
+			"Content-Disposition Before URL": {
+				path:        "/images/photo.png",
+				disposition: `attachment; filename="report.png"`,
+				want:        "report.png",
+			},
+			"Content-Disposition Directories": {
+				path:        "/download",
+				disposition: `attachment; filename="../../etc/report.png"`,
+				want:        "report.png",
+			},
+		}
+
+		for name, test := range tt {
+			t.Run(name, func(t *testing.T) {
+				t.Parallel()
+
+				client, uploaded := uploadedFile(t)
+
+				url := downloadServer(t, func(w http.ResponseWriter, _ *http.Request) {
+					if test.disposition != "" {
+						w.Header().Set("Content-Disposition", test.disposition)
+					}
+					_, _ = w.Write(png)
+				})
+
+				m := MediaServiceOp{Client: client}
+				_, err := m.UploadFromURL(context.TODO(), url+test.path, nil, nil, MediaOptions{})
+				AssertNoError(t, err)
+				got, contentType, _ := uploaded()
+				AssertEqual(t, test.want, got)
+				AssertEqual(t, "image/png", contentType)
+			})
+		}
 	})
+
+	t.Run("No Filename", func(t *testing.T) {
+		t.Parallel()
+
+		url := downloadServer(t, func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write(png)
+		})
+
+		m := MediaServiceOp{Client: noUpload(t)}
+		_, err := m.UploadFromURL(context.TODO(), url+"/download?file=photo.png", nil, nil, MediaOptions{})
+		AssertError(t, err)
+		AssertContains(t, err.Error(), "no filename provided")
+	})
+
+	t.Run("Max Bytes", func(t *testing.T) {
+		t.Parallel()
+
+		content := bytes.Repeat([]byte("a"), 100)
+
+		t.Run("Content-Length", func(t *testing.T) {
+			t.Parallel()
+
+			url := downloadServer(t, func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = w.Write(content)
+			})
+
+			m := MediaServiceOp{Client: noUpload(t)}
+			_, err := m.UploadFromURL(context.TODO(), url+"/file.txt", nil, nil, MediaOptions{
+				Download: DownloadOptions{MaxBytes: 99},
+			})
+			assert.ErrorIs(t, err, ErrDownloadTooLarge)
+		})
+
+		t.Run("Chunked", func(t *testing.T) {
+			t.Parallel()
+
+			client, teardown := Setup(t, func(_ http.ResponseWriter, r *http.Request) {
+				_, _ = io.Copy(io.Discard, r.Body)
+			})
+			defer teardown()
+
+			url := downloadServer(t, func(w http.ResponseWriter, _ *http.Request) {
+				for _, b := range content {
+					_, _ = w.Write([]byte{b})
+					w.(http.Flusher).Flush()
+				}
+			})
+
+			m := MediaServiceOp{Client: client}
+			_, err := m.UploadFromURL(context.TODO(), url+"/file.txt", nil, nil, MediaOptions{
+				Download: DownloadOptions{MaxBytes: 99},
+			})
+			assert.ErrorIs(t, err, ErrDownloadTooLarge)
+		})
+
+		t.Run("At Limit", func(t *testing.T) {
+			t.Parallel()
+
+			client, uploaded := uploadedFile(t)
+
+			url := downloadServer(t, func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = w.Write(content)
+			})
+
+			m := MediaServiceOp{Client: client}
+			_, err := m.UploadFromURL(context.TODO(), url+"/file.txt", nil, nil, MediaOptions{
+				Download: DownloadOptions{MaxBytes: 100},
+			})
+			AssertNoError(t, err)
+			_, _, got := uploaded()
+			AssertEqual(t, string(content), got)
+		})
+	})
+
+	t.Run("Allowed Types", func(t *testing.T) {
+		t.Parallel()
+
+		tt := map[string]struct {
+			allowed []string
+			wantErr bool
+		}{
+			"Exact":     {allowed: []string{"image/png"}},
+			"Wildcard":  {allowed: []string{"application/pdf", "image/*"}},
+			"Not Match": {allowed: []string{"image/jpeg", "text/*"}, wantErr: true},
+		}
+
+		for name, test := range tt {
+			t.Run(name, func(t *testing.T) {
+				t.Parallel()
+
+				client, uploaded := uploadedFile(t)
+
+				// The Content-Type header is ignored in favour of the content.
+				url := downloadServer(t, func(w http.ResponseWriter, _ *http.Request) {
+					w.Header().Set("Content-Type", "text/plain")
+					_, _ = w.Write(png)
+				})
+
+				m := MediaServiceOp{Client: client}
+				_, err := m.UploadFromURL(context.TODO(), url+"/photo.png", nil, nil, MediaOptions{
+					Download: DownloadOptions{AllowedTypes: test.allowed},
+				})
+				if test.wantErr {
+					assert.ErrorIs(t, err, ErrDownloadTypeNotAllowed)
+					return
+				}
+				AssertNoError(t, err)
+				_, _, content := uploaded()
+				AssertEqual(t, string(png), content)
+			})
+		}
+	})
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
 }
 
 func TestGetUploadValues(t *testing.T) {
@@ -230,7 +784,124 @@ func (m mockErrWriter) Write(_ []byte) (n int, err error) {
 	return 0, errors.New("mock write error")
 }
 
-func TestHandleReaderUpload(t *testing.T) {
+// errAfterReader returns the content and then fails, to simulate a
+// file that can't be read in full.
+type errAfterReader struct {
+	r io.Reader
+}
+
+func (e *errAfterReader) Read(p []byte) (int, error) {
+	n, err := e.r.Read(p)
+	if errors.Is(err, io.EOF) {
+		return n, errors.New("read error")
+	}
+	return n, err
+}
+
+func TestMediaService_UploadStreaming(t *testing.T) {
+	t.Parallel()
+
+	content := bytes.Repeat([]byte("Payload File "), 1000)
+
+	tt := map[string]struct {
+		reader        func(t *testing.T) io.Reader
+		wantKnownSize bool
+	}{
+		"Buffer": {
+			reader: func(_ *testing.T) io.Reader {
+				return bytes.NewReader(content)
+			},
+			wantKnownSize: true,
+		},
+		"File": {
+			reader: func(t *testing.T) io.Reader {
+				t.Helper()
+				file, clean, err := createTestFile(t, content)
+				t.Cleanup(clean)
+				AssertNoError(t, err)
+				return file
+			},
+			wantKnownSize: true,
+		},
+		"Unknown Size": {
+			reader: func(_ *testing.T) io.Reader {
+				return io.MultiReader(bytes.NewReader(content))
+			},
+			wantKnownSize: false,
+		},
+	}
+
+	for name, test := range tt {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			client, teardown := Setup(t, func(w http.ResponseWriter, r *http.Request) {
+				body, err := io.ReadAll(r.Body)
+				AssertNoError(t, err)
+
+				if test.wantKnownSize {
+					AssertEqual(t, int64(len(body)), r.ContentLength)
+				} else {
+					AssertEqual(t, int64(-1), r.ContentLength)
+				}
+
+				_, params, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+				AssertNoError(t, err)
+				form, err := multipart.NewReader(bytes.NewReader(body), params["boundary"]).ReadForm(1 << 20)
+				AssertNoError(t, err)
+
+				AssertEqual(t, `{"alt":"John Doe","caption":"Hello World","NoTag":"No Tag"}`, form.Value["_payload"][0])
+				AssertEqual(t, "filename.txt", form.File["file"][0].Filename)
+				AssertEqual(t, int64(len(content)), form.File["file"][0].Size)
+
+				_, err = w.Write(defaultBody)
+				AssertNoError(t, err)
+			})
+			defer teardown()
+
+			m := MediaServiceOp{Client: client}
+			r, err := m.Upload(context.TODO(), test.reader(t), mediaData, nil, MediaOptions{
+				FileName: "filename",
+			})
+			AssertNoError(t, err)
+			AssertEqual(t, string(defaultBody), string(r.Content))
+		})
+	}
+
+	t.Run("Read Error", func(t *testing.T) {
+		t.Parallel()
+
+		client, teardown := Setup(t, func(w http.ResponseWriter, r *http.Request) {
+			_, _ = io.Copy(io.Discard, r.Body)
+			w.WriteHeader(http.StatusOK)
+		})
+		defer teardown()
+
+		m := MediaServiceOp{Client: client}
+		_, err := m.Upload(context.TODO(), &errAfterReader{r: bytes.NewReader(content)}, mediaData, nil, MediaOptions{
+			FileName: "filename",
+		})
+		AssertError(t, err)
+		AssertContains(t, err.Error(), "read error")
+	})
+
+	t.Run("Server Closes Early", func(t *testing.T) {
+		t.Parallel()
+
+		client, teardown := Setup(t, func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusRequestEntityTooLarge)
+		})
+		defer teardown()
+
+		m := MediaServiceOp{Client: client}
+		_, err := m.Upload(context.TODO(), io.MultiReader(bytes.NewReader(bytes.Repeat(content, 100))), mediaData, nil, MediaOptions{
+			FileName: "filename",
+		})
+		AssertError(t, err)
+	})
+}
+
+func TestNewFilePart(t *testing.T) {
 	t.Parallel()
 
 	t.Run("File Closed", func(t *testing.T) {
@@ -241,10 +912,39 @@ func TestHandleReaderUpload(t *testing.T) {
 		AssertNoError(t, err)
 
 		AssertNoError(t, f.Close())
-		err = handleReaderUpload(nil, "file", f, "filename")
+		_, err = newFilePart(f, "filename", "")
 		AssertError(t, err)
 	})
 
+	t.Run("Bounded Peek", func(t *testing.T) {
+		t.Parallel()
+
+		content := bytes.Repeat([]byte("a"), sniffLen*4)
+		part, err := newFilePart(io.MultiReader(bytes.NewReader(content)), "filename", "")
+		AssertNoError(t, err)
+		AssertEqual(t, sniffLen, len(part.head))
+		AssertEqual(t, int64(-1), part.size)
+
+		rest, err := io.ReadAll(part.rest)
+		AssertNoError(t, err)
+		AssertEqual(t, len(content)-sniffLen, len(rest))
+	})
+
+	t.Run("Size From Offset", func(t *testing.T) {
+		t.Parallel()
+
+		r := strings.NewReader("Payload File")
+		_, err := r.Seek(8, io.SeekStart)
+		AssertNoError(t, err)
+		part, err := newFilePart(struct{ io.ReadSeeker }{r}, "filename", "")
+		AssertNoError(t, err)
+		AssertEqual(t, int64(4), part.size)
+	})
+}
+
+func TestWriteMultipart(t *testing.T) {
+	t.Parallel()
+
 	t.Run("Error", func(t *testing.T) {
 		t.Parallel()
 
@@ -252,8 +952,11 @@ func TestHandleReaderUpload(t *testing.T) {
 		defer teardown()
 		AssertNoError(t, err)
 
+		part, err := newFilePart(f, "filename", "")
+		AssertNoError(t, err)
+
 		w := multipart.NewWriter(&mockErrWriter{})
-		err = handleReaderUpload(w, "file", f, "filename")
+		err = writeMultipart(w, nil, part)
 		AssertError(t, err)
 	})
 
@@ -264,14 +967,17 @@ func TestHandleReaderUpload(t *testing.T) {
 		defer teardown()
 		AssertNoError(t, err)
 
+		part, err := newFilePart(tempFile, "filename", "")
+		AssertNoError(t, err)
+
 		body := &bytes.Buffer{}
 		writer := multipart.NewWriter(body)
 
-		err = handleReaderUpload(writer, "file", tempFile, "filename")
+		err = writeMultipart(writer, []formField{{name: "_payload", value: []byte("{}")}}, part)
 		AssertNoError(t, err)
-		AssertNoError(t, writer.Close())
 		AssertEqual(t, true, strings.Contains(body.String(), "text/plain; charset=utf-8"))
 		AssertEqual(t, true, strings.Contains(body.String(), "filename.txt"))
+		AssertEqual(t, true, strings.Index(body.String(), "_payload") < strings.Index(body.String(), "filename.txt"))
 	})
 }
 
@@ -292,6 +998,18 @@ func TestFileNameFromURL(t *testing.T) {
 			input: "https://example.com/",
 			want:  "", // Expecting empty string as no filename is present
 		},
+		"URL with query": {
+			input: "https://example.com/path/to/file.txt?v=1&name=other.jpg",
+			want:  "file.txt",
+		},
+		"URL with escaped path": {
+			input: "https://example.com/path/to/my%20file.txt#top",
+			want:  "my file.txt",
+		},
+		"Filename only in query": {
+			input: "https://example.com/download?file=file.txt",
+			want:  "",
+		},
 	}
 
 	for name, test := range tt {
@@ -301,3 +1019,285 @@ func TestFileNameFromURL(t *testing.T) {
 		})
 	}
 }
+
+func TestMediaService_UploadProgress(t *testing.T) {
+	t.Parallel()
+
+	content := bytes.Repeat([]byte("Payload File "), 10000)
+
+	tt := map[string]struct {
+		reader    io.Reader
+		wantTotal bool
+	}{
+		"Known Size":   {reader: bytes.NewReader(content), wantTotal: true},
+		"Unknown Size": {reader: io.MultiReader(bytes.NewReader(content)), wantTotal: false},
+	}
+
+	for name, test := range tt {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var length int64
+			client, teardown := Setup(t, func(w http.ResponseWriter, r *http.Request) {
+				n, err := io.Copy(io.Discard, r.Body)
+				AssertNoError(t, err)
+				length = n
+				_, _ = w.Write(defaultBody)
+			})
+			defer teardown()
+
+			var (
+				calls, last, total int64
+				backwards          bool
+			)
+			m := MediaServiceOp{Client: client}
+			_, err := m.Upload(context.TODO(), test.reader, mediaData, nil, MediaOptions{
+				FileName: "filename",
+				Progress: func(sent, t int64) {
+					backwards = backwards || sent < last
+					calls++
+					last, total = sent, t
+				},
+			})
+			AssertNoError(t, err)
+			AssertEqual(t, true, calls > 1)
+			AssertEqual(t, false, backwards)
+			AssertEqual(t, length, last)
+			if test.wantTotal {
+				AssertEqual(t, length, total)
+			} else {
+				AssertEqual(t, int64(-1), total)
+			}
+		})
+	}
+}
+
+func TestMediaService_UploadCancel(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Canceled Partway", func(t *testing.T) {
+		t.Parallel()
+
+		client, teardown := Setup(t, func(_ http.ResponseWriter, r *http.Request) {
+			_, _ = io.Copy(io.Discard, r.Body)
+		})
+		defer teardown()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		// The file never ends, so the upload only finishes if it's aborted.
+		pr, pw := io.Pipe()
+		go func() {
+			chunk := bytes.Repeat([]byte("a"), 1024)
+			for {
+				if _, err := pw.Write(chunk); err != nil {
+					return
+				}
+			}
+		}()
+		defer pr.Close()
+
+		m := MediaServiceOp{Client: client}
+		_, err := m.Upload(ctx, pr, mediaData, nil, MediaOptions{
+			FileName: "filename",
+			Progress: func(sent, _ int64) {
+				if sent > 1<<20 {
+					cancel()
+				}
+			},
+		})
+		AssertError(t, err)
+		AssertEqual(t, true, errors.Is(err, ErrUploadCanceled))
+		AssertEqual(t, true, errors.Is(err, context.Canceled))
+	})
+
+	t.Run("Deadline", func(t *testing.T) {
+		t.Parallel()
+
+		client, teardown := Setup(t, defaultHandler(t))
+		defer teardown()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 0)
+		defer cancel()
+
+		m := MediaServiceOp{Client: client}
+		_, err := m.Upload(ctx, strings.NewReader("Payload File"), mediaData, nil, MediaOptions{
+			FileName: "filename",
+		})
+		AssertEqual(t, true, errors.Is(err, ErrUploadCanceled))
+		AssertEqual(t, true, errors.Is(err, context.DeadlineExceeded))
+	})
+
+	t.Run("Server Error Is Not Canceled", func(t *testing.T) {
+		t.Parallel()
+
+		client, teardown := Setup(t, func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		})
+		defer teardown()
+
+		m := MediaServiceOp{Client: client}
+		_, err := m.Upload(context.Background(), strings.NewReader("Payload File"), mediaData, nil, MediaOptions{
+			FileName: "filename",
+		})
+		AssertError(t, err)
+		AssertEqual(t, false, errors.Is(err, ErrUploadCanceled))
+	})
+}
+
+func TestMediaService_Download(t *testing.T) {
+	t.Parallel()
+
+	const (
+		original = "original file"
+		thumb    = "thumbnail file"
+	)
+
+	// server serves a media document and its files, counting the
+	// downloads of each file.
+	server := func(t *testing.T, apiKey string) (*Client, map[string]int, *sync.Mutex) {
+		t.Helper()
+
+		var (
+			mu        sync.Mutex
+			downloads = map[string]int{}
+		)
+		client, teardown := Setup(t, func(w http.ResponseWriter, r *http.Request) {
+			AssertEqual(t, "users API-Key "+apiKey, r.Header.Get("Authorization"))
+
+			files := map[string]string{
+				"/api/media/file/cat.png":         original,
+				"/api/media/file/cat-300x225.png": thumb,
+			}
+			if content, ok := files[r.URL.Path]; ok {
+				etag := fmt.Sprintf("%q", content)
+				if r.Header.Get("If-None-Match") == etag {
+					w.WriteHeader(http.StatusNotModified)
+					return
+				}
+				mu.Lock()
+				downloads[r.URL.Path]++
+				mu.Unlock()
+				w.Header().Set("ETag", etag)
+				_, _ = w.Write([]byte(content))
+				return
+			}
+
+			switch r.URL.Path {
+			case "/api/media/1":
+				AssertEqual(t, "0", r.URL.Query().Get("depth"))
+				_, _ = fmt.Fprintf(w, `{"id": 1, "url": "/api/media/file/cat.png", "filesize": %d, "sizes": {
+					"thumbnail": {"url": "/api/media/file/cat-300x225.png", "filesize": %d},
+					"hero": {"url": null}
+				}}`, len(original), len(thumb))
+			case "/api/media/2":
+				_, _ = w.Write([]byte(`{"id": 2, "url": "/api/media/file/missing.png"}`))
+			default:
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte(`{"errors": [{"message": "Not Found"}]}`))
+			}
+		})
+		client.apiKey = apiKey
+		t.Cleanup(teardown)
+
+		return client, downloads, &mu
+	}
+
+	read := func(t *testing.T, rc io.ReadCloser, err error) string {
+		t.Helper()
+		require.NoError(t, err)
+		defer rc.Close()
+		buf, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		return string(buf)
+	}
+
+	t.Run("Original", func(t *testing.T) {
+		t.Parallel()
+		client, _, _ := server(t, "key")
+		m := MediaServiceOp{Client: client}
+		rc, err := m.Download(context.TODO(), "media", 1, "")
+		AssertEqual(t, original, read(t, rc, err))
+	})
+
+	t.Run("Size", func(t *testing.T) {
+		t.Parallel()
+		client, _, _ := server(t, "key")
+		m := MediaServiceOp{Client: client}
+		rc, err := m.Download(context.TODO(), "media", 1, "thumbnail")
+		AssertEqual(t, thumb, read(t, rc, err))
+	})
+
+	t.Run("Size Not Found", func(t *testing.T) {
+		t.Parallel()
+		client, _, _ := server(t, "key")
+		m := MediaServiceOp{Client: client}
+		_, err := m.Download(context.TODO(), "media", 1, "banner")
+		assert.ErrorIs(t, err, ErrSizeNotFound)
+	})
+
+	t.Run("Size Not Generated", func(t *testing.T) {
+		t.Parallel()
+		client, _, _ := server(t, "key")
+		m := MediaServiceOp{Client: client}
+		_, err := m.Download(context.TODO(), "media", 1, "hero")
+		AssertError(t, err)
+		AssertContains(t, err.Error(), "has no url")
+	})
+
+	t.Run("Document Not Found", func(t *testing.T) {
+		t.Parallel()
+		client, _, _ := server(t, "key")
+		m := MediaServiceOp{Client: client}
+		_, err := m.Download(context.TODO(), "media", 3, "")
+		AssertError(t, err)
+	})
+
+	t.Run("File Not Found", func(t *testing.T) {
+		t.Parallel()
+		client, _, _ := server(t, "key")
+		m := MediaServiceOp{Client: client}
+		_, err := m.Download(context.TODO(), "media", 2, "")
+		AssertError(t, err)
+		AssertContains(t, err.Error(), "status code 404")
+	})
+
+	t.Run("Other Host", func(t *testing.T) {
+		t.Parallel()
+
+		cdn := downloadServer(t, func(w http.ResponseWriter, r *http.Request) {
+			AssertEqual(t, "", r.Header.Get("Authorization"))
+			_, _ = w.Write([]byte(original))
+		})
+		client, teardown := Setup(t, func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = fmt.Fprintf(w, `{"id": 1, "url": %q}`, cdn+"/cat.png")
+		})
+		defer teardown()
+		client.apiKey = "key"
+
+		m := MediaServiceOp{Client: client}
+		rc, err := m.Download(context.TODO(), "media", 1, "")
+		AssertEqual(t, original, read(t, rc, err))
+	})
+
+	t.Run("Cache", func(t *testing.T) {
+		t.Parallel()
+
+		client, downloads, mu := server(t, "key")
+		client.mediaCache = &mediaCache{dir: t.TempDir()}
+		m := MediaServiceOp{Client: client}
+
+		for range 3 {
+			rc, err := m.Download(context.TODO(), "media", 1, "")
+			AssertEqual(t, original, read(t, rc, err))
+			rc, err = m.Download(context.TODO(), "media", 1, "thumbnail")
+			AssertEqual(t, thumb, read(t, rc, err))
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		AssertEqual(t, 1, downloads["/api/media/file/cat.png"])
+		AssertEqual(t, 1, downloads["/api/media/file/cat-300x225.png"])
+	})
+}
@@ -0,0 +1,135 @@
+package payloadcms
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// mediaCache is a content addressed disk cache of the files downloaded
+// by MediaService.Download. Files are stored under blobs by the SHA-256
+// of their content, so identical files are only stored once, and an
+// index maps the URL of each file to its blob.
+type mediaCache struct {
+	dir string
+}
+
+// mediaCacheEntry is the index entry of a downloaded file.
+type mediaCacheEntry struct {
+	URL      string `json:"url"`
+	Hash     string `json:"hash"`
+	Filesize int64  `json:"filesize"`
+	ETag     string `json:"etag,omitempty"`
+}
+
+// lookup returns the entry of the URL, reporting false if the file has
+// not been downloaded or its blob is missing or has changed size.
+func (m *mediaCache) lookup(url string) (mediaCacheEntry, bool) {
+	buf, err := os.ReadFile(m.indexPath(url))
+	if err != nil {
+		return mediaCacheEntry{}, false
+	}
+
+	var entry mediaCacheEntry
+	if err := json.Unmarshal(buf, &entry); err != nil || entry.URL != url {
+		return mediaCacheEntry{}, false
+	}
+
+	info, err := os.Stat(m.blobPath(entry.Hash))
+	if err != nil || info.Size() != entry.Filesize {
+		return mediaCacheEntry{}, false
+	}
+
+	return entry, true
+}
+
+// store writes the file read from r to the cache and indexes it under
+// the URL. The file is written to a temporary file first, so partial
+// downloads never end up in the cache.
+func (m *mediaCache) store(url, etag string, r io.Reader) (entry mediaCacheEntry, err error) {
+	if err := os.MkdirAll(filepath.Join(m.dir, "blobs"), 0o755); err != nil {
+		return entry, err
+	}
+	if err := os.MkdirAll(filepath.Join(m.dir, "index"), 0o755); err != nil {
+		return entry, err
+	}
+
+	tmp, err := os.CreateTemp(m.dir, "download-*")
+	if err != nil {
+		return entry, err
+	}
+	defer func() {
+		_ = tmp.Close()
+		if err != nil {
+			_ = os.Remove(tmp.Name())
+		}
+	}()
+
+	h := sha256.New()
+	n, err := io.Copy(io.MultiWriter(tmp, h), r)
+	if err != nil {
+		return entry, err
+	}
+	if err := tmp.Close(); err != nil {
+		return entry, err
+	}
+
+	entry = mediaCacheEntry{
+		URL:      url,
+		Hash:     hex.EncodeToString(h.Sum(nil)),
+		Filesize: n,
+		ETag:     etag,
+	}
+	if err := os.Rename(tmp.Name(), m.blobPath(entry.Hash)); err != nil {
+		return entry, err
+	}
+
+	buf, err := json.Marshal(entry)
+	if err != nil {
+		return entry, err
+	}
+
+	return entry, writeFileAtomic(m.indexPath(url), buf)
+}
+
+// open opens the blob of the entry. It returns an io.ReadCloser so it can
+// be returned by Download directly, which is nil when the blob can't be
+// opened rather than holding a nil *os.File.
+func (m *mediaCache) open(entry mediaCacheEntry) (io.ReadCloser, error) {
+	f, err := os.Open(m.blobPath(entry.Hash))
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (m *mediaCache) blobPath(hash string) string {
+	return filepath.Join(m.dir, "blobs", hash)
+}
+
+func (m *mediaCache) indexPath(url string) string {
+	sum := sha256.Sum256([]byte(url))
+	return filepath.Join(m.dir, "index", hex.EncodeToString(sum[:])+".json")
+}
+
+// writeFileAtomic writes the data to a temporary file in the same
+// directory and renames it, so readers never see a partial file.
+func writeFileAtomic(name string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(name), filepath.Base(name)+"-*")
+	if err != nil {
+		return err
+	}
+	_, err = tmp.Write(data)
+	err = errors.Join(err, tmp.Close())
+	if err == nil {
+		err = os.Rename(tmp.Name(), name)
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+	}
+	return err
+}
@@ -0,0 +1,95 @@
+package payloadcms
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMediaCache(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Store And Lookup", func(t *testing.T) {
+		t.Parallel()
+
+		cache := &mediaCache{dir: t.TempDir()}
+		_, ok := cache.lookup("/cat.png")
+		AssertEqual(t, false, ok)
+
+		entry, err := cache.store("/cat.png", `"v1"`, strings.NewReader("cat"))
+		require.NoError(t, err)
+		AssertEqual(t, int64(3), entry.Filesize)
+		AssertEqual(t, "77af778b51abd4a3c51c5ddd97204a9c3ae614ebccb75a606c3b6865aed6744e", entry.Hash)
+
+		got, ok := cache.lookup("/cat.png")
+		AssertEqual(t, true, ok)
+		AssertEqual(t, entry, got)
+
+		f, err := cache.open(got)
+		require.NoError(t, err)
+		defer f.Close()
+		buf, err := io.ReadAll(f)
+		require.NoError(t, err)
+		AssertEqual(t, "cat", string(buf))
+	})
+
+	t.Run("Content Addressed", func(t *testing.T) {
+		t.Parallel()
+
+		cache := &mediaCache{dir: t.TempDir()}
+		a, err := cache.store("/a.png", "", strings.NewReader("same"))
+		require.NoError(t, err)
+		b, err := cache.store("/b.png", "", strings.NewReader("same"))
+		require.NoError(t, err)
+		AssertEqual(t, a.Hash, b.Hash)
+
+		blobs, err := os.ReadDir(filepath.Join(cache.dir, "blobs"))
+		require.NoError(t, err)
+		AssertEqual(t, 1, len(blobs))
+	})
+
+	t.Run("Blob Changed", func(t *testing.T) {
+		t.Parallel()
+
+		cache := &mediaCache{dir: t.TempDir()}
+		entry, err := cache.store("/cat.png", "", strings.NewReader("cat"))
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(cache.blobPath(entry.Hash), []byte("truncated"), 0o600))
+
+		_, ok := cache.lookup("/cat.png")
+		AssertEqual(t, false, ok)
+	})
+
+	t.Run("Read Error", func(t *testing.T) {
+		t.Parallel()
+
+		cache := &mediaCache{dir: t.TempDir()}
+		_, err := cache.store("/cat.png", "", &errAfterReader{r: strings.NewReader("cat")})
+		AssertError(t, err)
+
+		_, ok := cache.lookup("/cat.png")
+		AssertEqual(t, false, ok)
+
+		// The partial download is removed.
+		files, err := os.ReadDir(cache.dir)
+		require.NoError(t, err)
+		for _, f := range files {
+			assert.True(t, f.IsDir(), f.Name())
+		}
+	})
+
+	t.Run("Open Missing Blob", func(t *testing.T) {
+		t.Parallel()
+
+		cache := &mediaCache{dir: t.TempDir()}
+		rc, err := cache.open(mediaCacheEntry{Hash: "missing"})
+		AssertError(t, err)
+		// A nil *os.File must not be returned as a non-nil io.ReadCloser.
+		assert.True(t, rc == nil)
+	})
+}
@@ -0,0 +1,161 @@
+package payloadcms
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// MediaDoc is a document of an upload collection, containing the fields
+// Payload adds to every upload. Embed it in a struct to add the fields
+// of the collection, or pass it directly to MediaService.Upload, which
+// decodes the uploaded document into it.
+//
+// Example:
+//
+//	type Media struct {
+//		payloadcms.MediaDoc
+//		Alt string `json:"alt"`
+//	}
+//
+// See: https://payloadcms.com/docs/upload/overview
+type MediaDoc struct {
+	// ID is the ID of the document, which is a float64 for numeric IDs
+	// and a string for MongoDB.
+	ID           any                  `json:"id,omitempty"`
+	URL          string               `json:"url"`
+	ThumbnailURL string               `json:"thumbnailURL,omitempty"` //nolint:tagliatelle
+	Filename     string               `json:"filename"`
+	MimeType     string               `json:"mimeType"`
+	Filesize     int64                `json:"filesize"`
+	Width        int                  `json:"width,omitempty"`
+	Height       int                  `json:"height,omitempty"`
+	FocalX       float64              `json:"focalX,omitempty"`
+	FocalY       float64              `json:"focalY,omitempty"`
+	Sizes        map[string]ImageSize `json:"sizes,omitempty"`
+	CreatedAt    string               `json:"createdAt,omitempty"`
+	UpdatedAt    string               `json:"updatedAt,omitempty"`
+}
+
+// ImageSize is a variant of an image generated by Payload from the
+// imageSizes of an upload collection. The fields are empty when the size
+// wasn't generated, for example when the image is smaller than it.
+//
+// See: https://payloadcms.com/docs/upload/overview#image-sizes
+type ImageSize struct {
+	URL      string `json:"url"`
+	Filename string `json:"filename"`
+	MimeType string `json:"mimeType"`
+	Filesize int64  `json:"filesize"`
+	Width    int    `json:"width"`
+	Height   int    `json:"height"`
+}
+
+// mediaDocument is implemented by MediaDoc and the structs embedding it,
+// which the response of an upload is unwrapped into.
+type mediaDocument interface {
+	mediaDoc() *MediaDoc
+}
+
+func (d *MediaDoc) mediaDoc() *MediaDoc {
+	return d
+}
+
+// IsImage reports whether the document is an image.
+func (d MediaDoc) IsImage() bool {
+	return strings.HasPrefix(d.MimeType, "image/")
+}
+
+// ClosestSize returns the smallest image, out of the original and its
+// generated sizes, that is at least the width given. If none are wide
+// enough, the widest is returned. The name of the size is empty when the
+// original is returned, and ok is false when there are no images with
+// a URL and width.
+func (d MediaDoc) ClosestSize(width int) (name string, size ImageSize, ok bool) {
+	names := make([]string, 0, len(d.Sizes))
+	for n := range d.Sizes {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	candidates := append([]string{""}, names...)
+	for _, n := range candidates {
+		s := d.size(n)
+		if s.URL == "" || s.Width == 0 {
+			continue
+		}
+		switch {
+		case !ok:
+		case size.Width >= width:
+			// Replace with a smaller image that is still wide enough.
+			if s.Width < width || s.Width >= size.Width {
+				continue
+			}
+		case s.Width <= size.Width:
+			// Replace with a wider image until one is wide enough.
+			continue
+		}
+		name, size, ok = n, s, true
+	}
+
+	return name, size, ok
+}
+
+// size returns the named size, or the original when the name is empty.
+func (d MediaDoc) size(name string) ImageSize {
+	if name == "" {
+		return ImageSize{
+			URL:      d.URL,
+			Filename: d.Filename,
+			MimeType: d.MimeType,
+			Filesize: d.Filesize,
+			Width:    d.Width,
+			Height:   d.Height,
+		}
+	}
+	return d.Sizes[name]
+}
+
+// AbsoluteURL returns the URL of a document, such as MediaDoc.URL, with
+// the base URL of the client prepended. Payload returns relative URLs
+// unless serverURL is set in its config, and URLs that are already
+// absolute are returned as they are.
+func (c *Client) AbsoluteURL(u string) string {
+	if u == "" || strings.Contains(u, "://") || strings.HasPrefix(u, "//") {
+		return u
+	}
+	return fmt.Sprintf("%s/%s", strings.TrimSuffix(c.baseURL, "/"), strings.TrimPrefix(u, "/"))
+}
+
+// newFileRequest creates a GET request for the URL of a file. The API key
+// is only sent when the file is served from the base URL, so it isn't
+// leaked to a CDN or other host set as the serverURL of Payload.
+func (c *Client) newFileRequest(ctx context.Context, fileURL string) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.AbsoluteURL(fileURL), nil)
+	if err != nil {
+		return nil, err
+	}
+	if base, err := url.Parse(c.baseURL); err == nil && base.Host == req.URL.Host {
+		req.Header.Set("Authorization", "users API-Key "+c.apiKey)
+	}
+	return req, nil
+}
+
+// decodeMediaDoc decodes the document of an upload response, which is
+// wrapped in a doc field along with a message, into out.
+func decodeMediaDoc(content []byte, out mediaDocument) error {
+	var envelope struct {
+		Doc json.RawMessage `json:"doc"`
+	}
+	if err := json.Unmarshal(content, &envelope); err != nil {
+		return err
+	}
+	if len(envelope.Doc) == 0 {
+		return nil
+	}
+	return json.Unmarshal(envelope.Doc, out)
+}
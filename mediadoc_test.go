@@ -0,0 +1,183 @@
+package payloadcms
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const mediaDocJSON = `{
+	"doc": {
+		"id": 12,
+		"alt": "A cat",
+		"url": "/api/media/file/cat.png",
+		"filename": "cat.png",
+		"mimeType": "image/png",
+		"filesize": 52000,
+		"width": 1600,
+		"height": 1200,
+		"focalX": 50,
+		"focalY": 40,
+		"sizes": {
+			"thumbnail": {"url": "/api/media/file/cat-300x225.png", "filename": "cat-300x225.png", "mimeType": "image/png", "filesize": 4000, "width": 300, "height": 225},
+			"card": {"url": "/api/media/file/cat-768x576.png", "filename": "cat-768x576.png", "mimeType": "image/png", "filesize": 21000, "width": 768, "height": 576},
+			"hero": {"url": null, "filename": null, "mimeType": null, "filesize": null, "width": null, "height": null}
+		}
+	},
+	"message": "Media successfully created."
+}`
+
+func TestMediaService_UploadMediaDoc(t *testing.T) {
+	t.Parallel()
+
+	type media struct {
+		MediaDoc
+		Alt string `json:"alt"`
+	}
+
+	client, teardown := Setup(t, func(w http.ResponseWriter, _ *http.Request) {
+		_, err := w.Write([]byte(mediaDocJSON))
+		AssertNoError(t, err)
+	})
+	t.Cleanup(teardown)
+
+	m := MediaServiceOp{Client: client}
+
+	t.Run("MediaDoc", func(t *testing.T) {
+		t.Parallel()
+
+		var doc MediaDoc
+		_, err := m.Upload(context.TODO(), strings.NewReader("file"), nil, &doc, MediaOptions{FileName: "cat"})
+		require.NoError(t, err)
+		AssertEqual[any](t, float64(12), doc.ID)
+		AssertEqual(t, "/api/media/file/cat.png", doc.URL)
+		AssertEqual(t, int64(52000), doc.Filesize)
+		AssertEqual(t, 1600, doc.Width)
+		AssertEqual(t, 40.0, doc.FocalY)
+		AssertEqual(t, 300, doc.Sizes["thumbnail"].Width)
+		AssertEqual(t, "", doc.Sizes["hero"].URL)
+	})
+
+	t.Run("Embedded", func(t *testing.T) {
+		t.Parallel()
+
+		var doc media
+		_, err := m.Upload(context.TODO(), strings.NewReader("file"), nil, &doc, MediaOptions{FileName: "cat"})
+		require.NoError(t, err)
+		AssertEqual(t, "A cat", doc.Alt)
+		AssertEqual(t, "cat.png", doc.Filename)
+	})
+
+	t.Run("Create Response", func(t *testing.T) {
+		t.Parallel()
+
+		var resp CreateResponse[media]
+		_, err := m.Upload(context.TODO(), strings.NewReader("file"), nil, &resp, MediaOptions{FileName: "cat"})
+		require.NoError(t, err)
+		AssertEqual(t, "Media successfully created.", resp.Message)
+		AssertEqual(t, "image/png", resp.Doc.MimeType)
+	})
+}
+
+func TestMediaDoc_ClosestSize(t *testing.T) {
+	t.Parallel()
+
+	var resp CreateResponse[MediaDoc]
+	require.NoError(t, json.Unmarshal([]byte(mediaDocJSON), &resp))
+
+	tt := map[string]struct {
+		doc      MediaDoc
+		width    int
+		wantName string
+		wantURL  string
+		wantOK   bool
+	}{
+		"Smallest": {
+			doc:      resp.Doc,
+			width:    100,
+			wantName: "thumbnail",
+			wantURL:  "/api/media/file/cat-300x225.png",
+			wantOK:   true,
+		},
+		"Exact": {
+			doc:      resp.Doc,
+			width:    768,
+			wantName: "card",
+			wantURL:  "/api/media/file/cat-768x576.png",
+			wantOK:   true,
+		},
+		"Between": {
+			doc:      resp.Doc,
+			width:    500,
+			wantName: "card",
+			wantURL:  "/api/media/file/cat-768x576.png",
+			wantOK:   true,
+		},
+		"Original": {
+			doc:      resp.Doc,
+			width:    1000,
+			wantName: "",
+			wantURL:  "/api/media/file/cat.png",
+			wantOK:   true,
+		},
+		"Wider Than All": {
+			doc:      resp.Doc,
+			width:    4000,
+			wantName: "",
+			wantURL:  "/api/media/file/cat.png",
+			wantOK:   true,
+		},
+		"Not An Image": {
+			doc:    MediaDoc{URL: "/api/media/file/doc.pdf", MimeType: "application/pdf"},
+			width:  100,
+			wantOK: false,
+		},
+	}
+
+	for name, test := range tt {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			gotName, got, ok := test.doc.ClosestSize(test.width)
+			AssertEqual(t, test.wantOK, ok)
+			AssertEqual(t, test.wantName, gotName)
+			AssertEqual(t, test.wantURL, got.URL)
+		})
+	}
+}
+
+func TestMediaDoc_IsImage(t *testing.T) {
+	t.Parallel()
+	assert.True(t, MediaDoc{MimeType: "image/webp"}.IsImage())
+	assert.False(t, MediaDoc{MimeType: "application/pdf"}.IsImage())
+}
+
+func TestClient_AbsoluteURL(t *testing.T) {
+	t.Parallel()
+
+	tt := map[string]struct {
+		base  string
+		input string
+		want  string
+	}{
+		"Relative":          {base: "https://cms.example.com", input: "/api/media/file/cat.png", want: "https://cms.example.com/api/media/file/cat.png"},
+		"Base With Slash":   {base: "https://cms.example.com/", input: "/api/media/file/cat.png", want: "https://cms.example.com/api/media/file/cat.png"},
+		"Base With Path":    {base: "https://example.com/cms", input: "/api/media/file/cat.png", want: "https://example.com/cms/api/media/file/cat.png"},
+		"Absolute":          {base: "https://cms.example.com", input: "https://cdn.example.com/cat.png", want: "https://cdn.example.com/cat.png"},
+		"Protocol Relative": {base: "https://cms.example.com", input: "//cdn.example.com/cat.png", want: "//cdn.example.com/cat.png"},
+		"Empty":             {base: "https://cms.example.com", input: "", want: ""},
+	}
+
+	for name, test := range tt {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			c, err := New(WithBaseURL(test.base))
+			require.NoError(t, err)
+			AssertEqual(t, test.want, c.AbsoluteURL(test.input))
+		})
+	}
+}
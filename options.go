@@ -3,6 +3,7 @@ package payloadcms
 import (
 	"net/http"
 	"strconv"
+	"time"
 )
 
 // ClientOption is a functional option type that allows us to configure the Client.
@@ -34,6 +35,77 @@ func WithAPIKey(apiKey string) ClientOption {
 	}
 }
 
+// WithCache is a functional option to cache the responses of GET requests
+// for the given TTL. Writes made through the Collections and Globals
+// services invalidate the affected entries automatically. A TTL of zero
+// disables caching for collections and globals without a TTL of their own.
+//
+// Pass NewMemoryCache for an in-memory LRU cache, or any other
+// implementation of the Cache interface.
+func WithCache(cache Cache, ttl time.Duration) ClientOption {
+	return func(c *Client) {
+		rc := c.cacheConfig()
+		rc.store = cache
+		rc.ttl = ttl
+	}
+}
+
+// WithCollectionCacheTTL is a functional option to override the cache TTL
+// for a single collection. A TTL of zero disables caching for the collection.
+// It has no effect unless WithCache is also used.
+func WithCollectionCacheTTL(collection Collection, ttl time.Duration) ClientOption {
+	return func(c *Client) {
+		c.cacheConfig().collections[collection] = ttl
+	}
+}
+
+// WithGlobalCacheTTL is a functional option to override the cache TTL
+// for a single global. A TTL of zero disables caching for the global.
+// It has no effect unless WithCache is also used.
+func WithGlobalCacheTTL(global Global, ttl time.Duration) ClientOption {
+	return func(c *Client) {
+		c.cacheConfig().globals[global] = ttl
+	}
+}
+
+// WithConditionalRequests is a functional option to revalidate GET requests
+// using the ETag and Last-Modified headers sent back by Payload. The
+// validators and body of each response are kept in the store, and on
+// a 304 Not Modified the stored body is returned as if it were a 200.
+//
+// If store is nil, an in-memory LRU cache with the default capacity is used.
+// The store may be shared with WithCache, as the validators are stored
+// under their own keys.
+func WithConditionalRequests(store Cache) ClientOption {
+	return func(c *Client) {
+		if store == nil {
+			store = NewMemoryCache(DefaultCacheCapacity)
+		}
+		c.validators = store
+	}
+}
+
+// WithRequestCoalescing is a functional option to share a single HTTP call
+// between identical GET requests that are in flight at the same time.
+// Requests are identical when the method, path, query and authorization
+// match. Each caller receives its own copy of the response, and cancelling
+// one caller's context does not cancel the request for the others.
+func WithRequestCoalescing() ClientOption {
+	return func(c *Client) {
+		c.flights = newFlightGroup()
+	}
+}
+
+// WithMediaCache is a functional option to keep the files downloaded by
+// MediaService.Download in dir. A file is only downloaded again when its
+// filesize in Payload no longer matches, or Payload responds to the ETag
+// of the cached file with a changed file.
+func WithMediaCache(dir string) ClientOption {
+	return func(c *Client) {
+		c.mediaCache = &mediaCache{dir: dir}
+	}
+}
+
 // RequestOption is a functional option type used to configure request options.
 type RequestOption func(*http.Request)
 
@@ -47,6 +119,25 @@ func WithDepth(depth int) RequestOption {
 	}
 }
 
+// WithLocale sets the locale of the API response for localized fields.
+// Pass "all" to retrieve every locale.
+//
+// See: https://payloadcms.com/docs/configuration/localization
+func WithLocale(locale string) RequestOption {
+	return func(r *http.Request) {
+		WithQueryParam("locale", locale)(r)
+	}
+}
+
+// WithNoCache bypasses the response cache of WithCache for the request,
+// so the response is always fetched from Payload and isn't stored. It
+// sets the Cache-Control header of the request to no-cache.
+func WithNoCache() RequestOption {
+	return func(r *http.Request) {
+		r.Header.Set("Cache-Control", "no-cache")
+	}
+}
+
 // WithQueryParam adds a query parameter to the API request.
 func WithQueryParam(key, val string) RequestOption {
 	return func(r *http.Request) {
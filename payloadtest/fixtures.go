@@ -0,0 +1,184 @@
+package payloadtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+
+	"github.com/ainsleyclark/go-payloadcms"
+)
+
+// Fixtures are the documents a Server is seeded with, typically decoded
+// from a JSON file of the form:
+//
+//	{
+//		"collections": {
+//			"posts": [{ "id": 1, "title": "Hello" }]
+//		},
+//		"globals": {
+//			"settings": { "siteName": "Example" }
+//		}
+//	}
+type Fixtures struct {
+	Collections map[payloadcms.Collection][]json.RawMessage `json:"collections"`
+	Globals     map[payloadcms.Global]json.RawMessage       `json:"globals"`
+}
+
+// Seed adds the documents to the collection, as if they were created.
+// Each document is encoded as JSON, so it can be a struct or a map.
+// Documents without an id are given the next numeric ID, and createdAt and
+// updatedAt are set if they're missing.
+func (s *Server) Seed(collection payloadcms.Collection, docs ...any) error {
+	decoded := make([]Doc, 0, len(docs))
+	for _, v := range docs {
+		doc, err := toDoc(v)
+		if err != nil {
+			return fmt.Errorf("seeding %s: %w", collection, err)
+		}
+		decoded = append(decoded, doc)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c := s.collection(collection)
+	for _, doc := range decoded {
+		if _, err := c.insert(doc, s.now()); err != nil {
+			return fmt.Errorf("seeding %s: %w", collection, err)
+		}
+	}
+	return nil
+}
+
+// SeedGlobal sets the fields of the global. The document is encoded as
+// JSON, so it can be a struct or a map.
+func (s *Server) SeedGlobal(global payloadcms.Global, doc any) error {
+	fields, err := toDoc(doc)
+	if err != nil {
+		return fmt.Errorf("seeding %s: %w", global, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.setGlobal(global, fields)
+	return nil
+}
+
+// Load seeds the server with the fixtures.
+func (s *Server) Load(f Fixtures) error {
+	for _, name := range slices.Sorted(maps.Keys(f.Collections)) {
+		docs := make([]any, len(f.Collections[name]))
+		for i, raw := range f.Collections[name] {
+			docs[i] = raw
+		}
+		if err := s.Seed(name, docs...); err != nil {
+			return err
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(f.Globals)) {
+		if err := s.SeedGlobal(name, f.Globals[name]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LoadJSON seeds the server with fixtures decoded from JSON.
+func (s *Server) LoadJSON(r io.Reader) error {
+	var f Fixtures
+	if err := json.NewDecoder(r).Decode(&f); err != nil {
+		return fmt.Errorf("decoding fixtures: %w", err)
+	}
+	return s.Load(f)
+}
+
+// LoadFile seeds the server with fixtures from the JSON file at the path.
+func (s *Server) LoadFile(name string) error {
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return s.LoadJSON(f)
+}
+
+// Docs returns a copy of the documents in the collection, in the order
+// they were created.
+func (s *Server) Docs(collection payloadcms.Collection) []Doc {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.collections[collection]
+	if !ok {
+		return nil
+	}
+	docs := make([]Doc, len(c.docs))
+	for i, doc := range c.docs {
+		docs[i] = maps.Clone(doc)
+	}
+	return docs
+}
+
+// Doc returns a copy of the document in the collection with the ID,
+// reporting false if it doesn't exist.
+func (s *Server) Doc(collection payloadcms.Collection, id any) (Doc, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.collections[collection]
+	if !ok {
+		return nil, false
+	}
+	_, doc := c.find(fmt.Sprint(id))
+	return maps.Clone(doc), doc != nil
+}
+
+// Global returns a copy of the global, reporting false if it hasn't been
+// set.
+func (s *Server) Global(global payloadcms.Global) (Doc, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	doc, ok := s.globals[global]
+	return maps.Clone(doc), ok
+}
+
+// File returns the content of a file uploaded to the collection,
+// reporting false if it doesn't exist.
+func (s *Server) File(collection payloadcms.Collection, filename string) ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.collections[collection]
+	if !ok {
+		return nil, false
+	}
+	f, ok := c.files[filename]
+	return bytes.Clone(f.data), ok
+}
+
+// Reset removes every document, global and file.
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	clear(s.collections)
+	clear(s.globals)
+}
+
+// toDoc encodes the value as JSON and decodes it into a Doc.
+func toDoc(v any) (Doc, error) {
+	buf, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := decodeDoc(bytes.NewReader(buf))
+	if err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
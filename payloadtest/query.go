@@ -0,0 +1,90 @@
+package payloadtest
+
+import (
+	"fmt"
+	"math"
+	"net/url"
+	"strconv"
+
+	"github.com/ainsleyclark/go-payloadcms"
+	"github.com/ainsleyclark/go-payloadcms/where"
+)
+
+// defaultLimit is the limit Payload applies to lists when none is given.
+const defaultLimit = 10
+
+// listQuery is the query string of a list request.
+type listQuery struct {
+	where where.Where
+	sort  string
+	limit int
+	page  int
+}
+
+func parseListQuery(q url.Values) (listQuery, error) {
+	lq := listQuery{
+		sort:  q.Get("sort"),
+		limit: defaultLimit,
+		page:  1,
+	}
+
+	w, err := where.Parse(q)
+	if err != nil {
+		return lq, err
+	}
+	lq.where = w
+
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return lq, fmt.Errorf("invalid limit: %s", v)
+		}
+		lq.limit = n
+	}
+	if v := q.Get("page"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			return lq, fmt.Errorf("invalid page: %s", v)
+		}
+		lq.page = n
+	}
+
+	return lq, nil
+}
+
+// apply filters, sorts and paginates the documents. A limit of zero
+// returns every document on a single page.
+func (q listQuery) apply(docs []Doc) (payloadcms.ListResponse[Doc], error) {
+	matched, err := where.Filter(docs, q.where)
+	if err != nil {
+		return payloadcms.ListResponse[Doc]{}, err
+	}
+	where.Sort(matched, q.sort)
+
+	limit := q.limit
+	if limit == 0 {
+		limit = max(len(matched), 1)
+	}
+	pages := max(int(math.Ceil(float64(len(matched))/float64(limit))), 1)
+	start := min((q.page-1)*limit, len(matched))
+	end := min(start+limit, len(matched))
+
+	list := payloadcms.ListResponse[Doc]{
+		Docs:          matched[start:end],
+		Total:         len(matched),
+		TotalDocs:     len(matched),
+		Limit:         q.limit,
+		TotalPages:    pages,
+		Page:          q.page,
+		PagingCounter: start + 1,
+		HasPrevPage:   q.page > 1,
+		HasNextPage:   q.page < pages,
+	}
+	if list.HasPrevPage {
+		list.PrevPage = q.page - 1
+	}
+	if list.HasNextPage {
+		list.NextPage = q.page + 1
+	}
+	return list, nil
+}
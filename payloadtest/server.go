@@ -0,0 +1,383 @@
+// Package payloadtest provides an in-memory fake of the Payload REST API
+// for tests, so that requests are sent over HTTP with their real paths,
+// query strings and bodies, rather than being stubbed out.
+//
+// The Server implements the endpoints used by payloadcms.Client:
+// collections, including lists with where, sort, limit and page,
+// globals, multipart uploads and API key auth. Where queries and sorts
+// are evaluated by the where package. Collections and globals
+// don't need to be declared, they're created when they're first written
+// to. Relationships aren't populated, so documents are always returned
+// at a depth of zero.
+//
+// Example:
+//
+//	srv := payloadtest.NewServer(payloadtest.WithAPIKey("key"))
+//	defer srv.Close()
+//
+//	client, _ := payloadcms.New(
+//		payloadcms.WithBaseURL(srv.URL),
+//		payloadcms.WithAPIKey("key"),
+//	)
+package payloadtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ainsleyclark/go-payloadcms"
+)
+
+// Server is a fake Payload server that stores documents in memory.
+// It's safe for concurrent use.
+type Server struct {
+	*httptest.Server
+
+	apiKey string
+	now    func() time.Time
+
+	mu          sync.Mutex
+	collections map[payloadcms.Collection]*collection
+	globals     map[payloadcms.Global]Doc
+}
+
+// Doc is a document stored by the Server, as it's decoded from JSON.
+// Numbers are stored as json.Number.
+type Doc = map[string]any
+
+// collection holds the documents of a collection in the order they were
+// created, along with the files uploaded to it.
+type collection struct {
+	docs   []Doc
+	nextID int64
+	files  map[string]file
+}
+
+// Option is a functional option type that allows us to configure a Server.
+type Option func(*Server)
+
+// WithAPIKey requires requests to be authenticated with the API key,
+// as set by payloadcms.WithAPIKey. Requests without it are forbidden.
+func WithAPIKey(key string) Option {
+	return func(s *Server) {
+		s.apiKey = key
+	}
+}
+
+// WithClock sets the function used for the createdAt and updatedAt
+// timestamps of documents, which defaults to time.Now.
+func WithClock(now func() time.Time) Option {
+	return func(s *Server) {
+		s.now = now
+	}
+}
+
+// NewServer starts and returns a new Server. The caller should call Close
+// when finished, to shut it down.
+func NewServer(options ...Option) *Server {
+	s := &Server{
+		now:         time.Now,
+		collections: make(map[payloadcms.Collection]*collection),
+		globals:     make(map[payloadcms.Global]Doc),
+	}
+	for _, opt := range options {
+		opt(s)
+	}
+	s.Server = httptest.NewServer(s.routes())
+	return s
+}
+
+func (s *Server) routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/globals/{global}", s.getGlobal)
+	mux.HandleFunc("POST /api/globals/{global}", s.updateGlobal)
+	mux.HandleFunc("GET /api/{collection}", s.list)
+	mux.HandleFunc("POST /api/{collection}", s.create)
+	mux.HandleFunc("GET /api/{collection}/{id}", s.findByID)
+	mux.HandleFunc("PATCH /api/{collection}/{id}", s.update)
+	mux.HandleFunc("DELETE /api/{collection}/{id}", s.delete)
+	mux.HandleFunc("GET /api/{collection}/slug/{slug}", s.findBySlug)
+	mux.HandleFunc(filePattern, s.file)
+	mux.HandleFunc("/", func(w http.ResponseWriter, _ *http.Request) {
+		writeError(w, http.StatusNotFound, "The requested resource was not found.")
+	})
+	return s.authenticate(mux)
+}
+
+// filePattern is the route of uploaded files.
+const filePattern = "GET /api/{collection}/file/{filename}"
+
+// authenticate checks the API key of requests, except for files, which
+// Payload serves publicly by default.
+func (s *Server) authenticate(mux *http.ServeMux) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, pattern := mux.Handler(r); s.apiKey != "" && pattern != filePattern {
+			_, key, ok := strings.Cut(r.Header.Get("Authorization"), " API-Key ")
+			if !ok || key != s.apiKey {
+				writeError(w, http.StatusForbidden, "You are not allowed to perform this action.")
+				return
+			}
+		}
+		mux.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) list(w http.ResponseWriter, r *http.Request) {
+	q, err := parseListQuery(r.URL.Query())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	list, err := q.apply(s.collection(payloadcms.Collection(r.PathValue("collection"))).docs)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, list)
+}
+
+func (s *Server) findByID(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, doc := s.collection(payloadcms.Collection(r.PathValue("collection"))).find(r.PathValue("id"))
+	if doc == nil {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	writeJSON(w, http.StatusOK, doc)
+}
+
+func (s *Server) findBySlug(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	slug := r.PathValue("slug")
+	for _, doc := range s.collection(payloadcms.Collection(r.PathValue("collection"))).docs {
+		if doc["slug"] == slug {
+			writeJSON(w, http.StatusOK, doc)
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, "Not Found")
+}
+
+func (s *Server) create(w http.ResponseWriter, r *http.Request) {
+	name := payloadcms.Collection(r.PathValue("collection"))
+
+	fields, upload, err := readBody(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c := s.collection(name)
+	if upload != nil {
+		maps.Copy(fields, c.store(name, upload))
+	}
+	doc, err := c.insert(fields, s.now())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, map[string]any{
+		"doc":     doc,
+		"message": "Successfully created.",
+	})
+}
+
+func (s *Server) update(w http.ResponseWriter, r *http.Request) {
+	name := payloadcms.Collection(r.PathValue("collection"))
+
+	fields, upload, err := readBody(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c := s.collection(name)
+	_, doc := c.find(r.PathValue("id"))
+	if doc == nil {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	if upload != nil {
+		if old, ok := doc["filename"].(string); ok {
+			delete(c.files, old)
+		}
+		maps.Copy(fields, c.store(name, upload))
+	}
+	delete(fields, "id")
+	delete(fields, "createdAt")
+	maps.Copy(doc, fields)
+	doc["updatedAt"] = timestamp(s.now())
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"doc":     doc,
+		"message": "Updated successfully.",
+	})
+}
+
+func (s *Server) delete(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c := s.collection(payloadcms.Collection(r.PathValue("collection")))
+	i, doc := c.find(r.PathValue("id"))
+	if doc == nil {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	c.docs = append(c.docs[:i:i], c.docs[i+1:]...)
+	if name, ok := doc["filename"].(string); ok {
+		delete(c.files, name)
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"doc":     doc,
+		"message": "Deleted successfully.",
+	})
+}
+
+func (s *Server) getGlobal(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	doc, ok := s.globals[payloadcms.Global(r.PathValue("global"))]
+	if !ok {
+		doc = Doc{}
+	}
+	writeJSON(w, http.StatusOK, doc)
+}
+
+func (s *Server) updateGlobal(w http.ResponseWriter, r *http.Request) {
+	fields, err := decodeDoc(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	doc := s.setGlobal(payloadcms.Global(r.PathValue("global")), fields)
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"result":  doc,
+		"message": "Updated successfully.",
+	})
+}
+
+func (s *Server) file(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	f, ok := s.collection(payloadcms.Collection(r.PathValue("collection"))).files[r.PathValue("filename")]
+	s.mu.Unlock()
+	if !ok {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	w.Header().Set("Content-Type", f.mimeType)
+	w.Header().Set("Content-Length", strconv.Itoa(len(f.data)))
+	_, _ = w.Write(f.data)
+}
+
+// collection returns the collection, creating it if it doesn't exist.
+// The caller must hold s.mu.
+func (s *Server) collection(name payloadcms.Collection) *collection {
+	c, ok := s.collections[name]
+	if !ok {
+		c = &collection{nextID: 1, files: make(map[string]file)}
+		s.collections[name] = c
+	}
+	return c
+}
+
+// setGlobal merges the fields into the global. The caller must hold s.mu.
+func (s *Server) setGlobal(global payloadcms.Global, fields Doc) Doc {
+	doc, ok := s.globals[global]
+	if !ok {
+		doc = Doc{"globalType": string(global)}
+		s.globals[global] = doc
+	}
+	maps.Copy(doc, fields)
+	doc["updatedAt"] = timestamp(s.now())
+	return doc
+}
+
+// find returns the document with the ID and its index, or nil if it
+// doesn't exist.
+func (c *collection) find(id string) (int, Doc) {
+	for i, doc := range c.docs {
+		if fmt.Sprint(doc["id"]) == id {
+			return i, doc
+		}
+	}
+	return -1, nil
+}
+
+// insert adds the document, assigning the next ID if it doesn't have one
+// and setting its timestamps if they're missing.
+func (c *collection) insert(doc Doc, now time.Time) (Doc, error) {
+	id, ok := doc["id"]
+	if !ok || id == nil {
+		doc["id"] = json.Number(strconv.FormatInt(c.nextID, 10))
+		c.nextID++
+	} else {
+		if _, existing := c.find(fmt.Sprint(id)); existing != nil {
+			return nil, fmt.Errorf("a document with the id %v already exists", id)
+		}
+		if n, err := strconv.ParseInt(fmt.Sprint(id), 10, 64); err == nil && n >= c.nextID {
+			c.nextID = n + 1
+		}
+	}
+
+	ts := timestamp(now)
+	if _, ok := doc["createdAt"]; !ok {
+		doc["createdAt"] = ts
+	}
+	if _, ok := doc["updatedAt"]; !ok {
+		doc["updatedAt"] = ts
+	}
+
+	c.docs = append(c.docs, doc)
+	return doc, nil
+}
+
+// timestamp formats the time as Payload does.
+func timestamp(t time.Time) string {
+	return t.UTC().Format("2006-01-02T15:04:05.000Z07:00")
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]any{
+		"errors": payloadcms.Errors{{Message: message}},
+	})
+}
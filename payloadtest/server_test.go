@@ -0,0 +1,425 @@
+package payloadtest
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ainsleyclark/go-payloadcms"
+)
+
+type post struct {
+	ID        int       `json:"id"`
+	Title     string    `json:"title"`
+	Slug      string    `json:"slug"`
+	Views     int       `json:"views"`
+	Tags      []string  `json:"tags"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+var now = time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+// setup starts a server seeded with testdata/fixtures.json and returns a
+// client for it.
+func setup(t *testing.T, options ...Option) (*Server, *payloadcms.Client) {
+	t.Helper()
+
+	srv := NewServer(append([]Option{WithClock(func() time.Time { return now })}, options...)...)
+	t.Cleanup(srv.Close)
+	require.NoError(t, srv.LoadFile("testdata/fixtures.json"))
+
+	client, err := payloadcms.New(payloadcms.WithBaseURL(srv.URL))
+	require.NoError(t, err)
+	return srv, client
+}
+
+func TestServer_Collections(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	t.Run("FindByID", func(t *testing.T) {
+		t.Parallel()
+
+		_, client := setup(t)
+		var p post
+		_, err := client.Collections.FindByID(ctx, "posts", 1, &p)
+		require.NoError(t, err)
+		assert.Equal(t, "Hello", p.Title)
+		assert.Equal(t, now, p.CreatedAt)
+	})
+
+	t.Run("FindBySlug", func(t *testing.T) {
+		t.Parallel()
+
+		_, client := setup(t)
+		var p post
+		_, err := client.Collections.FindBySlug(ctx, "posts", "world", &p)
+		require.NoError(t, err)
+		assert.Equal(t, 2, p.ID)
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		t.Parallel()
+
+		_, client := setup(t)
+		resp, err := client.Collections.FindByID(ctx, "posts", 99, nil)
+		require.Error(t, err)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+		assert.Equal(t, "Not Found", resp.Errors.Error())
+	})
+
+	t.Run("Create", func(t *testing.T) {
+		t.Parallel()
+
+		srv, client := setup(t)
+		resp, err := client.Collections.Create(ctx, "posts", map[string]any{"title": "New"})
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+
+		doc, ok := srv.Doc("posts", 4)
+		require.True(t, ok)
+		assert.Equal(t, "New", doc["title"])
+		assert.Equal(t, "2024-06-01T12:00:00.000Z", doc["createdAt"])
+	})
+
+	t.Run("UpdateByID", func(t *testing.T) {
+		t.Parallel()
+
+		var elapsed atomic.Int64
+		_, client := setup(t, WithClock(func() time.Time {
+			return now.Add(time.Duration(elapsed.Load()))
+		}))
+		elapsed.Store(int64(time.Hour))
+
+		_, err := client.Collections.UpdateByID(ctx, "posts", 2, map[string]any{"title": "Updated", "id": 5})
+		require.NoError(t, err)
+
+		var p post
+		_, err = client.Collections.FindByID(ctx, "posts", 2, &p)
+		require.NoError(t, err)
+		assert.Equal(t, "Updated", p.Title)
+		assert.Equal(t, "world", p.Slug)
+		assert.Equal(t, now, p.CreatedAt)
+		assert.Equal(t, now.Add(time.Hour), p.UpdatedAt)
+	})
+
+	t.Run("DeleteByID", func(t *testing.T) {
+		t.Parallel()
+
+		srv, client := setup(t)
+		_, err := client.Collections.DeleteByID(ctx, "posts", 1)
+		require.NoError(t, err)
+
+		_, ok := srv.Doc("posts", 1)
+		assert.False(t, ok)
+		assert.Len(t, srv.Docs("posts"), 2)
+
+		_, err = client.Collections.DeleteByID(ctx, "posts", 1)
+		assert.Error(t, err)
+	})
+}
+
+func TestServer_List(t *testing.T) {
+	t.Parallel()
+
+	tt := map[string]struct {
+		params    payloadcms.ListParams
+		want      []int
+		wantTotal int
+		wantNext  bool
+	}{
+		"Default": {
+			want:      []int{1, 2, 3},
+			wantTotal: 3,
+		},
+		"Equals": {
+			params:    payloadcms.ListParams{Where: payloadcms.Query().Equals("title", "World")},
+			want:      []int{2},
+			wantTotal: 1,
+		},
+		"Has Many": {
+			params:    payloadcms.ListParams{Where: payloadcms.Query().Equals("tags", "go")},
+			want:      []int{1, 3},
+			wantTotal: 2,
+		},
+		"Greater Than": {
+			params:    payloadcms.ListParams{Where: payloadcms.Query().GreaterThan("views", "5")},
+			want:      []int{1, 3},
+			wantTotal: 2,
+		},
+		"In": {
+			params:    payloadcms.ListParams{Where: payloadcms.Query().In("id", []string{"1", "3"})},
+			want:      []int{1, 3},
+			wantTotal: 2,
+		},
+		"Exists": {
+			params:    payloadcms.ListParams{Where: payloadcms.Query().Exists("tags", false)},
+			want:      []int{2},
+			wantTotal: 1,
+		},
+		"Combined": {
+			params:    payloadcms.ListParams{Where: payloadcms.Query().NotEquals("id", "1").LessThan("views", "20")},
+			want:      []int{2},
+			wantTotal: 1,
+		},
+		"Or": {
+			params: payloadcms.ListParams{Where: payloadcms.Query().Or(
+				payloadcms.Query().Equals("title", "Hello").GreaterThan("views", "15"),
+			)},
+			want:      []int{1, 3},
+			wantTotal: 2,
+		},
+		"And": {
+			params: payloadcms.ListParams{Where: payloadcms.Query().And(
+				payloadcms.Query().Equals("tags", "go").LessThan("views", "15"),
+			)},
+			want:      []int{1},
+			wantTotal: 1,
+		},
+		"Sort": {
+			params:    payloadcms.ListParams{Sort: "views"},
+			want:      []int{2, 1, 3},
+			wantTotal: 3,
+		},
+		"Sort Descending": {
+			params:    payloadcms.ListParams{Sort: "-views"},
+			want:      []int{3, 1, 2},
+			wantTotal: 3,
+		},
+		"Sort Multiple": {
+			params:    payloadcms.ListParams{Sort: "-tags,views"},
+			want:      []int{1, 3, 2},
+			wantTotal: 3,
+		},
+		"Limit": {
+			params:    payloadcms.ListParams{Limit: 2},
+			want:      []int{1, 2},
+			wantTotal: 3,
+			wantNext:  true,
+		},
+		"Page": {
+			params:    payloadcms.ListParams{Limit: 2, Page: 2},
+			want:      []int{3},
+			wantTotal: 3,
+		},
+	}
+
+	for name, test := range tt {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, client := setup(t)
+			var list payloadcms.ListResponse[post]
+			_, err := client.Collections.List(context.Background(), "posts", test.params, &list)
+			require.NoError(t, err)
+
+			var ids []int
+			for _, p := range list.Docs {
+				ids = append(ids, p.ID)
+			}
+			assert.Equal(t, test.want, ids)
+			assert.Equal(t, test.wantTotal, list.TotalDocs)
+			assert.Equal(t, test.wantNext, list.HasNextPage)
+		})
+	}
+
+	t.Run("Unsupported Query", func(t *testing.T) {
+		t.Parallel()
+
+		_, client := setup(t)
+		resp, err := client.Collections.List(context.Background(), "posts", payloadcms.ListParams{}, nil,
+			payloadcms.WithQueryParam("where[location][near]", "1,2"),
+		)
+		require.Error(t, err)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+}
+
+func TestServer_Globals(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	srv, client := setup(t)
+
+	var settings struct {
+		SiteName string `json:"siteName"`
+		Footer   string `json:"footer"`
+	}
+	_, err := client.Globals.Get(ctx, "settings", &settings)
+	require.NoError(t, err)
+	assert.Equal(t, "Example", settings.SiteName)
+
+	_, err = client.Globals.Update(ctx, "settings", map[string]any{"footer": "Bye"})
+	require.NoError(t, err)
+
+	_, err = client.Globals.Get(ctx, "settings", &settings)
+	require.NoError(t, err)
+	assert.Equal(t, "Example", settings.SiteName)
+	assert.Equal(t, "Bye", settings.Footer)
+
+	doc, ok := srv.Global("settings")
+	require.True(t, ok)
+	assert.Equal(t, "settings", doc["globalType"])
+
+	_, ok = srv.Global("footer")
+	assert.False(t, ok)
+}
+
+func TestServer_Media(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, image.NewGray(image.Rect(0, 0, 30, 20))))
+	img := buf.Bytes()
+
+	t.Run("Upload", func(t *testing.T) {
+		t.Parallel()
+
+		srv, client := setup(t)
+		var doc payloadcms.MediaDoc
+		_, err := client.Media.Upload(ctx, bytes.NewReader(img), map[string]any{"alt": "Image"}, &doc, payloadcms.MediaOptions{
+			FileName: "image",
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, "image.png", doc.Filename)
+		assert.Equal(t, "image/png", doc.MimeType)
+		assert.Equal(t, int64(len(img)), doc.Filesize)
+		assert.Equal(t, 30, doc.Width)
+		assert.Equal(t, 20, doc.Height)
+
+		stored, ok := srv.Doc("media", doc.ID)
+		require.True(t, ok)
+		assert.Equal(t, "Image", stored["alt"])
+
+		file, ok := srv.File("media", "image.png")
+		require.True(t, ok)
+		assert.Equal(t, img, file)
+
+		rc, err := client.Media.Download(ctx, "media", doc.ID, "")
+		require.NoError(t, err)
+		defer rc.Close()
+		got, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		assert.Equal(t, img, got)
+	})
+
+	t.Run("Unique Filenames", func(t *testing.T) {
+		t.Parallel()
+
+		_, client := setup(t)
+		var names []string
+		for range 2 {
+			var doc payloadcms.MediaDoc
+			_, err := client.Media.Upload(ctx, strings.NewReader("hello"), nil, &doc, payloadcms.MediaOptions{FileName: "notes"})
+			require.NoError(t, err)
+			names = append(names, doc.Filename)
+		}
+		assert.Equal(t, []string{"notes.txt", "notes-1.txt"}, names)
+	})
+
+	t.Run("Replace", func(t *testing.T) {
+		t.Parallel()
+
+		srv, client := setup(t)
+		var doc payloadcms.MediaDoc
+		_, err := client.Media.Upload(ctx, strings.NewReader("hello"), map[string]any{"alt": "Notes"}, &doc, payloadcms.MediaOptions{FileName: "notes"})
+		require.NoError(t, err)
+
+		_, err = client.Media.Replace(ctx, doc.ID, bytes.NewReader(img), nil, &doc, payloadcms.MediaOptions{FileName: "image"})
+		require.NoError(t, err)
+		assert.Equal(t, "image.png", doc.Filename)
+
+		stored, _ := srv.Doc("media", doc.ID)
+		assert.Equal(t, "Notes", stored["alt"])
+		_, ok := srv.File("media", "notes.txt")
+		assert.False(t, ok)
+	})
+}
+
+func TestServer_Auth(t *testing.T) {
+	t.Parallel()
+
+	srv, client := setup(t, WithAPIKey("secret"))
+
+	resp, err := client.Collections.FindByID(context.Background(), "posts", 1, nil)
+	require.Error(t, err)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	authed, err := payloadcms.New(payloadcms.WithBaseURL(srv.URL), payloadcms.WithAPIKey("secret"))
+	require.NoError(t, err)
+	_, err = authed.Collections.FindByID(context.Background(), "posts", 1, nil)
+	assert.NoError(t, err)
+
+	t.Run("Files Are Public", func(t *testing.T) {
+		var doc payloadcms.MediaDoc
+		_, err := authed.Media.Upload(context.Background(), strings.NewReader("hello"), nil, &doc, payloadcms.MediaOptions{
+			FileName:    "notes.txt",
+			ContentType: "text/plain",
+		})
+		require.NoError(t, err)
+
+		res, err := http.Get(srv.URL + doc.URL)
+		require.NoError(t, err)
+		defer res.Body.Close()
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+	})
+
+	t.Run("Collection Named File", func(t *testing.T) {
+		require.NoError(t, srv.Seed("file", map[string]any{"id": 1}))
+
+		res, err := http.Get(srv.URL + "/api/file/1")
+		require.NoError(t, err)
+		defer res.Body.Close()
+		assert.Equal(t, http.StatusForbidden, res.StatusCode)
+	})
+}
+
+func TestServer_Seed(t *testing.T) {
+	t.Parallel()
+
+	srv := NewServer()
+	defer srv.Close()
+
+	require.NoError(t, srv.Seed("posts",
+		map[string]any{"title": "First"},
+		post{ID: 10, Title: "Tenth"},
+		map[string]any{"title": "Eleventh"},
+	))
+	require.NoError(t, srv.SeedGlobal("settings", map[string]any{"siteName": "Seeded"}))
+
+	var ids []string
+	for _, doc := range srv.Docs("posts") {
+		ids = append(ids, fmt.Sprint(doc["id"]))
+	}
+	assert.Equal(t, []string{"1", "10", "11"}, ids)
+
+	err := srv.Seed("posts", map[string]any{"id": 10})
+	assert.ErrorContains(t, err, "already exists")
+
+	err = srv.LoadJSON(strings.NewReader(`{"collections": {"pages": [{"title": "Home"}]}}`))
+	require.NoError(t, err)
+	doc, ok := srv.Doc("pages", 1)
+	require.True(t, ok)
+	assert.Equal(t, "Home", doc["title"])
+
+	srv.Reset()
+	assert.Empty(t, srv.Docs("posts"))
+	_, ok = srv.Global("settings")
+	assert.False(t, ok)
+}
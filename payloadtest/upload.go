@@ -0,0 +1,125 @@
+package payloadtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+
+	// Register the decoders used to read the dimensions of images.
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+
+	"github.com/ainsleyclark/go-payloadcms"
+)
+
+// maxMemory is the maximum size of a multipart form held in memory.
+const maxMemory = 32 << 20
+
+// file is a file uploaded to a collection.
+type file struct {
+	data     []byte
+	mimeType string
+}
+
+// upload is the file part of a multipart request.
+type upload struct {
+	filename string
+	mimeType string
+	data     []byte
+}
+
+// readBody decodes the fields of a JSON or multipart request, along with
+// the file of a multipart request.
+func readBody(r *http.Request) (Doc, *upload, error) {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != "multipart/form-data" {
+		fields, err := decodeDoc(r.Body)
+		return fields, nil, err
+	}
+
+	if err := r.ParseMultipartForm(maxMemory); err != nil {
+		return nil, nil, fmt.Errorf("parsing multipart form: %w", err)
+	}
+
+	fields := Doc{}
+	if payload := r.FormValue("_payload"); payload != "" {
+		var err error
+		if fields, err = decodeDoc(strings.NewReader(payload)); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	f, header, err := r.FormFile("file")
+	if errors.Is(err, http.ErrMissingFile) {
+		return fields, nil, nil
+	} else if err != nil {
+		return nil, nil, fmt.Errorf("reading file: %w", err)
+	}
+	defer f.Close()
+
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return nil, nil, fmt.Errorf("reading file: %w", err)
+	}
+
+	mimeType := header.Header.Get("Content-Type")
+	if mimeType == "" || mimeType == "application/octet-stream" {
+		mimeType = http.DetectContentType(data)
+	}
+
+	return fields, &upload{
+		filename: path.Base(header.Filename),
+		mimeType: mimeType,
+		data:     data,
+	}, nil
+}
+
+// decodeDoc decodes a JSON object, where an empty body is an empty
+// document.
+func decodeDoc(r io.Reader) (Doc, error) {
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+
+	doc := Doc{}
+	if err := dec.Decode(&doc); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("decoding body: %w", err)
+	}
+	if doc == nil {
+		doc = Doc{}
+	}
+	return doc, nil
+}
+
+// store saves the file under a unique filename, returning the upload
+// fields Payload sets on the document.
+func (c *collection) store(name payloadcms.Collection, u *upload) Doc {
+	filename := u.filename
+	ext := path.Ext(filename)
+	for i := 1; ; i++ {
+		if _, ok := c.files[filename]; !ok {
+			break
+		}
+		filename = fmt.Sprintf("%s-%d%s", strings.TrimSuffix(u.filename, ext), i, ext)
+	}
+	c.files[filename] = file{data: u.data, mimeType: u.mimeType}
+
+	fields := Doc{
+		"filename": filename,
+		"mimeType": u.mimeType,
+		"filesize": len(u.data),
+		"url":      fmt.Sprintf("/api/%s/file/%s", name, filename),
+	}
+	if cfg, _, err := image.DecodeConfig(bytes.NewReader(u.data)); err == nil {
+		fields["width"] = cfg.Width
+		fields["height"] = cfg.Height
+	}
+	return fields
+}
@@ -0,0 +1,554 @@
+package payloadcms
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Poller emits the changes made to a collection by polling List for
+// documents whose updatedAt is at or after a checkpoint, for installs of
+// Payload that can't send webhooks.
+//
+// Documents updated at the same time as the checkpoint are remembered, so
+// that ties aren't emitted twice or skipped. Deletes can't be seen by
+// updatedAt, so the IDs of the collection are reconciled periodically
+// against the IDs listed by the last reconciliation and emitted since.
+// These IDs are kept in memory rather than in the checkpoint, so deletes
+// made while the poller isn't running are only found for the documents
+// it has emitted since it started.
+//
+// Events are delivered at least once, a change may be emitted again if
+// the poller stops before its checkpoint is saved.
+type Poller struct {
+	service    CollectionService
+	collection Collection
+	config     pollerConfig
+	now        func() time.Time
+
+	// mu serializes polls and reconciliations.
+	mu           sync.Mutex
+	known        map[string]struct{}
+	reconciledAt time.Time
+}
+
+// PollerOption is a functional option type that allows us to configure a Poller.
+type PollerOption func(*pollerConfig)
+
+type pollerConfig struct {
+	store     CheckpointStore
+	key       string
+	interval  time.Duration
+	reconcile time.Duration
+	limit     int
+	onError   func(err error)
+	opts      []RequestOption
+}
+
+// ChangeOperation is the kind of change emitted by a Poller.
+type ChangeOperation string
+
+// Operations emitted by a Poller.
+const (
+	ChangeCreated ChangeOperation = "created"
+	ChangeUpdated ChangeOperation = "updated"
+	ChangeDeleted ChangeOperation = "deleted"
+)
+
+// ChangeEvent is a change to a document found by a Poller.
+type ChangeEvent struct {
+	Collection Collection
+	Operation  ChangeOperation
+	ID         string
+	// Doc is the document after the change, which is nil for deletes.
+	Doc json.RawMessage
+	// UpdatedAt is the updatedAt of the document, which is zero for
+	// deletes.
+	UpdatedAt time.Time
+}
+
+const (
+	// DefaultPollInterval is the default interval between polls.
+	DefaultPollInterval = 30 * time.Second
+	// DefaultReconcileInterval is the default interval between
+	// reconciliations of the IDs of the collection.
+	DefaultReconcileInterval = 10 * time.Minute
+	// DefaultPollLimit is the default amount of documents requested per page.
+	DefaultPollLimit = 100
+)
+
+// WithCheckpointStore sets the store the checkpoint is saved to, so
+// polling resumes where it left off after a restart. Defaults to an
+// in-memory store.
+func WithCheckpointStore(store CheckpointStore) PollerOption {
+	return func(c *pollerConfig) {
+		c.store = store
+	}
+}
+
+// WithCheckpointKey sets the key the checkpoint is saved under, which
+// defaults to the collection slug. Use it when several pollers of the
+// same collection share a store.
+func WithCheckpointKey(key string) PollerOption {
+	return func(c *pollerConfig) {
+		c.key = key
+	}
+}
+
+// WithPollInterval sets the interval between polls.
+func WithPollInterval(d time.Duration) PollerOption {
+	return func(c *pollerConfig) {
+		c.interval = d
+	}
+}
+
+// WithReconcileInterval sets the interval between reconciliations of
+// the IDs of the collection, which find deleted documents. Reconciling
+// lists every document, so it's done far less often than polling.
+// Zero disables reconciliation, and so deletes.
+func WithReconcileInterval(d time.Duration) PollerOption {
+	return func(c *pollerConfig) {
+		c.reconcile = d
+	}
+}
+
+// WithPollLimit sets the amount of documents requested per page.
+func WithPollLimit(n int) PollerOption {
+	return func(c *pollerConfig) {
+		c.limit = n
+	}
+}
+
+// WithPollErrorHandler sets the function called with the errors of
+// polls made by Run, which then carries on at the next interval. Without
+// it, Run returns the first error.
+func WithPollErrorHandler(fn func(err error)) PollerOption {
+	return func(c *pollerConfig) {
+		c.onError = fn
+	}
+}
+
+// WithPollerRequestOptions sets the request options that are passed to
+// every List request, for example WithDepth(0). WithNoCache is always
+// passed as well, so polls aren't served from the response cache.
+func WithPollerRequestOptions(opts ...RequestOption) PollerOption {
+	return func(c *pollerConfig) {
+		c.opts = opts
+	}
+}
+
+// NewPoller creates a new Poller for the given collection.
+func NewPoller(service CollectionService, collection Collection, options ...PollerOption) *Poller {
+	cfg := pollerConfig{
+		key:       string(collection),
+		interval:  DefaultPollInterval,
+		reconcile: DefaultReconcileInterval,
+		limit:     DefaultPollLimit,
+	}
+	for _, opt := range options {
+		opt(&cfg)
+	}
+	if cfg.store == nil {
+		cfg.store = NewMemoryCheckpointStore()
+	}
+	if cfg.interval <= 0 {
+		cfg.interval = DefaultPollInterval
+	}
+	if cfg.limit <= 0 {
+		cfg.limit = DefaultPollLimit
+	}
+	// Polls must see the latest changes, not responses cached by WithCache.
+	cfg.opts = append(slices.Clone(cfg.opts), WithNoCache())
+	return &Poller{
+		service:    service,
+		collection: collection,
+		config:     cfg,
+		now:        time.Now,
+		known:      make(map[string]struct{}),
+	}
+}
+
+// Run polls the collection until the context is cancelled, sending the
+// changes to events and returning the error of the context. The first
+// poll is made immediately, and the IDs are reconciled on the polls at
+// which the reconcile interval has passed.
+func (p *Poller) Run(ctx context.Context, events chan<- ChangeEvent) error {
+	ticker := time.NewTicker(p.config.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := p.Poll(ctx, events); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if p.config.onError == nil {
+				return err
+			}
+			p.config.onError(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Poll sends the documents created or updated since the checkpoint to
+// events, then reconciles the IDs of the collection if the reconcile
+// interval has passed.
+func (p *Poller) Poll(ctx context.Context, events chan<- ChangeEvent) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	cp, err := p.load(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := p.changes(ctx, &cp, events); err != nil {
+		return err
+	}
+
+	if p.config.reconcile > 0 && p.now().Sub(p.reconciledAt) >= p.config.reconcile {
+		return p.reconcile(ctx, &cp, events)
+	}
+
+	return nil
+}
+
+// Reconcile lists the IDs of the collection and sends a delete to events
+// for every ID that was listed by the last reconciliation or emitted
+// since, but no longer exists.
+func (p *Poller) Reconcile(ctx context.Context, events chan<- ChangeEvent) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	cp, err := p.load(ctx)
+	if err != nil {
+		return err
+	}
+	return p.reconcile(ctx, &cp, events)
+}
+
+// changes pages through the documents updated at or after the
+// checkpoint, advancing it as they're sent. The query is restarted from
+// the first page whenever the checkpoint moves, and only moves on to the
+// next page when a whole page is tied with the checkpoint.
+func (p *Poller) changes(ctx context.Context, cp *Checkpoint, events chan<- ChangeEvent) error {
+	// Documents created after the checkpoint at the start of the poll
+	// haven't been emitted before.
+	since, seenBefore := cp.UpdatedAt, cp.seen()
+	initial := cp.UpdatedAt.IsZero()
+
+	seen := cp.seen()
+	dirty := false
+
+	save := func() error {
+		if !dirty {
+			return nil
+		}
+		cp.Seen = sortedKeys(seen)
+		dirty = false
+		return p.config.store.Save(context.WithoutCancel(ctx), p.config.key, *cp)
+	}
+
+	for page := 1; ; {
+		params := ListParams{Sort: "updatedAt", Limit: p.config.limit, Page: page}
+		if !cp.UpdatedAt.IsZero() {
+			params.Where = Query().GreaterThanEqual("updatedAt", formatTimestamp(cp.UpdatedAt))
+		}
+
+		var list ListResponse[json.RawMessage]
+		if _, err := p.service.List(ctx, p.collection, params, &list, p.config.opts...); err != nil {
+			return fmt.Errorf("polling %s: %w", p.collection, err)
+		}
+
+		advanced := false
+		for _, raw := range list.Docs {
+			doc, err := decodePolledDoc(raw)
+			if err != nil {
+				return fmt.Errorf("polling %s: %w", p.collection, err)
+			}
+			if doc.UpdatedAt.Before(cp.UpdatedAt) {
+				continue
+			}
+			if _, ok := seen[doc.ID]; ok && doc.UpdatedAt.Equal(cp.UpdatedAt) {
+				continue
+			}
+
+			op := ChangeUpdated
+			if _, ok := seenBefore[doc.ID]; doc.CreatedAt.After(since) || (doc.CreatedAt.Equal(since) && !ok) || since.IsZero() {
+				op = ChangeCreated
+			}
+
+			e := ChangeEvent{
+				Collection: p.collection,
+				Operation:  op,
+				ID:         doc.ID,
+				Doc:        raw,
+				UpdatedAt:  doc.UpdatedAt,
+			}
+			select {
+			case events <- e:
+			case <-ctx.Done():
+				if err := save(); err != nil {
+					return err
+				}
+				return ctx.Err()
+			}
+
+			if doc.UpdatedAt.After(cp.UpdatedAt) {
+				cp.UpdatedAt = doc.UpdatedAt
+				clear(seen)
+				advanced = true
+			}
+			seen[doc.ID] = struct{}{}
+			if p.config.reconcile > 0 {
+				p.known[doc.ID] = struct{}{}
+			}
+			dirty = true
+		}
+
+		if err := save(); err != nil {
+			return err
+		}
+
+		if !list.HasNextPage || len(list.Docs) == 0 {
+			break
+		}
+		if advanced {
+			page = 1
+		} else {
+			page++
+		}
+	}
+
+	// Every document has just been emitted, so there's nothing to
+	// reconcile yet.
+	if initial {
+		p.reconciledAt = p.now()
+	}
+
+	return nil
+}
+
+// reconcile lists every ID of the collection and emits a delete for the
+// known IDs that are missing. Pages can shift while they're being listed,
+// so missing IDs are looked up again before they're treated as deleted.
+// The IDs listed then become the known IDs.
+func (p *Poller) reconcile(ctx context.Context, cp *Checkpoint, events chan<- ChangeEvent) error {
+	started := p.now()
+
+	current := make(map[string]struct{}, len(p.known))
+	for page := 1; ; page++ {
+		var list ListResponse[json.RawMessage]
+		_, err := p.service.List(ctx, p.collection, ListParams{Sort: "createdAt", Limit: p.config.limit, Page: page}, &list, p.config.opts...)
+		if err != nil {
+			return fmt.Errorf("reconciling %s: %w", p.collection, err)
+		}
+		for _, raw := range list.Docs {
+			doc, err := decodePolledDoc(raw)
+			if err != nil {
+				return fmt.Errorf("reconciling %s: %w", p.collection, err)
+			}
+			current[doc.ID] = struct{}{}
+		}
+		if !list.HasNextPage || len(list.Docs) == 0 {
+			break
+		}
+	}
+
+	var missing []string
+	for id := range p.known {
+		if _, ok := current[id]; !ok {
+			missing = append(missing, id)
+		}
+	}
+	slices.Sort(missing)
+
+	deleted, err := p.confirmDeleted(ctx, missing)
+	if err != nil {
+		return fmt.Errorf("reconciling %s: %w", p.collection, err)
+	}
+
+	seen := cp.seen()
+	for _, id := range deleted {
+		e := ChangeEvent{
+			Collection: p.collection,
+			Operation:  ChangeDeleted,
+			ID:         id,
+		}
+		select {
+		case events <- e:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		delete(p.known, id)
+		if _, ok := seen[id]; ok {
+			delete(seen, id)
+			cp.Seen = sortedKeys(seen)
+			if err := p.config.store.Save(context.WithoutCancel(ctx), p.config.key, *cp); err != nil {
+				return err
+			}
+		}
+	}
+
+	// The known IDs that weren't deleted are kept along with the IDs
+	// listed, as they were found when they were looked up again.
+	for id := range current {
+		p.known[id] = struct{}{}
+	}
+	p.reconciledAt = started
+	return nil
+}
+
+// confirmDeleted returns the IDs that still can't be found when they're
+// requested directly.
+func (p *Poller) confirmDeleted(ctx context.Context, ids []string) ([]string, error) {
+	var deleted []string
+	for batch := range slices.Chunk(ids, p.config.limit) {
+		var list ListResponse[json.RawMessage]
+		_, err := p.service.List(ctx, p.collection, ListParams{
+			Where: Query().In("id", batch),
+			Limit: len(batch),
+		}, &list, p.config.opts...)
+		if err != nil {
+			return nil, err
+		}
+
+		found := make(map[string]struct{}, len(list.Docs))
+		for _, raw := range list.Docs {
+			doc, err := decodePolledDoc(raw)
+			if err != nil {
+				return nil, err
+			}
+			found[doc.ID] = struct{}{}
+		}
+		for _, id := range batch {
+			if _, ok := found[id]; !ok {
+				deleted = append(deleted, id)
+			}
+		}
+	}
+	return deleted, nil
+}
+
+func (p *Poller) load(ctx context.Context) (Checkpoint, error) {
+	cp, _, err := p.config.store.Load(ctx, p.config.key)
+	if err != nil {
+		return Checkpoint{}, fmt.Errorf("loading checkpoint: %w", err)
+	}
+	return cp, nil
+}
+
+// polledDoc is the part of a document the Poller reads.
+type polledDoc struct {
+	ID        string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func decodePolledDoc(raw json.RawMessage) (polledDoc, error) {
+	var doc struct {
+		ID        any       `json:"id"`
+		CreatedAt time.Time `json:"createdAt"`
+		UpdatedAt time.Time `json:"updatedAt"`
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	if err := dec.Decode(&doc); err != nil {
+		return polledDoc{}, err
+	}
+	if doc.ID == nil {
+		return polledDoc{}, errors.New("document has no id")
+	}
+	return polledDoc{
+		ID:        fmt.Sprint(doc.ID),
+		CreatedAt: doc.CreatedAt,
+		UpdatedAt: doc.UpdatedAt,
+	}, nil
+}
+
+// formatTimestamp formats the time in the format Payload stores
+// timestamps in.
+func formatTimestamp(t time.Time) string {
+	return t.UTC().Format("2006-01-02T15:04:05.000Z07:00")
+}
+
+// Checkpoint is the position of a Poller, saved to a CheckpointStore
+// after changes are emitted. It can be encoded as JSON.
+type Checkpoint struct {
+	// UpdatedAt is the updatedAt of the last document emitted.
+	UpdatedAt time.Time `json:"updatedAt"`
+	// Seen are the IDs of the documents emitted with an updatedAt equal to
+	// UpdatedAt, which are skipped when they're listed again.
+	Seen []string `json:"seen,omitempty"`
+}
+
+func (c Checkpoint) seen() map[string]struct{} {
+	return idSet(c.Seen)
+}
+
+func idSet(ids []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(ids))
+	for _, id := range ids {
+		set[id] = struct{}{}
+	}
+	return set
+}
+
+func sortedKeys(set map[string]struct{}) []string {
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// CheckpointStore saves the checkpoints of pollers, so they resume where
+// they left off. Implementations must be safe for concurrent use.
+type CheckpointStore interface {
+	// Load returns the checkpoint saved under the key, reporting false
+	// if there isn't one.
+	Load(ctx context.Context, key string) (Checkpoint, bool, error)
+	// Save saves the checkpoint under the key.
+	Save(ctx context.Context, key string, cp Checkpoint) error
+}
+
+// MemoryCheckpointStore is an in-memory CheckpointStore, whose
+// checkpoints are lost when the process exits.
+type MemoryCheckpointStore struct {
+	mu          sync.Mutex
+	checkpoints map[string]Checkpoint
+}
+
+// NewMemoryCheckpointStore creates a new MemoryCheckpointStore.
+func NewMemoryCheckpointStore() *MemoryCheckpointStore {
+	return &MemoryCheckpointStore{
+		checkpoints: make(map[string]Checkpoint),
+	}
+}
+
+// Load implements CheckpointStore.
+func (s *MemoryCheckpointStore) Load(_ context.Context, key string) (Checkpoint, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cp, ok := s.checkpoints[key]
+	return cp, ok, nil
+}
+
+// Save implements CheckpointStore.
+func (s *MemoryCheckpointStore) Save(_ context.Context, key string, cp Checkpoint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.checkpoints[key] = cp
+	return nil
+}
@@ -0,0 +1,414 @@
+package payloadcms
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type polledPost struct {
+	ID        int       `json:"id"`
+	Title     string    `json:"title"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// pollServer is a collection of posts that responds to the List requests
+// made by a Poller.
+type pollServer struct {
+	mu    sync.Mutex
+	posts []polledPost
+	fail  bool
+}
+
+var pollEpoch = time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+// put creates or updates the post, setting updatedAt to the epoch plus
+// the given seconds.
+func (s *pollServer) put(id int, at int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ts := pollEpoch.Add(time.Duration(at) * time.Second)
+	for i, p := range s.posts {
+		if p.ID == id {
+			s.posts[i].UpdatedAt = ts
+			return
+		}
+	}
+	s.posts = append(s.posts, polledPost{ID: id, Title: "Post " + strconv.Itoa(id), CreatedAt: ts, UpdatedAt: ts})
+}
+
+func (s *pollServer) delete(id int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.posts = slices.DeleteFunc(s.posts, func(p polledPost) bool { return p.ID == id })
+}
+
+func (s *pollServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.fail {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(`{"errors":[{"message":"Something went wrong."}]}`))
+		return
+	}
+
+	q := r.URL.Query()
+	posts := slices.Clone(s.posts)
+
+	if v := q.Get("where[updatedAt][greater_than_equal]"); v != "" {
+		since, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		posts = slices.DeleteFunc(posts, func(p polledPost) bool { return p.UpdatedAt.Before(since) })
+	}
+	if v := q.Get("where[id][in]"); v != "" {
+		ids := strings.Split(v, ",")
+		posts = slices.DeleteFunc(posts, func(p polledPost) bool { return !slices.Contains(ids, strconv.Itoa(p.ID)) })
+	}
+	switch q.Get("sort") {
+	case "updatedAt":
+		slices.SortStableFunc(posts, func(a, b polledPost) int { return a.UpdatedAt.Compare(b.UpdatedAt) })
+	case "createdAt":
+		slices.SortStableFunc(posts, func(a, b polledPost) int { return a.CreatedAt.Compare(b.CreatedAt) })
+	}
+
+	limit, _ := strconv.Atoi(q.Get("limit"))
+	page, _ := strconv.Atoi(q.Get("page"))
+	page = max(page, 1)
+	start := min((page-1)*limit, len(posts))
+	end := min(start+limit, len(posts))
+
+	_ = json.NewEncoder(w).Encode(ListResponse[polledPost]{
+		Docs:        posts[start:end],
+		TotalDocs:   len(posts),
+		Limit:       limit,
+		Page:        page,
+		HasNextPage: end < len(posts),
+	})
+}
+
+func setupPoller(t *testing.T, options ...PollerOption) (*Poller, *pollServer) {
+	t.Helper()
+
+	server := &pollServer{}
+	client, teardown := Setup(t, server.ServeHTTP)
+	t.Cleanup(teardown)
+
+	p := NewPoller(CollectionServiceOp{Client: client}, "posts", append([]PollerOption{WithPollLimit(2)}, options...)...)
+	return p, server
+}
+
+type change struct {
+	Op ChangeOperation
+	ID string
+}
+
+// poll polls once and returns the changes that were emitted.
+func poll(t *testing.T, fn func(ctx context.Context, events chan<- ChangeEvent) error) []change {
+	t.Helper()
+
+	events := make(chan ChangeEvent, 100)
+	require.NoError(t, fn(context.Background(), events))
+	close(events)
+
+	var got []change
+	for e := range events {
+		assert.Equal(t, Collection("posts"), e.Collection)
+		got = append(got, change{Op: e.Operation, ID: e.ID})
+	}
+	return got
+}
+
+func TestPoller_Poll(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Creates And Updates", func(t *testing.T) {
+		t.Parallel()
+
+		p, server := setupPoller(t)
+		for id := 1; id <= 5; id++ {
+			server.put(id, id)
+		}
+
+		got := poll(t, p.Poll)
+		assert.Equal(t, []change{
+			{ChangeCreated, "1"}, {ChangeCreated, "2"}, {ChangeCreated, "3"}, {ChangeCreated, "4"}, {ChangeCreated, "5"},
+		}, got)
+
+		assert.Empty(t, poll(t, p.Poll))
+
+		server.put(2, 10)
+		server.put(6, 11)
+		assert.Equal(t, []change{{ChangeUpdated, "2"}, {ChangeCreated, "6"}}, poll(t, p.Poll))
+		assert.Empty(t, poll(t, p.Poll))
+	})
+
+	t.Run("Ties", func(t *testing.T) {
+		t.Parallel()
+
+		p, server := setupPoller(t)
+		for id := 1; id <= 5; id++ {
+			server.put(id, 1)
+		}
+
+		got := poll(t, p.Poll)
+		assert.Len(t, got, 5)
+		assert.Empty(t, poll(t, p.Poll))
+
+		// Changes at the same time as the checkpoint are still found.
+		server.put(6, 1)
+		server.put(3, 1)
+		assert.Equal(t, []change{{ChangeCreated, "6"}}, poll(t, p.Poll))
+
+		server.put(3, 2)
+		assert.Equal(t, []change{{ChangeUpdated, "3"}}, poll(t, p.Poll))
+	})
+
+	t.Run("Doc", func(t *testing.T) {
+		t.Parallel()
+
+		p, server := setupPoller(t)
+		server.put(1, 1)
+
+		events := make(chan ChangeEvent, 1)
+		require.NoError(t, p.Poll(context.Background(), events))
+
+		e := <-events
+		AssertEqual(t, pollEpoch.Add(time.Second), e.UpdatedAt)
+		var post polledPost
+		require.NoError(t, json.Unmarshal(e.Doc, &post))
+		AssertEqual(t, "Post 1", post.Title)
+	})
+
+	t.Run("Resumes From Store", func(t *testing.T) {
+		t.Parallel()
+
+		store := NewMemoryCheckpointStore()
+		p, server := setupPoller(t, WithCheckpointStore(store))
+		server.put(1, 1)
+		server.put(2, 2)
+		assert.Len(t, poll(t, p.Poll), 2)
+
+		cp, ok, err := store.Load(context.Background(), "posts")
+		require.NoError(t, err)
+		require.True(t, ok)
+		AssertEqual(t, pollEpoch.Add(2*time.Second), cp.UpdatedAt)
+		assert.Equal(t, []string{"2"}, cp.Seen)
+
+		server.put(3, 3)
+		resumed := NewPoller(p.service, "posts", WithCheckpointStore(store))
+		assert.Equal(t, []change{{ChangeCreated, "3"}}, poll(t, resumed.Poll))
+	})
+
+	t.Run("Bypasses Cache", func(t *testing.T) {
+		t.Parallel()
+
+		server := &pollServer{}
+		client, teardown := Setup(t, server.ServeHTTP)
+		t.Cleanup(teardown)
+		WithCache(NewMemoryCache(10), time.Hour)(client)
+
+		p := NewPoller(CollectionServiceOp{Client: client}, "posts")
+		server.put(1, 1)
+		assert.Equal(t, []change{{ChangeCreated, "1"}}, poll(t, p.Poll))
+		assert.Empty(t, poll(t, p.Poll))
+
+		// The same query as the last poll finds the new document.
+		server.put(2, 1)
+		assert.Equal(t, []change{{ChangeCreated, "2"}}, poll(t, p.Poll))
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		t.Parallel()
+
+		p, server := setupPoller(t)
+		server.fail = true
+
+		err := p.Poll(context.Background(), make(chan ChangeEvent))
+		AssertError(t, err)
+		AssertContains(t, err.Error(), "polling posts")
+	})
+
+	t.Run("Cancelled", func(t *testing.T) {
+		t.Parallel()
+
+		store := NewMemoryCheckpointStore()
+		p, server := setupPoller(t, WithCheckpointStore(store))
+		server.put(1, 1)
+		server.put(2, 2)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		events := make(chan ChangeEvent)
+		go func() {
+			<-events
+			cancel()
+		}()
+
+		err := p.Poll(ctx, events)
+		assert.ErrorIs(t, err, context.Canceled)
+
+		// The first change is saved, so only the second is emitted again.
+		assert.Equal(t, []change{{ChangeCreated, "2"}}, poll(t, p.Poll))
+	})
+}
+
+func TestPoller_Reconcile(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Deletes", func(t *testing.T) {
+		t.Parallel()
+
+		p, server := setupPoller(t)
+		for id := 1; id <= 5; id++ {
+			server.put(id, id)
+		}
+		assert.Len(t, poll(t, p.Poll), 5)
+
+		server.delete(2)
+		server.delete(5)
+		assert.Equal(t, []change{{ChangeDeleted, "2"}, {ChangeDeleted, "5"}}, poll(t, p.Reconcile))
+		assert.Empty(t, poll(t, p.Reconcile))
+	})
+
+	t.Run("On Interval", func(t *testing.T) {
+		t.Parallel()
+
+		p, server := setupPoller(t, WithReconcileInterval(time.Minute))
+		now := pollEpoch
+		p.now = func() time.Time { return now }
+
+		server.put(1, 1)
+		server.put(2, 2)
+		assert.Len(t, poll(t, p.Poll), 2)
+
+		server.delete(1)
+		assert.Empty(t, poll(t, p.Poll))
+
+		now = now.Add(time.Minute)
+		assert.Equal(t, []change{{ChangeDeleted, "1"}}, poll(t, p.Poll))
+	})
+
+	t.Run("After Restart", func(t *testing.T) {
+		t.Parallel()
+
+		store := NewMemoryCheckpointStore()
+		p, server := setupPoller(t, WithCheckpointStore(store))
+		for id := 1; id <= 3; id++ {
+			server.put(id, id)
+		}
+		assert.Len(t, poll(t, p.Poll), 3)
+
+		// The first poll after a restart lists the IDs to reconcile
+		// against, without emitting anything.
+		resumed := NewPoller(p.service, "posts", WithCheckpointStore(store), WithPollLimit(2))
+		assert.Empty(t, poll(t, resumed.Poll))
+
+		server.delete(3)
+		assert.Equal(t, []change{{ChangeDeleted, "3"}}, poll(t, resumed.Reconcile))
+
+		// Only the IDs tied with the checkpoint are saved.
+		cp, _, err := store.Load(context.Background(), "posts")
+		require.NoError(t, err)
+		assert.Empty(t, cp.Seen)
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		t.Parallel()
+
+		p, server := setupPoller(t, WithReconcileInterval(0))
+		server.put(1, 1)
+		assert.Len(t, poll(t, p.Poll), 1)
+
+		server.delete(1)
+		assert.Empty(t, poll(t, p.Poll))
+	})
+}
+
+func TestPoller_Run(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Cancelled", func(t *testing.T) {
+		t.Parallel()
+
+		p, server := setupPoller(t, WithPollInterval(time.Millisecond))
+		server.put(1, 1)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		events := make(chan ChangeEvent)
+		done := make(chan error)
+		go func() { done <- p.Run(ctx, events) }()
+
+		AssertEqual(t, "1", (<-events).ID)
+		server.put(2, 2)
+		AssertEqual(t, "2", (<-events).ID)
+
+		cancel()
+		assert.ErrorIs(t, <-done, context.Canceled)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		t.Parallel()
+
+		p, server := setupPoller(t, WithPollInterval(time.Millisecond))
+		server.fail = true
+
+		err := p.Run(context.Background(), make(chan ChangeEvent))
+		AssertError(t, err)
+	})
+
+	t.Run("Error Handler", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		var errs []error
+		p, server := setupPoller(t,
+			WithPollInterval(time.Millisecond),
+			WithPollErrorHandler(func(err error) {
+				errs = append(errs, err)
+				if len(errs) == 2 {
+					cancel()
+				}
+			}),
+		)
+		server.fail = true
+
+		err := p.Run(ctx, make(chan ChangeEvent))
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Len(t, errs, 2)
+		assert.False(t, errors.Is(errs[0], context.Canceled))
+	})
+}
+
+func TestMemoryCheckpointStore(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	store := NewMemoryCheckpointStore()
+
+	_, ok, err := store.Load(ctx, "posts")
+	AssertNoError(t, err)
+	AssertEqual(t, false, ok)
+
+	cp := Checkpoint{UpdatedAt: pollEpoch, Seen: []string{"1"}}
+	AssertNoError(t, store.Save(ctx, "posts", cp))
+
+	got, ok, err := store.Load(ctx, "posts")
+	AssertNoError(t, err)
+	AssertEqual(t, true, ok)
+	assert.Equal(t, cp, got)
+}
@@ -40,6 +40,12 @@ func (qb *QueryBuilder) GreaterThan(field, value string) *QueryBuilder {
 	return qb
 }
 
+// GreaterThanEqual adds a greater_than_equal filter to the query
+func (qb *QueryBuilder) GreaterThanEqual(field, value string) *QueryBuilder {
+	qb.params.Add(fmt.Sprintf("where[%s][greater_than_equal]", field), value)
+	return qb
+}
+
 // LessThan adds a less_than filter to the query
 func (qb *QueryBuilder) LessThan(field, value string) *QueryBuilder {
 	qb.params.Add(fmt.Sprintf("where[%s][less_than]", field), value)
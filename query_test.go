@@ -28,6 +28,12 @@ func TestQuery(t *testing.T) {
 		assert.Equal(t, "where%5Bfield%5D%5Bgreater_than%5D=10", qb.Build())
 	})
 
+	t.Run("GreaterThanEqual", func(t *testing.T) {
+		t.Parallel()
+		qb := Query().GreaterThanEqual("field", "10")
+		assert.Equal(t, "where%5Bfield%5D%5Bgreater_than_equal%5D=10", qb.Build())
+	})
+
 	t.Run("LessThan", func(t *testing.T) {
 		t.Parallel()
 		qb := Query().LessThan("field", "5")
@@ -0,0 +1,259 @@
+package payloadcms
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// Relation represents a relationship or upload field that points to a
+// single collection. Payload returns the field as a bare ID at depth 0,
+// and as the full document at a depth of 1 or more. Relation handles
+// both forms so that structs don't break when WithDepth changes.
+//
+// Example:
+//
+//	type Post struct {
+//		Author payloadcms.Relation[User] `json:"author"`
+//	}
+//
+// See: https://payloadcms.com/docs/fields/relationship
+type Relation[T any] struct {
+	// ID is the ID of the related document. Numeric IDs are decoded as a
+	// json.Number and string IDs (e.g. MongoDB) as a string.
+	ID any
+	// Value is the related document, which is nil when the field has
+	// not been populated.
+	Value *T
+}
+
+// PolyRelation represents a polymorphic relationship field, where the
+// field can point to more than one collection. Payload returns these
+// fields as an object containing relationTo and value, where value is
+// either a bare ID or the full document depending on the depth.
+//
+// See: https://payloadcms.com/docs/fields/relationship#polymorphic-relationships
+type PolyRelation struct {
+	// RelationTo is the slug of the collection the document belongs to.
+	RelationTo Collection
+	// ID is the ID of the related document.
+	ID any
+	// Value is the raw JSON of the related document, which is empty when
+	// the field has not been populated. Use Decode to unmarshal it.
+	Value json.RawMessage
+}
+
+// errNoRelationID is returned when fetching a relation that has no ID.
+var errNoRelationID = errors.New("relation has no id")
+
+// IsPopulated reports if the related document has been populated.
+func (r Relation[T]) IsPopulated() bool {
+	return r.Value != nil
+}
+
+// IsZero reports if the relation is empty, i.e. it was null or not set.
+func (r Relation[T]) IsZero() bool {
+	return r.ID == nil && r.Value == nil
+}
+
+// Fetch returns the related document. If the relation has not been
+// populated, it is fetched by its ID using the collection service
+// and stored on the relation for subsequent calls.
+func (r *Relation[T]) Fetch(ctx context.Context, s CollectionService, collection Collection, opts ...RequestOption) (*T, error) {
+	if r.Value != nil {
+		return r.Value, nil
+	}
+	if r.ID == nil {
+		return nil, errNoRelationID
+	}
+
+	var v T
+	if _, err := s.FindByID(ctx, collection, r.ID, &v, opts...); err != nil {
+		return nil, err
+	}
+	r.Value = &v
+
+	return r.Value, nil
+}
+
+// UnmarshalJSON decodes either a bare ID or a populated document.
+func (r *Relation[T]) UnmarshalJSON(data []byte) error {
+	*r = Relation[T]{}
+
+	data = bytes.TrimSpace(data)
+	if isJSONNull(data) {
+		return nil
+	}
+
+	if data[0] != '{' {
+		id, err := decodeRelationID(data)
+		if err != nil {
+			return err
+		}
+		r.ID = id
+		return nil
+	}
+
+	id, err := documentID(data)
+	if err != nil {
+		return err
+	}
+
+	var v T
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	r.ID = id
+	r.Value = &v
+
+	return nil
+}
+
+// MarshalJSON encodes the relation in the form it was received, the
+// document if it has been populated, otherwise the ID.
+func (r Relation[T]) MarshalJSON() ([]byte, error) {
+	if r.Value != nil {
+		return json.Marshal(r.Value)
+	}
+	return json.Marshal(r.ID)
+}
+
+// IsZero reports if the relation is empty, i.e. it was null or not set.
+func (p PolyRelation) IsZero() bool {
+	return p.RelationTo == "" && p.ID == nil && len(p.Value) == 0
+}
+
+// IsPopulated reports if the related document has been populated.
+func (p PolyRelation) IsPopulated() bool {
+	return len(p.Value) > 0
+}
+
+// Decode unmarshals the populated document into out.
+func (p PolyRelation) Decode(out any) error {
+	if !p.IsPopulated() {
+		return fmt.Errorf("relation to %s with id %v is not populated", p.RelationTo, p.ID)
+	}
+	return json.Unmarshal(p.Value, out)
+}
+
+// Fetch decodes the related document into out. If the relation has not
+// been populated, it is fetched by its ID from the collection it belongs
+// to and stored on the relation for subsequent calls.
+func (p *PolyRelation) Fetch(ctx context.Context, s CollectionService, out any, opts ...RequestOption) error {
+	if p.IsPopulated() {
+		return p.Decode(out)
+	}
+	if p.ID == nil {
+		return errNoRelationID
+	}
+
+	resp, err := s.FindByID(ctx, p.RelationTo, p.ID, out, opts...)
+	if err != nil {
+		return err
+	}
+	p.Value = resp.Content
+
+	return nil
+}
+
+type polyRelationJSON struct {
+	RelationTo Collection      `json:"relationTo"`
+	Value      json.RawMessage `json:"value"`
+}
+
+// UnmarshalJSON decodes a {relationTo, value} object where value is
+// either a bare ID or a populated document.
+func (p *PolyRelation) UnmarshalJSON(data []byte) error {
+	*p = PolyRelation{}
+
+	if isJSONNull(bytes.TrimSpace(data)) {
+		return nil
+	}
+
+	var raw polyRelationJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	p.RelationTo = raw.RelationTo
+
+	value := bytes.TrimSpace(raw.Value)
+	if len(value) == 0 || isJSONNull(value) {
+		return nil
+	}
+
+	if value[0] != '{' {
+		id, err := decodeRelationID(value)
+		if err != nil {
+			return err
+		}
+		p.ID = id
+		return nil
+	}
+
+	id, err := documentID(value)
+	if err != nil {
+		return err
+	}
+	p.ID = id
+	p.Value = value
+
+	return nil
+}
+
+// MarshalJSON encodes the relation as a {relationTo, value} object,
+// where value is the document if populated, otherwise the ID.
+func (p PolyRelation) MarshalJSON() ([]byte, error) {
+	if p.IsZero() {
+		return []byte("null"), nil
+	}
+	value := p.Value
+	if !p.IsPopulated() {
+		id, err := json.Marshal(p.ID)
+		if err != nil {
+			return nil, err
+		}
+		value = id
+	}
+	return json.Marshal(polyRelationJSON{
+		RelationTo: p.RelationTo,
+		Value:      value,
+	})
+}
+
+// decodeRelationID decodes a bare ID, which is either a number or a string.
+func decodeRelationID(data []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var id any
+	if err := dec.Decode(&id); err != nil {
+		return nil, err
+	}
+
+	switch id.(type) {
+	case json.Number, string:
+		return id, nil
+	default:
+		return nil, fmt.Errorf("relation id must be a string or number, got: %s", data)
+	}
+}
+
+// documentID extracts the ID from a populated document.
+func documentID(data []byte) (any, error) {
+	var doc struct {
+		ID json.RawMessage `json:"id"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if len(doc.ID) == 0 || isJSONNull(doc.ID) {
+		return nil, nil
+	}
+	return decodeRelationID(doc.ID)
+}
+
+func isJSONNull(data []byte) bool {
+	return bytes.Equal(data, []byte("null"))
+}
@@ -0,0 +1,310 @@
+package payloadcms
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type relationDoc struct {
+	Author  Relation[Resource]   `json:"author"`
+	Authors []Relation[Resource] `json:"authors"`
+}
+
+func TestRelation_UnmarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	tt := map[string]struct {
+		input         string
+		wantID        any
+		wantPopulated bool
+		wantErr       bool
+	}{
+		"Numeric ID": {
+			input:  `1`,
+			wantID: json.Number("1"),
+		},
+		"String ID": {
+			input:  `"6639f8e2b7c5e1a1e4d6b6a1"`,
+			wantID: "6639f8e2b7c5e1a1e4d6b6a1",
+		},
+		"Populated": {
+			input:         `{"id": 1, "name": "John Doe"}`,
+			wantID:        json.Number("1"),
+			wantPopulated: true,
+		},
+		"Null": {
+			input: `null`,
+		},
+		"Invalid ID": {
+			input:   `true`,
+			wantErr: true,
+		},
+		"Invalid Document": {
+			input:   `{"id": 1, "name": 1}`,
+			wantErr: true,
+		},
+	}
+
+	for name, test := range tt {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var r Relation[Resource]
+			err := json.Unmarshal([]byte(test.input), &r)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.wantID, r.ID)
+			assert.Equal(t, test.wantPopulated, r.IsPopulated())
+			if test.wantPopulated {
+				assert.Equal(t, defaultResource, *r.Value)
+			}
+		})
+	}
+}
+
+func TestRelation_MarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	tt := map[string]struct {
+		input string
+		want  string
+	}{
+		"ID":        {input: `{"author":1,"authors":[1,"2"]}`, want: `{"author":1,"authors":[1,"2"]}`},
+		"Populated": {input: `{"author":{"id":1,"name":"John Doe"},"authors":[]}`, want: `{"author":{"id":1,"name":"John Doe"},"authors":[]}`},
+		"Null":      {input: `{"author":null,"authors":null}`, want: `{"author":null,"authors":null}`},
+	}
+
+	for name, test := range tt {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var doc relationDoc
+			require.NoError(t, json.Unmarshal([]byte(test.input), &doc))
+
+			got, err := json.Marshal(doc)
+			require.NoError(t, err)
+			assert.JSONEq(t, test.want, string(got))
+		})
+	}
+}
+
+func TestRelation_IsZero(t *testing.T) {
+	t.Parallel()
+	assert.True(t, Relation[Resource]{}.IsZero())
+	assert.False(t, Relation[Resource]{ID: 1}.IsZero())
+}
+
+func TestRelation_Fetch(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Populated", func(t *testing.T) {
+		t.Parallel()
+
+		r := Relation[Resource]{ID: 1, Value: &defaultResource}
+		got, err := r.Fetch(context.Background(), nil, "users")
+		require.NoError(t, err)
+		assert.Equal(t, defaultResource, *got)
+	})
+
+	t.Run("No ID", func(t *testing.T) {
+		t.Parallel()
+
+		r := Relation[Resource]{}
+		_, err := r.Fetch(context.Background(), nil, "users")
+		assert.Error(t, err)
+	})
+
+	t.Run("Fetches By ID", func(t *testing.T) {
+		t.Parallel()
+
+		client, teardown := Setup(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/api/users/1", r.URL.Path)
+			assert.Equal(t, "1", r.URL.Query().Get("depth"))
+			w.WriteHeader(http.StatusOK)
+			_, err := w.Write(defaultBody)
+			AssertNoError(t, err)
+		})
+		defer teardown()
+
+		var r Relation[Resource]
+		require.NoError(t, json.Unmarshal([]byte(`1`), &r))
+
+		got, err := r.Fetch(context.Background(), CollectionServiceOp{Client: client}, "users", WithDepth(1))
+		require.NoError(t, err)
+		assert.Equal(t, defaultResource, *got)
+		assert.True(t, r.IsPopulated())
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		t.Parallel()
+
+		client, teardown := Setup(t, func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		})
+		defer teardown()
+
+		r := Relation[Resource]{ID: 1}
+		_, err := r.Fetch(context.Background(), CollectionServiceOp{Client: client}, "users")
+		assert.Error(t, err)
+		assert.False(t, r.IsPopulated())
+	})
+}
+
+func TestPolyRelation_UnmarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	tt := map[string]struct {
+		input          string
+		wantRelationTo Collection
+		wantID         any
+		wantPopulated  bool
+		wantErr        bool
+	}{
+		"ID": {
+			input:          `{"relationTo": "posts", "value": 1}`,
+			wantRelationTo: "posts",
+			wantID:         json.Number("1"),
+		},
+		"Populated": {
+			input:          `{"relationTo": "users", "value": {"id": "abc", "name": "John Doe"}}`,
+			wantRelationTo: "users",
+			wantID:         "abc",
+			wantPopulated:  true,
+		},
+		"Null Value": {
+			input:          `{"relationTo": "users", "value": null}`,
+			wantRelationTo: "users",
+		},
+		"Null": {
+			input: `null`,
+		},
+		"Invalid": {
+			input:   `[]`,
+			wantErr: true,
+		},
+		"Invalid ID": {
+			input:   `{"relationTo": "users", "value": false}`,
+			wantErr: true,
+		},
+	}
+
+	for name, test := range tt {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var p PolyRelation
+			err := json.Unmarshal([]byte(test.input), &p)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.wantRelationTo, p.RelationTo)
+			assert.Equal(t, test.wantID, p.ID)
+			assert.Equal(t, test.wantPopulated, p.IsPopulated())
+		})
+	}
+}
+
+func TestPolyRelation_MarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	for _, input := range []string{
+		`{"relationTo":"posts","value":1}`,
+		`{"relationTo":"users","value":{"id":"abc","name":"John Doe"}}`,
+		`null`,
+	} {
+		var p PolyRelation
+		require.NoError(t, json.Unmarshal([]byte(input), &p))
+
+		got, err := json.Marshal(p)
+		require.NoError(t, err)
+		assert.JSONEq(t, input, string(got))
+	}
+}
+
+func TestPolyRelation_IsZero(t *testing.T) {
+	t.Parallel()
+	assert.True(t, PolyRelation{}.IsZero())
+	assert.False(t, PolyRelation{RelationTo: "posts", ID: 1}.IsZero())
+}
+
+func TestPolyRelation_MarshalJSON_Null(t *testing.T) {
+	t.Parallel()
+
+	type doc struct {
+		Featured PolyRelation `json:"featured,omitempty"`
+	}
+
+	var d doc
+	require.NoError(t, json.Unmarshal([]byte(`{"featured":null}`), &d))
+	assert.True(t, d.Featured.IsZero())
+
+	got, err := json.Marshal(d)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"featured":null}`, string(got))
+}
+
+func TestPolyRelation_Decode(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Populated", func(t *testing.T) {
+		t.Parallel()
+
+		p := PolyRelation{RelationTo: "users", ID: 1, Value: defaultBody}
+		var r Resource
+		require.NoError(t, p.Decode(&r))
+		assert.Equal(t, defaultResource, r)
+	})
+
+	t.Run("Not Populated", func(t *testing.T) {
+		t.Parallel()
+
+		p := PolyRelation{RelationTo: "users", ID: 1}
+		assert.Error(t, p.Decode(&Resource{}))
+	})
+}
+
+func TestPolyRelation_Fetch(t *testing.T) {
+	t.Parallel()
+
+	client, teardown := Setup(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/users/1", r.URL.Path)
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write(defaultBody)
+		AssertNoError(t, err)
+	})
+	defer teardown()
+
+	service := CollectionServiceOp{Client: client}
+
+	t.Run("Fetches By ID", func(t *testing.T) {
+		p := PolyRelation{RelationTo: "users", ID: 1}
+
+		var r Resource
+		require.NoError(t, p.Fetch(context.Background(), service, &r))
+		assert.Equal(t, defaultResource, r)
+		assert.True(t, p.IsPopulated())
+	})
+
+	t.Run("Populated", func(t *testing.T) {
+		p := PolyRelation{RelationTo: "users", ID: 1, Value: defaultBody}
+
+		var r Resource
+		require.NoError(t, p.Fetch(context.Background(), nil, &r))
+		assert.Equal(t, defaultResource, r)
+	})
+
+	t.Run("No ID", func(t *testing.T) {
+		p := PolyRelation{RelationTo: "users"}
+		assert.Error(t, p.Fetch(context.Background(), service, &Resource{}))
+	})
+}
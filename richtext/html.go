@@ -0,0 +1,171 @@
+package richtext
+
+import (
+	"fmt"
+	"html"
+	"net/url"
+	"strings"
+)
+
+// HTMLRenderer renders a node tree to HTML.
+type HTMLRenderer struct {
+	renderers nodeRenderers
+}
+
+var _ Renderer = (*HTMLRenderer)(nil)
+
+// NewHTMLRenderer creates a new HTML renderer with the default node renderers.
+func NewHTMLRenderer() *HTMLRenderer {
+	return &HTMLRenderer{renderers: make(nodeRenderers)}
+}
+
+// Register overrides the rendering of a node type. It returns the
+// renderer so calls can be chained.
+func (r *HTMLRenderer) Register(t NodeType, fn NodeRenderer) *HTMLRenderer {
+	r.renderers[t] = fn
+	return r
+}
+
+// Render renders the node and its children to HTML.
+func (r *HTMLRenderer) Render(n *Node) string {
+	return r.renderers.render(n, r.node)
+}
+
+func (r *HTMLRenderer) node(n *Node, children string) string {
+	switch n.Type {
+	case NodeRoot:
+		return children
+	case NodeParagraph:
+		return "<p" + alignAttr(n) + ">" + children + "</p>"
+	case NodeHeading:
+		tag := headingTag(n.Tag)
+		return "<" + tag + alignAttr(n) + ">" + children + "</" + tag + ">"
+	case NodeQuote:
+		return "<blockquote>" + children + "</blockquote>"
+	case NodeList:
+		if n.ListType == ListNumber {
+			return "<ol>" + children + "</ol>"
+		}
+		return "<ul>" + children + "</ul>"
+	case NodeListItem:
+		return r.listItem(n, children)
+	case NodeLink:
+		return r.link(n, children)
+	case NodeUpload:
+		return r.upload(n)
+	case NodeRelationship:
+		return r.relationship(n)
+	case NodeText:
+		return r.text(n)
+	case NodeLineBreak:
+		return "<br>"
+	case NodeHorizontalRule:
+		return "<hr>"
+	default:
+		return children
+	}
+}
+
+func (r *HTMLRenderer) listItem(n *Node, children string) string {
+	if n.ListType == ListCheck {
+		checked := ""
+		if n.Checked {
+			checked = " checked"
+		}
+		return `<li><input type="checkbox" disabled` + checked + ">" + children + "</li>"
+	}
+	return "<li>" + children + "</li>"
+}
+
+func (r *HTMLRenderer) link(n *Node, children string) string {
+	if !safeURL(n.URL) {
+		return children
+	}
+	attrs := ` href="` + html.EscapeString(n.URL) + `"`
+	if n.NewTab {
+		attrs += ` target="_blank" rel="noopener noreferrer"`
+	}
+	return "<a" + attrs + ">" + children + "</a>"
+}
+
+func (r *HTMLRenderer) upload(n *Node) string {
+	doc, ok := n.Doc()
+	if !ok || doc.URL == "" {
+		return ""
+	}
+	src := html.EscapeString(doc.URL)
+	if strings.HasPrefix(doc.MimeType, "image/") {
+		return `<img src="` + src + `" alt="` + html.EscapeString(doc.Alt) + `">`
+	}
+	name := doc.Filename
+	if name == "" {
+		name = doc.URL
+	}
+	return `<a href="` + src + `">` + html.EscapeString(name) + "</a>"
+}
+
+func (r *HTMLRenderer) relationship(n *Node) string {
+	id := strings.Trim(string(n.Value), `"`)
+	if doc, ok := n.Doc(); ok {
+		id = fmt.Sprint(doc.ID)
+	}
+	return fmt.Sprintf(`<div data-relation-to="%s" data-id="%s"></div>`,
+		html.EscapeString(n.RelationTo),
+		html.EscapeString(id),
+	)
+}
+
+func (r *HTMLRenderer) text(n *Node) string {
+	text := strings.ReplaceAll(html.EscapeString(n.Text), "\n", "<br>")
+	wrap := func(flag Format, tag string) {
+		if n.Format.Has(flag) {
+			text = "<" + tag + ">" + text + "</" + tag + ">"
+		}
+	}
+	wrap(FormatCode, "code")
+	wrap(FormatSubscript, "sub")
+	wrap(FormatSuperscript, "sup")
+	wrap(FormatStrikethrough, "s")
+	wrap(FormatUnderline, "u")
+	wrap(FormatItalic, "em")
+	wrap(FormatBold, "strong")
+	return text
+}
+
+func alignAttr(n *Node) string {
+	switch n.Align {
+	case "left", "center", "right", "justify":
+		return ` style="text-align:` + n.Align + `"`
+	default:
+		return ""
+	}
+}
+
+// headingTag returns the tag if it's h1 to h6, otherwise h2.
+func headingTag(tag string) string {
+	switch tag {
+	case "h1", "h2", "h3", "h4", "h5", "h6":
+		return tag
+	default:
+		return "h2"
+	}
+}
+
+// safeURL reports whether the URL can be used as a link. Relative URLs,
+// fragments and the http, https, mailto and tel schemes are allowed so
+// that script URLs such as javascript:alert(1) are never rendered.
+func safeURL(raw string) bool {
+	if raw == "" {
+		return false
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return false
+	}
+	switch u.Scheme {
+	case "", "http", "https", "mailto", "tel":
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,123 @@
+package richtext
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTMLRenderer_Render(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Fixture", func(t *testing.T) {
+		t.Parallel()
+
+		want := `<h1>Hello World</h1>` +
+			`<p style="text-align:center">This is <strong>bold</strong> and <em>italic</em>, <code>code</code><br>` +
+			`<a href="https://payloadcms.com" target="_blank" rel="noopener noreferrer">Payload</a></p>` +
+			`<ul><li>One</li><li><ol><li>Nested</li></ol></li><li>Two</li></ul>` +
+			`<ul><li><input type="checkbox" disabled checked>Done</li><li><input type="checkbox" disabled>Todo</li></ul>` +
+			`<blockquote>A quote</blockquote>` +
+			`<img src="/media/cat.jpg" alt="A cat">` +
+			`<div data-relation-to="posts" data-id="2"></div>` +
+			`<p>Internal</p>`
+
+		assert.Equal(t, want, NewHTMLRenderer().Render(lexicalFixture(t)))
+	})
+
+	t.Run("Custom Renderer", func(t *testing.T) {
+		t.Parallel()
+
+		r := NewHTMLRenderer().
+			Register(NodeLink, func(n *Node, children string) string {
+				if n.LinkType != "internal" {
+					return `<a href="` + n.URL + `">` + children + `</a>`
+				}
+				doc, _ := n.Doc()
+				return `<a href="/` + n.RelationTo + `/` + doc.Slug + `">` + children + `</a>`
+			}).
+			Register(NodeRelationship, func(_ *Node, _ string) string {
+				return ""
+			})
+
+		got := r.Render(lexicalFixture(t))
+		assert.Contains(t, got, `<a href="/posts/another-post">Internal</a>`)
+		assert.Contains(t, got, `<a href="https://payloadcms.com">Payload</a>`)
+		assert.NotContains(t, got, "data-relation-to")
+	})
+
+	t.Run("Escapes Text", func(t *testing.T) {
+		t.Parallel()
+
+		n := &Node{Type: NodeText, Text: "<script>\nalert(1)</script>", Format: FormatUnderline | FormatStrikethrough}
+		assert.Equal(t, "<u><s>&lt;script&gt;<br>alert(1)&lt;/script&gt;</s></u>", NewHTMLRenderer().Render(n))
+	})
+
+	t.Run("Nodes", func(t *testing.T) {
+		t.Parallel()
+
+		tt := map[string]struct {
+			input *Node
+			want  string
+		}{
+			"Nil":                 {input: nil, want: ""},
+			"Heading Without Tag": {input: &Node{Type: NodeHeading}, want: "<h2></h2>"},
+			"Heading Invalid Tag": {
+				input: &Node{Type: NodeHeading, Tag: "h1 onmouseover=alert(1)", Children: []*Node{{Type: NodeText, Text: "x"}}},
+				want:  "<h2>x</h2>",
+			},
+			"Heading H6":       {input: &Node{Type: NodeHeading, Tag: "h6"}, want: "<h6></h6>"},
+			"Ordered List":     {input: &Node{Type: NodeList, ListType: ListNumber}, want: "<ol></ol>"},
+			"Horizontal Rule":  {input: &Node{Type: NodeHorizontalRule}, want: "<hr>"},
+			"Link Without URL": {input: &Node{Type: NodeLink, Children: []*Node{{Type: NodeText, Text: "a"}}}, want: "a"},
+			"Link Relative":    {input: &Node{Type: NodeLink, URL: "/posts/1"}, want: `<a href="/posts/1"></a>`},
+			"Link Fragment":    {input: &Node{Type: NodeLink, URL: "#top"}, want: `<a href="#top"></a>`},
+			"Link Mailto":      {input: &Node{Type: NodeLink, URL: "mailto:a@b.com"}, want: `<a href="mailto:a@b.com"></a>`},
+			"Link Tel":         {input: &Node{Type: NodeLink, URL: "tel:+441234"}, want: `<a href="tel:+441234"></a>`},
+			"Link Javascript": {
+				input: &Node{Type: NodeLink, URL: "javascript:alert(1)", Children: []*Node{{Type: NodeText, Text: "a"}}},
+				want:  "a",
+			},
+			"Link Javascript Upper": {
+				input: &Node{Type: NodeLink, URL: "JavaScript:alert(1)", Children: []*Node{{Type: NodeText, Text: "a"}}},
+				want:  "a",
+			},
+			"Link Data": {
+				input: &Node{Type: NodeLink, URL: "data:text/html,<script>alert(1)</script>", Children: []*Node{{Type: NodeText, Text: "a"}}},
+				want:  "a",
+			},
+			"Link Leading Space": {
+				input: &Node{Type: NodeLink, URL: " javascript:alert(1)", Children: []*Node{{Type: NodeText, Text: "a"}}},
+				want:  "a",
+			},
+			"Sub And Sup": {
+				input: &Node{Type: NodeText, Text: "a", Format: FormatSubscript | FormatSuperscript},
+				want:  "<sup><sub>a</sub></sup>",
+			},
+			"Upload File": {
+				input: &Node{Type: NodeUpload, Value: json.RawMessage(`{"url": "/media/a.pdf", "filename": "a.pdf", "mimeType": "application/pdf"}`)},
+				want:  `<a href="/media/a.pdf">a.pdf</a>`,
+			},
+			"Upload Not Populated": {
+				input: &Node{Type: NodeUpload, Value: json.RawMessage(`1`)},
+				want:  "",
+			},
+			"Relationship ID": {
+				input: &Node{Type: NodeRelationship, RelationTo: "posts", Value: json.RawMessage(`"abc"`)},
+				want:  `<div data-relation-to="posts" data-id="abc"></div>`,
+			},
+			"Unknown": {
+				input: &Node{Type: "block", Children: []*Node{{Type: NodeText, Text: "a"}}},
+				want:  "a",
+			},
+		}
+
+		for name, test := range tt {
+			t.Run(name, func(t *testing.T) {
+				t.Parallel()
+				assert.Equal(t, test.want, NewHTMLRenderer().Render(test.input))
+			})
+		}
+	})
+}
@@ -0,0 +1,202 @@
+package richtext
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// Lexical represents a rich text field stored by the Lexical editor. It can
+// be used as a struct field to decode the JSON into a node tree, and
+// encodes back into the JSON it was decoded from.
+//
+// Example:
+//
+//	type Post struct {
+//		Content richtext.Lexical `json:"content"`
+//	}
+//
+//	html := richtext.NewHTMLRenderer().Render(post.Content.Root)
+//
+// See: https://payloadcms.com/docs/rich-text/lexical
+type Lexical struct {
+	// Root is the root node of the tree, which is nil when the field is empty.
+	Root *Node
+	raw  json.RawMessage
+}
+
+// lexicalNode is the JSON shape of a single Lexical node.
+type lexicalNode struct {
+	Type       string            `json:"type"`
+	Children   []json.RawMessage `json:"children"`
+	Text       string            `json:"text"`
+	Format     json.RawMessage   `json:"format"`
+	Tag        string            `json:"tag"`
+	ListType   string            `json:"listType"`
+	Checked    bool              `json:"checked"`
+	Indent     int               `json:"indent"`
+	URL        string            `json:"url"`
+	NewTab     bool              `json:"newTab"`
+	RelationTo string            `json:"relationTo"`
+	Value      json.RawMessage   `json:"value"`
+	Fields     json.RawMessage   `json:"fields"`
+}
+
+// lexicalLinkFields are the fields Payload stores on link nodes.
+type lexicalLinkFields struct {
+	URL      string `json:"url"`
+	NewTab   bool   `json:"newTab"`
+	LinkType string `json:"linkType"`
+	Doc      *struct {
+		RelationTo string          `json:"relationTo"`
+		Value      json.RawMessage `json:"value"`
+	} `json:"doc"`
+}
+
+// ParseLexical decodes Lexical JSON into a node tree. Both the editor
+// state, i.e. {"root": {...}}, and a bare root node are accepted.
+func ParseLexical(data []byte) (*Node, error) {
+	var state struct {
+		Root json.RawMessage `json:"root"`
+	}
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("decoding lexical state: %w", err)
+	}
+
+	root := state.Root
+	if len(root) == 0 {
+		root = data
+	}
+
+	n, err := parseLexicalNode(root)
+	if err != nil {
+		return nil, err
+	}
+	if n.Type != NodeRoot {
+		return nil, fmt.Errorf("expected lexical root node, got: %q", n.Type)
+	}
+
+	return n, nil
+}
+
+func parseLexicalNode(data json.RawMessage) (*Node, error) {
+	var ln lexicalNode
+	if err := json.Unmarshal(data, &ln); err != nil {
+		return nil, fmt.Errorf("decoding lexical node: %w", err)
+	}
+	if ln.Type == "" {
+		return nil, errors.New("lexical node has no type")
+	}
+
+	n := &Node{
+		Type:       lexicalNodeType(ln.Type),
+		Text:       ln.Text,
+		Tag:        ln.Tag,
+		ListType:   ListType(ln.ListType),
+		Checked:    ln.Checked,
+		Indent:     ln.Indent,
+		URL:        ln.URL,
+		NewTab:     ln.NewTab,
+		RelationTo: ln.RelationTo,
+		Fields:     ln.Fields,
+		Raw:        data,
+	}
+
+	// Format is a bitmask on text nodes and the alignment on elements.
+	if format := bytes.TrimSpace(ln.Format); len(format) > 0 {
+		if format[0] == '"' {
+			if err := json.Unmarshal(format, &n.Align); err != nil {
+				return nil, err
+			}
+		} else if f, err := strconv.Atoi(string(format)); err == nil {
+			n.Format = Format(f)
+		}
+	}
+
+	switch n.Type {
+	case NodeUpload, NodeRelationship:
+		n.Value = ln.Value
+	case NodeLink:
+		if err := parseLexicalLink(n, ln.Fields); err != nil {
+			return nil, err
+		}
+	case NodeList:
+		if n.ListType == "" {
+			n.ListType = ListBullet
+			if n.Tag == "ol" {
+				n.ListType = ListNumber
+			}
+		}
+	}
+
+	for _, child := range ln.Children {
+		c, err := parseLexicalNode(child)
+		if err != nil {
+			return nil, err
+		}
+		if n.Type == NodeList && c.Type == NodeListItem {
+			c.ListType = n.ListType
+		}
+		n.Children = append(n.Children, c)
+	}
+
+	return n, nil
+}
+
+func parseLexicalLink(n *Node, fields json.RawMessage) error {
+	if len(fields) == 0 {
+		return nil
+	}
+
+	var lf lexicalLinkFields
+	if err := json.Unmarshal(fields, &lf); err != nil {
+		return fmt.Errorf("decoding lexical link fields: %w", err)
+	}
+
+	if lf.URL != "" {
+		n.URL = lf.URL
+	}
+	n.NewTab = n.NewTab || lf.NewTab
+	n.LinkType = lf.LinkType
+	if lf.Doc != nil {
+		n.RelationTo = lf.Doc.RelationTo
+		n.Value = lf.Doc.Value
+	}
+
+	return nil
+}
+
+// lexicalNodeType maps Lexical node types onto the node types of the tree.
+func lexicalNodeType(t string) NodeType {
+	if t == "autolink" {
+		return NodeLink
+	}
+	return NodeType(t)
+}
+
+// UnmarshalJSON decodes the Lexical JSON into the node tree.
+func (l *Lexical) UnmarshalJSON(data []byte) error {
+	*l = Lexical{}
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		return nil
+	}
+
+	root, err := ParseLexical(data)
+	if err != nil {
+		return err
+	}
+	l.Root = root
+	l.raw = append(json.RawMessage(nil), data...)
+
+	return nil
+}
+
+// MarshalJSON encodes the Lexical JSON the field was decoded from.
+func (l Lexical) MarshalJSON() ([]byte, error) {
+	if len(l.raw) == 0 {
+		return []byte("null"), nil
+	}
+	return l.raw, nil
+}
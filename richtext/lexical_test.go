@@ -0,0 +1,180 @@
+package richtext
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func lexicalFixture(t *testing.T) *Node {
+	t.Helper()
+
+	buf, err := os.ReadFile("testdata/lexical.json")
+	require.NoError(t, err)
+
+	root, err := ParseLexical(buf)
+	require.NoError(t, err)
+
+	return root
+}
+
+func TestParseLexical(t *testing.T) {
+	t.Parallel()
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+
+		root := lexicalFixture(t)
+		assert.Equal(t, NodeRoot, root.Type)
+		require.Len(t, root.Children, 8)
+
+		heading := root.Children[0]
+		assert.Equal(t, NodeHeading, heading.Type)
+		assert.Equal(t, "h1", heading.Tag)
+
+		paragraph := root.Children[1]
+		assert.Equal(t, "center", paragraph.Align)
+		assert.True(t, paragraph.Children[1].Format.Has(FormatBold))
+		assert.False(t, paragraph.Children[1].Format.Has(FormatItalic))
+
+		link := paragraph.Children[7]
+		assert.Equal(t, NodeLink, link.Type)
+		assert.Equal(t, "https://payloadcms.com", link.URL)
+		assert.True(t, link.NewTab)
+		assert.Equal(t, "custom", link.LinkType)
+
+		list := root.Children[2]
+		assert.Equal(t, ListBullet, list.ListType)
+		assert.Equal(t, ListBullet, list.Children[0].ListType)
+		assert.Equal(t, ListNumber, list.Children[1].Children[0].ListType)
+
+		check := root.Children[3]
+		assert.True(t, check.Children[0].Checked)
+		assert.False(t, check.Children[1].Checked)
+
+		upload := root.Children[5]
+		assert.Equal(t, "media", upload.RelationTo)
+		doc, ok := upload.Doc()
+		assert.True(t, ok)
+		assert.Equal(t, "/media/cat.jpg", doc.URL)
+
+		internal := root.Children[7].Children[0]
+		assert.Equal(t, "internal", internal.LinkType)
+		assert.Equal(t, "posts", internal.RelationTo)
+		assert.True(t, internal.IsPopulated())
+	})
+
+	t.Run("Bare Root", func(t *testing.T) {
+		t.Parallel()
+
+		root, err := ParseLexical([]byte(`{"type": "root", "children": []}`))
+		require.NoError(t, err)
+		assert.Equal(t, NodeRoot, root.Type)
+	})
+
+	t.Run("Autolink", func(t *testing.T) {
+		t.Parallel()
+
+		root, err := ParseLexical([]byte(`{"root": {"type": "root", "children": [
+			{"type": "autolink", "fields": {"url": "https://example.com"}, "children": []}
+		]}}`))
+		require.NoError(t, err)
+		assert.Equal(t, NodeLink, root.Children[0].Type)
+		assert.Equal(t, "https://example.com", root.Children[0].URL)
+	})
+
+	t.Run("Unknown Nodes Are Kept", func(t *testing.T) {
+		t.Parallel()
+
+		root, err := ParseLexical([]byte(`{"root": {"type": "root", "children": [
+			{"type": "block", "fields": {"blockType": "cta"}}
+		]}}`))
+		require.NoError(t, err)
+		assert.Equal(t, NodeType("block"), root.Children[0].Type)
+		assert.JSONEq(t, `{"blockType": "cta"}`, string(root.Children[0].Fields))
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		t.Parallel()
+
+		for name, input := range map[string]string{
+			"Invalid JSON": `{`,
+			"No Type":      `{"root": {"children": []}}`,
+			"Not Root":     `{"root": {"type": "paragraph"}}`,
+			"Bad Child":    `{"root": {"type": "root", "children": [{"type": 1}]}}`,
+			"Bad Link":     `{"root": {"type": "root", "children": [{"type": "link", "fields": []}]}}`,
+			"Bad Format":   `{"root": {"type": "root", "format": "\u", "children": []}}`,
+		} {
+			_, err := ParseLexical([]byte(input))
+			assert.Error(t, err, name)
+		}
+	})
+}
+
+func TestLexical_JSON(t *testing.T) {
+	t.Parallel()
+
+	type post struct {
+		Content Lexical `json:"content"`
+	}
+
+	t.Run("Round Trip", func(t *testing.T) {
+		t.Parallel()
+
+		buf, err := os.ReadFile("testdata/lexical.json")
+		require.NoError(t, err)
+
+		var p post
+		require.NoError(t, json.Unmarshal([]byte(`{"content":`+string(buf)+`}`), &p))
+		require.NotNil(t, p.Content.Root)
+
+		got, err := json.Marshal(p)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"content":`+string(buf)+`}`, string(got))
+	})
+
+	t.Run("Null", func(t *testing.T) {
+		t.Parallel()
+
+		var p post
+		require.NoError(t, json.Unmarshal([]byte(`{"content": null}`), &p))
+		assert.Nil(t, p.Content.Root)
+
+		got, err := json.Marshal(p)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"content": null}`, string(got))
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		t.Parallel()
+
+		var p post
+		assert.Error(t, json.Unmarshal([]byte(`{"content": {"root": {}}}`), &p))
+	})
+}
+
+func TestNode_Walk(t *testing.T) {
+	t.Parallel()
+
+	var texts int
+	lexicalFixture(t).Walk(func(n *Node) bool {
+		if n.Type == NodeText {
+			texts++
+		}
+		return n.Type != NodeList
+	})
+	assert.Equal(t, 10, texts)
+}
+
+func TestNode_Decode(t *testing.T) {
+	t.Parallel()
+
+	n := &Node{Value: json.RawMessage(`1`)}
+	assert.False(t, n.IsPopulated())
+	assert.Error(t, n.Decode(&Document{}))
+	_, ok := n.Doc()
+	assert.False(t, ok)
+}
@@ -0,0 +1,193 @@
+package richtext
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// MarkdownRenderer renders a node tree to Markdown.
+type MarkdownRenderer struct {
+	renderers nodeRenderers
+}
+
+var _ Renderer = (*MarkdownRenderer)(nil)
+
+// NewMarkdownRenderer creates a new Markdown renderer with the default
+// node renderers.
+func NewMarkdownRenderer() *MarkdownRenderer {
+	return &MarkdownRenderer{renderers: make(nodeRenderers)}
+}
+
+// Register overrides the rendering of a node type. It returns the
+// renderer so calls can be chained.
+func (r *MarkdownRenderer) Register(t NodeType, fn NodeRenderer) *MarkdownRenderer {
+	r.renderers[t] = fn
+	return r
+}
+
+// Render renders the node and its children to Markdown.
+func (r *MarkdownRenderer) Render(n *Node) string {
+	return collapseBlankLines(r.renderers.renderParts(n, r.parts))
+}
+
+// parts renders lists from the output of each item, and every other
+// node from the output of its children joined together. Lists start on
+// a new line so that lists nested within a list item are placed below
+// the item's text.
+func (r *MarkdownRenderer) parts(n *Node, children []string) string {
+	if n.Type == NodeList {
+		return "\n" + r.list(n, children) + "\n\n"
+	}
+	return r.node(n, strings.Join(children, ""))
+}
+
+// Block level nodes end with a blank line, which is trimmed from the
+// final output by Render. Lists are rendered by parts.
+func (r *MarkdownRenderer) node(n *Node, children string) string {
+	switch n.Type {
+	case NodeRoot:
+		return children
+	case NodeParagraph:
+		return strings.TrimSpace(children) + "\n\n"
+	case NodeHeading:
+		level := int(headingTag(n.Tag)[1] - '0')
+		return strings.Repeat("#", level) + " " + strings.TrimSpace(children) + "\n\n"
+	case NodeQuote:
+		return prefixLines(strings.TrimSpace(children), "> ", "> ") + "\n\n"
+	case NodeListItem:
+		return strings.TrimSpace(children)
+	case NodeLink:
+		if !safeURL(n.URL) {
+			return children
+		}
+		return "[" + children + "](" + n.URL + ")"
+	case NodeUpload:
+		doc, ok := n.Doc()
+		if !ok || doc.URL == "" {
+			return ""
+		}
+		if strings.HasPrefix(doc.MimeType, "image/") {
+			return "![" + escapeMarkdown(doc.Alt) + "](" + doc.URL + ")\n\n"
+		}
+		name := doc.Filename
+		if name == "" {
+			name = doc.URL
+		}
+		return "[" + escapeMarkdown(name) + "](" + doc.URL + ")\n\n"
+	case NodeRelationship:
+		return ""
+	case NodeText:
+		return markdownText(n)
+	case NodeLineBreak:
+		return "  \n"
+	case NodeHorizontalRule:
+		return "---\n\n"
+	default:
+		return children
+	}
+}
+
+// list renders each item of the list with its marker, indenting nested
+// lists and any continuation lines so they belong to the item. The items
+// are the rendered output of the list's children.
+func (r *MarkdownRenderer) list(n *Node, items []string) string {
+	var (
+		lines []string
+		num   = 1
+	)
+	for i, item := range n.Children {
+		content := strings.TrimRight(items[i], "\n")
+
+		// Lexical nests lists within a list item of their own.
+		if isNestedList(item) {
+			lines = append(lines, prefixLines(content, "  ", "  "))
+			continue
+		}
+
+		var marker string
+		switch n.ListType {
+		case ListNumber:
+			marker = strconv.Itoa(num) + ". "
+			num++
+		case ListCheck:
+			marker = "- [ ] "
+			if item.Checked {
+				marker = "- [x] "
+			}
+		default:
+			marker = "- "
+		}
+
+		lines = append(lines, prefixLines(content, marker, strings.Repeat(" ", len(marker))))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func isNestedList(item *Node) bool {
+	return item.Type == NodeListItem && len(item.Children) == 1 && item.Children[0].Type == NodeList
+}
+
+func markdownText(n *Node) string {
+	text := n.Text
+	if n.Format.Has(FormatCode) {
+		return "`" + text + "`"
+	}
+
+	// Markdown can't wrap surrounding whitespace within markers.
+	trimmed := strings.TrimSpace(text)
+	if trimmed == "" {
+		return text
+	}
+	lead := text[:strings.Index(text, trimmed)]
+	trail := text[len(lead)+len(trimmed):]
+
+	text = escapeMarkdown(trimmed)
+	if n.Format.Has(FormatStrikethrough) {
+		text = "~~" + text + "~~"
+	}
+	if n.Format.Has(FormatItalic) {
+		text = "_" + text + "_"
+	}
+	if n.Format.Has(FormatBold) {
+		text = "**" + text + "**"
+	}
+	return lead + text + trail
+}
+
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	"`", "\\`",
+	"*", `\*`,
+	"_", `\_`,
+	"[", `\[`,
+	"]", `\]`,
+)
+
+func escapeMarkdown(s string) string {
+	return markdownEscaper.Replace(s)
+}
+
+var blankLines = regexp.MustCompile(`\n{3,}`)
+
+// collapseBlankLines trims the output and collapses consecutive blank
+// lines left between block level nodes into one.
+func collapseBlankLines(s string) string {
+	return blankLines.ReplaceAllString(strings.TrimSpace(s), "\n\n")
+}
+
+// prefixLines prefixes the first line of s with first, and every
+// other line with rest.
+func prefixLines(s, first, rest string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if i == 0 {
+			lines[i] = first + line
+		} else if line != "" {
+			lines[i] = rest + line
+		} else {
+			lines[i] = strings.TrimRight(rest, " ")
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,150 @@
+package richtext
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarkdownRenderer_Render(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Fixture", func(t *testing.T) {
+		t.Parallel()
+
+		want := "# Hello World\n\n" +
+			"This is **bold** and _italic_, `code`  \n[Payload](https://payloadcms.com)\n\n" +
+			"- One\n  1. Nested\n- Two\n\n" +
+			"- [x] Done\n- [ ] Todo\n\n" +
+			"> A quote\n\n" +
+			"![A cat](/media/cat.jpg)\n\n" +
+			"Internal"
+
+		assert.Equal(t, want, NewMarkdownRenderer().Render(lexicalFixture(t)))
+	})
+
+	t.Run("Custom Renderer", func(t *testing.T) {
+		t.Parallel()
+
+		r := NewMarkdownRenderer().Register(NodeRelationship, func(n *Node, _ string) string {
+			doc, _ := n.Doc()
+			return "See: " + doc.Title + "\n\n"
+		})
+		assert.Contains(t, r.Render(lexicalFixture(t)), "See: Another Post")
+	})
+
+	t.Run("Renders Nested Lists Once", func(t *testing.T) {
+		t.Parallel()
+
+		// Ten levels of lists, each item holding a text node and the
+		// next list.
+		var list *Node
+		for range 10 {
+			item := &Node{Type: NodeListItem, Children: []*Node{{Type: NodeText, Text: "a"}}}
+			if list != nil {
+				item.Children = append(item.Children, list)
+			}
+			list = &Node{Type: NodeList, Children: []*Node{item}}
+		}
+
+		calls := 0
+		r := NewMarkdownRenderer().Register(NodeText, func(n *Node, _ string) string {
+			calls++
+			return n.Text
+		})
+		got := r.Render(list)
+
+		assert.Equal(t, 10, calls)
+		assert.True(t, strings.HasPrefix(got, "- a\n  - a\n    - a"))
+	})
+
+	t.Run("Nodes", func(t *testing.T) {
+		t.Parallel()
+
+		text := func(s string, f Format) *Node {
+			return &Node{Type: NodeText, Text: s, Format: f}
+		}
+
+		tt := map[string]struct {
+			input *Node
+			want  string
+		}{
+			"Heading Without Tag": {
+				input: &Node{Type: NodeHeading, Children: []*Node{text("a", 0)}},
+				want:  "## a",
+			},
+			"Whitespace Outside Markers": {
+				input: &Node{Type: NodeParagraph, Children: []*Node{text("a", 0), text(" bold ", FormatBold), text("c", 0)}},
+				want:  "a **bold** c",
+			},
+			"Whitespace Only": {
+				input: text("  ", FormatBold),
+				want:  "",
+			},
+			"Strikethrough": {
+				input: text("a", FormatStrikethrough|FormatItalic),
+				want:  "_~~a~~_",
+			},
+			"Escapes": {
+				input: text("*a_b*", 0),
+				want:  `\*a\_b\*`,
+			},
+			"Horizontal Rule": {
+				input: &Node{Type: NodeHorizontalRule},
+				want:  "---",
+			},
+			"Multi Line Quote": {
+				input: &Node{Type: NodeQuote, Children: []*Node{text("a", 0), {Type: NodeLineBreak}, text("b", 0)}},
+				want:  "> a  \n> b",
+			},
+			"Link Without URL": {
+				input: &Node{Type: NodeLink, Children: []*Node{text("a", 0)}},
+				want:  "a",
+			},
+			"Heading H4": {
+				input: &Node{Type: NodeHeading, Tag: "h4", Children: []*Node{text("a", 0)}},
+				want:  "#### a",
+			},
+			"Heading Out Of Range": {
+				input: &Node{Type: NodeHeading, Tag: "h9", Children: []*Node{text("a", 0)}},
+				want:  "## a",
+			},
+			"Link Relative": {
+				input: &Node{Type: NodeLink, URL: "/posts/1", Children: []*Node{text("a", 0)}},
+				want:  "[a](/posts/1)",
+			},
+			"Link Javascript": {
+				input: &Node{Type: NodeLink, URL: "javascript:alert(1)", Children: []*Node{text("a", 0)}},
+				want:  "a",
+			},
+			"Link Data": {
+				input: &Node{Type: NodeLink, URL: "data:text/html,<script>alert(1)</script>", Children: []*Node{text("a", 0)}},
+				want:  "a",
+			},
+			"Numbered List": {
+				input: &Node{Type: NodeList, ListType: ListNumber, Children: []*Node{
+					{Type: NodeListItem, Children: []*Node{text("a", 0)}},
+					{Type: NodeListItem, Children: []*Node{text("b", 0), {Type: NodeLineBreak}, text("c", 0)}},
+				}},
+				want: "1. a\n2. b  \n   c",
+			},
+			"Upload File": {
+				input: &Node{Type: NodeUpload, Value: json.RawMessage(`{"url": "/media/a.pdf", "mimeType": "application/pdf"}`)},
+				want:  "[/media/a.pdf](/media/a.pdf)",
+			},
+			"Upload Not Populated": {
+				input: &Node{Type: NodeUpload, Value: json.RawMessage(`1`)},
+				want:  "",
+			},
+		}
+
+		for name, test := range tt {
+			t.Run(name, func(t *testing.T) {
+				t.Parallel()
+				assert.Equal(t, test.want, NewMarkdownRenderer().Render(test.input))
+			})
+		}
+	})
+}
@@ -0,0 +1,165 @@
+// Package richtext decodes Payload rich text fields into a typed node tree
+// that can be rendered to HTML, Markdown and plain text.
+//
+// Lexical JSON is decoded with ParseLexical and Slate JSON with ParseSlate,
+// or by using the Lexical, Slate or RichText types as a struct field. Both
+// editors decode into the same tree, so the renderers work with either.
+// Renderers can be customised per node type by registering a NodeRenderer.
+//
+// See: https://payloadcms.com/docs/rich-text/overview
+package richtext
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+// NodeType defines the type of node within the tree.
+type NodeType string
+
+// Node types that are decoded and rendered by default. Nodes with any
+// other type are kept in the tree with their raw JSON, and only their
+// children are rendered unless a NodeRenderer has been registered.
+const (
+	NodeRoot           NodeType = "root"
+	NodeParagraph      NodeType = "paragraph"
+	NodeHeading        NodeType = "heading"
+	NodeQuote          NodeType = "quote"
+	NodeList           NodeType = "list"
+	NodeListItem       NodeType = "listitem"
+	NodeLink           NodeType = "link"
+	NodeUpload         NodeType = "upload"
+	NodeRelationship   NodeType = "relationship"
+	NodeText           NodeType = "text"
+	NodeLineBreak      NodeType = "linebreak"
+	NodeHorizontalRule NodeType = "horizontalrule"
+)
+
+// ListType defines the type of list.
+type ListType string
+
+// List types.
+const (
+	ListBullet ListType = "bullet"
+	ListNumber ListType = "number"
+	ListCheck  ListType = "check"
+)
+
+// Format is a bitmask of the formats applied to a text node. The values
+// match the ones used by Lexical.
+type Format int
+
+// Text formats.
+const (
+	FormatBold Format = 1 << iota
+	FormatItalic
+	FormatStrikethrough
+	FormatUnderline
+	FormatCode
+	FormatSubscript
+	FormatSuperscript
+)
+
+// Has reports if the format contains the flag.
+func (f Format) Has(flag Format) bool {
+	return f&flag != 0
+}
+
+// Node is a single node within a rich text tree. Only the fields that
+// are relevant to the node's Type are set.
+type Node struct {
+	Type     NodeType
+	Children []*Node
+
+	// Text and Format are set on text nodes.
+	Text   string
+	Format Format
+
+	// Tag is the HTML tag of headings (h1-h6) and lists (ul, ol).
+	Tag string
+	// ListType is set on lists and the list items within them.
+	ListType ListType
+	// Checked is set on list items within a check list.
+	Checked bool
+	// Align is the alignment of element nodes, e.g. center or right.
+	Align string
+	// Indent is the indentation level of element nodes.
+	Indent int
+
+	// URL and NewTab are set on links with a custom URL.
+	URL    string
+	NewTab bool
+	// LinkType is either custom or internal for links.
+	LinkType string
+
+	// RelationTo and Value are set on upload and relationship nodes, and
+	// on internal links. Value is either the ID of the document, or the
+	// populated document, see Doc.
+	RelationTo string
+	Value      json.RawMessage
+	// Fields holds any additional fields set on the node, such as the
+	// fields of an upload or a link.
+	Fields json.RawMessage
+
+	// Raw is the JSON the node was decoded from.
+	Raw json.RawMessage
+}
+
+// Document holds the commonly used fields of a populated upload or
+// relationship document.
+type Document struct {
+	ID       any    `json:"id"`
+	URL      string `json:"url"`
+	Alt      string `json:"alt"`
+	Filename string `json:"filename"`
+	MimeType string `json:"mimeType"`
+	Title    string `json:"title"`
+	Slug     string `json:"slug"`
+}
+
+// errNotPopulated is returned when decoding a value that is only an ID.
+var errNotPopulated = errors.New("value is not populated")
+
+// IsPopulated reports if Value holds the full document, rather than an ID.
+func (n *Node) IsPopulated() bool {
+	for _, b := range n.Value {
+		switch b {
+		case ' ', '\t', '\n', '\r':
+			continue
+		case '{':
+			return true
+		default:
+			return false
+		}
+	}
+	return false
+}
+
+// Decode unmarshals the populated document in Value into out.
+func (n *Node) Decode(out any) error {
+	if !n.IsPopulated() {
+		return errNotPopulated
+	}
+	return json.Unmarshal(n.Value, out)
+}
+
+// Doc returns the commonly used fields of the populated document in
+// Value. The boolean is false if the value has not been populated.
+func (n *Node) Doc() (Document, bool) {
+	var d Document
+	if err := n.Decode(&d); err != nil {
+		return Document{}, false
+	}
+	return d, true
+}
+
+// Walk calls fn for the node and each of its descendants, depth first.
+// If fn returns false, the children of that node are skipped.
+func (n *Node) Walk(fn func(n *Node) bool) {
+	if n == nil || !fn(n) {
+		return
+	}
+	for _, c := range n.Children {
+		c.Walk(fn)
+	}
+}
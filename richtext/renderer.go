@@ -0,0 +1,45 @@
+package richtext
+
+import (
+	"strings"
+)
+
+// Renderer renders a node tree to a string.
+type Renderer interface {
+	Render(n *Node) string
+}
+
+// NodeRenderer renders a single node. The children argument contains
+// the output of the node's children, which have already been rendered.
+type NodeRenderer func(n *Node, children string) string
+
+// nodeRenderers holds the custom renderers registered per node type.
+type nodeRenderers map[NodeType]NodeRenderer
+
+// render renders the node and its children, using a registered
+// NodeRenderer for the type of node if one exists, otherwise def.
+func (r nodeRenderers) render(n *Node, def NodeRenderer) string {
+	return r.renderParts(n, func(n *Node, children []string) string {
+		return def(n, strings.Join(children, ""))
+	})
+}
+
+// renderParts is render for default renderers that need the output of
+// each child separately, such as the items of a list. Every node is
+// rendered once, with its children rendered before it.
+func (r nodeRenderers) renderParts(n *Node, def func(n *Node, children []string) string) string {
+	if n == nil {
+		return ""
+	}
+
+	children := make([]string, len(n.Children))
+	for i, c := range n.Children {
+		children[i] = r.renderParts(c, def)
+	}
+
+	if fn, ok := r[n.Type]; ok {
+		return fn(n, strings.Join(children, ""))
+	}
+
+	return def(n, children)
+}
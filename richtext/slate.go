@@ -0,0 +1,237 @@
+package richtext
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// Slate represents a rich text field stored by the Slate editor. It can be
+// used as a struct field to decode the JSON into the same node tree as
+// Lexical, and encodes back into the JSON it was decoded from.
+//
+// Example:
+//
+//	type Media struct {
+//		Caption richtext.Slate `json:"caption"`
+//	}
+//
+//	html := richtext.NewHTMLRenderer().Render(media.Caption.Root)
+//
+// See: https://payloadcms.com/docs/rich-text/slate
+type Slate struct {
+	// Root is the root node of the tree, which is nil when the field is empty.
+	Root *Node
+	raw  json.RawMessage
+}
+
+// slateNode is the JSON shape of a single Slate element or text leaf.
+type slateNode struct {
+	Type          string            `json:"type"`
+	Children      []json.RawMessage `json:"children"`
+	Text          *string           `json:"text"`
+	Bold          bool              `json:"bold"`
+	Italic        bool              `json:"italic"`
+	Underline     bool              `json:"underline"`
+	Strikethrough bool              `json:"strikethrough"`
+	Code          bool              `json:"code"`
+	Subscript     bool              `json:"subscript"`
+	Superscript   bool              `json:"superscript"`
+	URL           string            `json:"url"`
+	NewTab        bool              `json:"newTab"`
+	LinkType      string            `json:"linkType"`
+	Doc           *struct {
+		RelationTo string          `json:"relationTo"`
+		Value      json.RawMessage `json:"value"`
+	} `json:"doc"`
+	RelationTo string          `json:"relationTo"`
+	Value      json.RawMessage `json:"value"`
+	Fields     json.RawMessage `json:"fields"`
+}
+
+// ParseSlate decodes a Slate node array into a node tree, with the nodes
+// wrapped in a root node so it can be rendered in the same way as Lexical.
+func ParseSlate(data []byte) (*Node, error) {
+	var nodes []json.RawMessage
+	if err := json.Unmarshal(data, &nodes); err != nil {
+		return nil, fmt.Errorf("decoding slate nodes: %w", err)
+	}
+
+	root := &Node{Type: NodeRoot, Raw: data}
+	for _, raw := range nodes {
+		n, err := parseSlateNode(raw)
+		if err != nil {
+			return nil, err
+		}
+		root.Children = append(root.Children, n)
+	}
+
+	return root, nil
+}
+
+func parseSlateNode(data json.RawMessage) (*Node, error) {
+	var sn slateNode
+	if err := json.Unmarshal(data, &sn); err != nil {
+		return nil, fmt.Errorf("decoding slate node: %w", err)
+	}
+
+	// Text leaves have no children and carry their marks as booleans.
+	if sn.Text != nil && sn.Children == nil {
+		return &Node{
+			Type:   NodeText,
+			Text:   *sn.Text,
+			Format: slateFormat(sn),
+			Raw:    data,
+		}, nil
+	}
+
+	n := &Node{
+		Type:       NodeType(sn.Type),
+		RelationTo: sn.RelationTo,
+		Fields:     sn.Fields,
+		Raw:        data,
+	}
+
+	switch sn.Type {
+	case "", "p", "paragraph":
+		n.Type = NodeParagraph
+	case "h1", "h2", "h3", "h4", "h5", "h6":
+		n.Type = NodeHeading
+		n.Tag = sn.Type
+	case "ul":
+		n.Type = NodeList
+		n.Tag = sn.Type
+		n.ListType = ListBullet
+	case "ol":
+		n.Type = NodeList
+		n.Tag = sn.Type
+		n.ListType = ListNumber
+	case "li":
+		n.Type = NodeListItem
+	case "blockquote":
+		n.Type = NodeQuote
+	case "indent":
+		n.Indent = 1
+	case "link":
+		n.Type = NodeLink
+		n.URL = sn.URL
+		n.NewTab = sn.NewTab
+		n.LinkType = sn.LinkType
+		if sn.Doc != nil {
+			n.RelationTo = sn.Doc.RelationTo
+			n.Value = sn.Doc.Value
+		}
+	case "upload":
+		n.Type = NodeUpload
+		n.Value = sn.Value
+	case "relationship":
+		n.Type = NodeRelationship
+		n.Value = sn.Value
+	}
+
+	for _, child := range sn.Children {
+		c, err := parseSlateNode(child)
+		if err != nil {
+			return nil, err
+		}
+		if n.Type == NodeList && c.Type == NodeListItem {
+			c.ListType = n.ListType
+		}
+		n.Children = append(n.Children, c)
+	}
+
+	// Upload and relationship elements carry an empty text child that
+	// Slate requires for void elements, it has no meaning here.
+	if n.Type == NodeUpload || n.Type == NodeRelationship {
+		n.Children = nil
+	}
+
+	return n, nil
+}
+
+func slateFormat(sn slateNode) Format {
+	var f Format
+	for flag, ok := range map[Format]bool{
+		FormatBold:          sn.Bold,
+		FormatItalic:        sn.Italic,
+		FormatUnderline:     sn.Underline,
+		FormatStrikethrough: sn.Strikethrough,
+		FormatCode:          sn.Code,
+		FormatSubscript:     sn.Subscript,
+		FormatSuperscript:   sn.Superscript,
+	} {
+		if ok {
+			f |= flag
+		}
+	}
+	return f
+}
+
+// UnmarshalJSON decodes the Slate JSON into the node tree.
+func (s *Slate) UnmarshalJSON(data []byte) error {
+	*s = Slate{}
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		return nil
+	}
+
+	root, err := ParseSlate(data)
+	if err != nil {
+		return err
+	}
+	s.Root = root
+	s.raw = append(json.RawMessage(nil), data...)
+
+	return nil
+}
+
+// MarshalJSON encodes the Slate JSON the field was decoded from.
+func (s Slate) MarshalJSON() ([]byte, error) {
+	if len(s.raw) == 0 {
+		return []byte("null"), nil
+	}
+	return s.raw, nil
+}
+
+// RichText represents a rich text field stored by either the Lexical or the
+// Slate editor, detected by the shape of the JSON. It's useful when content
+// is being migrated from Slate to Lexical and documents contain both.
+type RichText struct {
+	// Root is the root node of the tree, which is nil when the field is empty.
+	Root *Node
+	raw  json.RawMessage
+}
+
+// UnmarshalJSON decodes a Slate array or Lexical object into the node tree.
+func (r *RichText) UnmarshalJSON(data []byte) error {
+	*r = RichText{}
+
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+
+	var (
+		root *Node
+		err  error
+	)
+	if data[0] == '[' {
+		root, err = ParseSlate(data)
+	} else {
+		root, err = ParseLexical(data)
+	}
+	if err != nil {
+		return err
+	}
+	r.Root = root
+	r.raw = append(json.RawMessage(nil), data...)
+
+	return nil
+}
+
+// MarshalJSON encodes the JSON the field was decoded from.
+func (r RichText) MarshalJSON() ([]byte, error) {
+	if len(r.raw) == 0 {
+		return []byte("null"), nil
+	}
+	return r.raw, nil
+}
@@ -0,0 +1,221 @@
+package richtext
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func slateFixture(t *testing.T) *Node {
+	t.Helper()
+
+	buf, err := os.ReadFile("testdata/slate.json")
+	require.NoError(t, err)
+
+	root, err := ParseSlate(buf)
+	require.NoError(t, err)
+
+	return root
+}
+
+func TestParseSlate(t *testing.T) {
+	t.Parallel()
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+
+		root := slateFixture(t)
+		assert.Equal(t, NodeRoot, root.Type)
+		require.Len(t, root.Children, 7)
+
+		heading := root.Children[0]
+		assert.Equal(t, NodeHeading, heading.Type)
+		assert.Equal(t, "h2", heading.Tag)
+
+		paragraph := root.Children[1]
+		assert.Equal(t, NodeParagraph, paragraph.Type)
+		assert.Equal(t, FormatBold, paragraph.Children[1].Format)
+		assert.Equal(t, FormatStrikethrough|FormatItalic, paragraph.Children[3].Format)
+
+		link := paragraph.Children[5]
+		assert.Equal(t, NodeLink, link.Type)
+		assert.Equal(t, "https://payloadcms.com", link.URL)
+		assert.True(t, link.NewTab)
+
+		list := root.Children[2]
+		assert.Equal(t, ListBullet, list.ListType)
+		assert.Equal(t, ListNumber, list.Children[1].Children[1].ListType)
+
+		upload := root.Children[4]
+		assert.Equal(t, NodeUpload, upload.Type)
+		assert.Empty(t, upload.Children)
+		assert.JSONEq(t, `{"caption": "Good dog"}`, string(upload.Fields))
+
+		assert.Equal(t, NodeRelationship, root.Children[5].Type)
+		assert.Equal(t, 1, root.Children[6].Indent)
+	})
+
+	t.Run("Internal Link", func(t *testing.T) {
+		t.Parallel()
+
+		root, err := ParseSlate([]byte(`[{"children": [
+			{"type": "link", "linkType": "internal", "doc": {"relationTo": "posts", "value": "abc"}, "children": [{"text": "a"}]}
+		]}]`))
+		require.NoError(t, err)
+
+		link := root.Children[0].Children[0]
+		assert.Equal(t, "internal", link.LinkType)
+		assert.Equal(t, "posts", link.RelationTo)
+		assert.JSONEq(t, `"abc"`, string(link.Value))
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		t.Parallel()
+
+		for name, input := range map[string]string{
+			"Not An Array": `{}`,
+			"Bad Node":     `[1]`,
+			"Bad Child":    `[{"type": "p", "children": [1]}]`,
+		} {
+			_, err := ParseSlate([]byte(input))
+			assert.Error(t, err, name)
+		}
+	})
+}
+
+func TestSlate_Render(t *testing.T) {
+	t.Parallel()
+
+	t.Run("HTML", func(t *testing.T) {
+		t.Parallel()
+
+		want := `<h2>Legacy Content</h2>` +
+			`<p>Some <strong>bold</strong> and <em><s>struck</s></em> text with a ` +
+			`<a href="https://payloadcms.com" target="_blank" rel="noopener noreferrer">link</a>.</p>` +
+			`<ul><li>First</li><li>Second<ol><li><code>Nested</code></li></ol></li></ul>` +
+			`<blockquote><u>Quoted</u></blockquote>` +
+			`<img src="/media/dog.png" alt="A dog">` +
+			`<div data-relation-to="posts" data-id="3"></div>` +
+			`<p>Indented</p>`
+
+		assert.Equal(t, want, NewHTMLRenderer().Render(slateFixture(t)))
+	})
+
+	t.Run("HTML Unsafe Link", func(t *testing.T) {
+		t.Parallel()
+
+		root, err := ParseSlate([]byte(`[{"type": "link", "url": "javascript:alert(1)", "children": [{"text": "click"}]}]`))
+		require.NoError(t, err)
+		assert.Equal(t, "click", NewHTMLRenderer().Render(root))
+	})
+
+	t.Run("Markdown", func(t *testing.T) {
+		t.Parallel()
+
+		want := "## Legacy Content\n\n" +
+			"Some **bold** and _~~struck~~_ text with a [link](https://payloadcms.com).\n\n" +
+			"- First\n- Second\n  1. `Nested`\n\n" +
+			"> Quoted\n\n" +
+			"![A dog](/media/dog.png)\n\n" +
+			"Indented"
+
+		assert.Equal(t, want, NewMarkdownRenderer().Render(slateFixture(t)))
+	})
+
+	t.Run("Text", func(t *testing.T) {
+		t.Parallel()
+
+		want := "Legacy Content\n\n" +
+			"Some bold and struck text with a link.\n\n" +
+			"First\nSecond\nNested\n\n" +
+			"Quoted\n\n" +
+			"A dog\n\n" +
+			"Indented"
+
+		assert.Equal(t, want, NewTextRenderer().Render(slateFixture(t)))
+	})
+}
+
+func TestSlate_JSON(t *testing.T) {
+	t.Parallel()
+
+	type media struct {
+		Caption Slate `json:"caption"`
+	}
+
+	t.Run("Round Trip", func(t *testing.T) {
+		t.Parallel()
+
+		buf, err := os.ReadFile("testdata/slate.json")
+		require.NoError(t, err)
+
+		var m media
+		require.NoError(t, json.Unmarshal([]byte(`{"caption":`+string(buf)+`}`), &m))
+		require.NotNil(t, m.Caption.Root)
+
+		got, err := json.Marshal(m)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"caption":`+string(buf)+`}`, string(got))
+	})
+
+	t.Run("Null", func(t *testing.T) {
+		t.Parallel()
+
+		var m media
+		require.NoError(t, json.Unmarshal([]byte(`{"caption": null}`), &m))
+		assert.Nil(t, m.Caption.Root)
+
+		got, err := json.Marshal(m)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"caption": null}`, string(got))
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		t.Parallel()
+
+		var m media
+		assert.Error(t, json.Unmarshal([]byte(`{"caption": [1]}`), &m))
+	})
+}
+
+func TestRichText_JSON(t *testing.T) {
+	t.Parallel()
+
+	tt := map[string]struct {
+		input   string
+		want    NodeType
+		wantErr bool
+	}{
+		"Slate":   {input: `[{"children": [{"text": "a"}]}]`, want: NodeParagraph},
+		"Lexical": {input: `{"root": {"type": "root", "children": [{"type": "heading", "tag": "h1"}]}}`, want: NodeHeading},
+		"Null":    {input: `null`},
+		"Error":   {input: `{"root": {}}`, wantErr: true},
+	}
+
+	for name, test := range tt {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var r RichText
+			err := json.Unmarshal([]byte(test.input), &r)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			got, err := json.Marshal(r)
+			require.NoError(t, err)
+			assert.JSONEq(t, test.input, string(got))
+
+			if test.want == "" {
+				assert.Nil(t, r.Root)
+				return
+			}
+			assert.Equal(t, test.want, r.Root.Children[0].Type)
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package richtext
+
+import (
+	"strings"
+)
+
+// TextRenderer renders a node tree to plain text, discarding any
+// formatting. Block level nodes are separated by a blank line.
+type TextRenderer struct {
+	renderers nodeRenderers
+}
+
+var _ Renderer = (*TextRenderer)(nil)
+
+// NewTextRenderer creates a new plain text renderer with the default
+// node renderers.
+func NewTextRenderer() *TextRenderer {
+	return &TextRenderer{renderers: make(nodeRenderers)}
+}
+
+// Register overrides the rendering of a node type. It returns the
+// renderer so calls can be chained.
+func (r *TextRenderer) Register(t NodeType, fn NodeRenderer) *TextRenderer {
+	r.renderers[t] = fn
+	return r
+}
+
+// Render renders the node and its children to plain text.
+func (r *TextRenderer) Render(n *Node) string {
+	return collapseBlankLines(r.renderers.render(n, r.node))
+}
+
+func (r *TextRenderer) node(n *Node, children string) string {
+	switch n.Type {
+	case NodeParagraph, NodeHeading, NodeQuote:
+		return strings.TrimSpace(children) + "\n\n"
+	case NodeList:
+		return "\n" + strings.TrimSpace(children) + "\n\n"
+	case NodeListItem:
+		return strings.TrimSpace(children) + "\n"
+	case NodeUpload:
+		if doc, ok := n.Doc(); ok && doc.Alt != "" {
+			return doc.Alt + "\n\n"
+		}
+		return ""
+	case NodeRelationship, NodeHorizontalRule:
+		return ""
+	case NodeText:
+		return n.Text
+	case NodeLineBreak:
+		return "\n"
+	default:
+		return children
+	}
+}
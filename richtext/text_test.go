@@ -0,0 +1,35 @@
+package richtext
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTextRenderer_Render(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Fixture", func(t *testing.T) {
+		t.Parallel()
+
+		want := "Hello World\n\n" +
+			"This is bold and italic, code\nPayload\n\n" +
+			"One\nNested\nTwo\n\n" +
+			"Done\nTodo\n\n" +
+			"A quote\n\n" +
+			"A cat\n\n" +
+			"Internal"
+
+		assert.Equal(t, want, NewTextRenderer().Render(lexicalFixture(t)))
+	})
+
+	t.Run("Custom Renderer", func(t *testing.T) {
+		t.Parallel()
+
+		r := NewTextRenderer().Register(NodeText, func(n *Node, _ string) string {
+			return strings.ToUpper(n.Text)
+		})
+		assert.True(t, strings.HasPrefix(r.Render(lexicalFixture(t)), "HELLO WORLD"))
+	})
+}
@@ -0,0 +1,125 @@
+package payloadcms
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// TypedCollection wraps a CollectionService for a single collection,
+// decoding documents into T so the slug and the output type don't need
+// to be passed on every call. It's used by the clients generated by
+// payloadgen, but can also be created by hand.
+//
+// Example:
+//
+//	posts := payloadcms.NewTypedCollection[Post](client.Collections, "posts")
+//	list, _, err := posts.List(ctx, payloadcms.ListParams{Limit: 10})
+type TypedCollection[T any] struct {
+	service    CollectionService
+	collection Collection
+}
+
+// NewTypedCollection creates a TypedCollection for the collection.
+func NewTypedCollection[T any](service CollectionService, collection Collection) TypedCollection[T] {
+	return TypedCollection[T]{service: service, collection: collection}
+}
+
+// Collection returns the slug of the collection.
+func (c TypedCollection[T]) Collection() Collection {
+	return c.collection
+}
+
+// FindByID finds a document by its ID.
+func (c TypedCollection[T]) FindByID(ctx context.Context, id any, opts ...RequestOption) (T, Response, error) {
+	var out T
+	resp, err := c.service.FindByID(ctx, c.collection, id, &out, opts...)
+	return out, resp, err
+}
+
+// FindBySlug finds a document by its slug.
+// See CollectionService.FindBySlug for the endpoint this requires.
+func (c TypedCollection[T]) FindBySlug(ctx context.Context, slug string, opts ...RequestOption) (T, Response, error) {
+	var out T
+	resp, err := c.service.FindBySlug(ctx, c.collection, slug, &out, opts...)
+	return out, resp, err
+}
+
+// List lists the documents of the collection.
+func (c TypedCollection[T]) List(ctx context.Context, params ListParams, opts ...RequestOption) (ListResponse[T], Response, error) {
+	var out ListResponse[T]
+	resp, err := c.service.List(ctx, c.collection, params, &out, opts...)
+	return out, resp, err
+}
+
+// Create creates a new document, where in is typically a T or a map of
+// the fields to set.
+func (c TypedCollection[T]) Create(ctx context.Context, in any, opts ...RequestOption) (CreateResponse[T], Response, error) {
+	var out CreateResponse[T]
+	resp, err := c.service.Create(ctx, c.collection, in, opts...)
+	if err != nil {
+		return out, resp, err
+	}
+	return out, resp, decodeContent(resp, &out)
+}
+
+// UpdateByID updates a document by its ID, where in is typically a T or
+// a map of the fields to update.
+func (c TypedCollection[T]) UpdateByID(ctx context.Context, id any, in any, opts ...RequestOption) (UpdateResponse[T], Response, error) {
+	var out UpdateResponse[T]
+	resp, err := c.service.UpdateByID(ctx, c.collection, id, in, opts...)
+	if err != nil {
+		return out, resp, err
+	}
+	return out, resp, decodeContent(resp, &out)
+}
+
+// DeleteByID deletes a document by its ID.
+func (c TypedCollection[T]) DeleteByID(ctx context.Context, id any, opts ...RequestOption) (Response, error) {
+	return c.service.DeleteByID(ctx, c.collection, id, opts...)
+}
+
+// TypedGlobal wraps a GlobalsService for a single global, decoding it
+// into T.
+type TypedGlobal[T any] struct {
+	service GlobalsService
+	global  Global
+}
+
+// NewTypedGlobal creates a TypedGlobal for the global.
+func NewTypedGlobal[T any](service GlobalsService, global Global) TypedGlobal[T] {
+	return TypedGlobal[T]{service: service, global: global}
+}
+
+// Global returns the slug of the global.
+func (g TypedGlobal[T]) Global() Global {
+	return g.global
+}
+
+// Get retrieves the global.
+func (g TypedGlobal[T]) Get(ctx context.Context, opts ...RequestOption) (T, Response, error) {
+	var out T
+	resp, err := g.service.Get(ctx, g.global, &out, opts...)
+	return out, resp, err
+}
+
+// Update updates the global, returning the updated document. in is
+// typically a T or a map of the fields to update.
+func (g TypedGlobal[T]) Update(ctx context.Context, in any, opts ...RequestOption) (T, Response, error) {
+	var out struct {
+		Result T `json:"result"`
+	}
+	resp, err := g.service.Update(ctx, g.global, in, opts...)
+	if err != nil {
+		return out.Result, resp, err
+	}
+	return out.Result, resp, decodeContent(resp, &out)
+}
+
+// decodeContent decodes the body of a response, for the endpoints that
+// don't decode their response themselves.
+func decodeContent(resp Response, out any) error {
+	if len(resp.Content) == 0 {
+		return nil
+	}
+	return json.Unmarshal(resp.Content, out)
+}
@@ -0,0 +1,190 @@
+package payloadcms
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type typedPost struct {
+	ID    int    `json:"id"`
+	Title string `json:"title"`
+}
+
+func TestTypedCollection(t *testing.T) {
+	t.Parallel()
+
+	tt := map[string]struct {
+		call       func(c TypedCollection[typedPost]) (any, error)
+		body       string
+		wantURL    string
+		wantMethod string
+		want       any
+	}{
+		"FindByID": {
+			call: func(c TypedCollection[typedPost]) (any, error) {
+				doc, _, err := c.FindByID(context.Background(), 1)
+				return doc, err
+			},
+			body:       `{"id": 1, "title": "Hello"}`,
+			wantURL:    "/api/posts/1",
+			wantMethod: http.MethodGet,
+			want:       typedPost{ID: 1, Title: "Hello"},
+		},
+		"FindBySlug": {
+			call: func(c TypedCollection[typedPost]) (any, error) {
+				doc, _, err := c.FindBySlug(context.Background(), "hello")
+				return doc, err
+			},
+			body:       `{"id": 1, "title": "Hello"}`,
+			wantURL:    "/api/posts/slug/hello",
+			wantMethod: http.MethodGet,
+			want:       typedPost{ID: 1, Title: "Hello"},
+		},
+		"List": {
+			call: func(c TypedCollection[typedPost]) (any, error) {
+				list, _, err := c.List(context.Background(), ListParams{Limit: 1})
+				return list.Docs, err
+			},
+			body:       `{"docs": [{"id": 1, "title": "Hello"}], "totalDocs": 1}`,
+			wantURL:    "/api/posts?limit=1",
+			wantMethod: http.MethodGet,
+			want:       []typedPost{{ID: 1, Title: "Hello"}},
+		},
+		"Create": {
+			call: func(c TypedCollection[typedPost]) (any, error) {
+				res, _, err := c.Create(context.Background(), typedPost{Title: "Hello"})
+				return res.Doc, err
+			},
+			body:       `{"doc": {"id": 2, "title": "Hello"}, "message": "created"}`,
+			wantURL:    "/api/posts",
+			wantMethod: http.MethodPost,
+			want:       typedPost{ID: 2, Title: "Hello"},
+		},
+		"UpdateByID": {
+			call: func(c TypedCollection[typedPost]) (any, error) {
+				res, _, err := c.UpdateByID(context.Background(), 2, map[string]any{"title": "Updated"})
+				return res.Doc, err
+			},
+			body:       `{"doc": {"id": 2, "title": "Updated"}, "message": "updated"}`,
+			wantURL:    "/api/posts/2",
+			wantMethod: http.MethodPatch,
+			want:       typedPost{ID: 2, Title: "Updated"},
+		},
+		"DeleteByID": {
+			call: func(c TypedCollection[typedPost]) (any, error) {
+				_, err := c.DeleteByID(context.Background(), 2)
+				return nil, err
+			},
+			body:       `{}`,
+			wantURL:    "/api/posts/2",
+			wantMethod: http.MethodDelete,
+		},
+	}
+
+	for name, test := range tt {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			client, teardown := Setup(t, func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, test.wantURL, r.URL.String())
+				assert.Equal(t, test.wantMethod, r.Method)
+				_, _ = w.Write([]byte(test.body))
+			})
+			defer teardown()
+
+			posts := NewTypedCollection[typedPost](CollectionServiceOp{Client: client}, "posts")
+			assert.Equal(t, Collection("posts"), posts.Collection())
+
+			got, err := test.call(posts)
+			require.NoError(t, err)
+			assert.Equal(t, test.want, got)
+		})
+	}
+
+	t.Run("Error", func(t *testing.T) {
+		t.Parallel()
+
+		client, teardown := Setup(t, func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"errors": [{"message": "invalid"}]}`))
+		})
+		defer teardown()
+
+		posts := NewTypedCollection[typedPost](CollectionServiceOp{Client: client}, "posts")
+		_, _, err := posts.Create(context.Background(), typedPost{})
+		assert.Error(t, err)
+		_, _, err = posts.UpdateByID(context.Background(), 1, typedPost{})
+		assert.Error(t, err)
+	})
+
+	t.Run("Bad Content", func(t *testing.T) {
+		t.Parallel()
+
+		client, teardown := Setup(t, func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(`{"doc": []}`))
+		})
+		defer teardown()
+
+		posts := NewTypedCollection[typedPost](CollectionServiceOp{Client: client}, "posts")
+		_, _, err := posts.Create(context.Background(), typedPost{})
+		assert.Error(t, err)
+	})
+}
+
+func TestTypedGlobal(t *testing.T) {
+	t.Parallel()
+
+	type settings struct {
+		SiteName string `json:"siteName"`
+	}
+
+	t.Run("Get", func(t *testing.T) {
+		t.Parallel()
+
+		client, teardown := Setup(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/api/globals/settings", r.URL.Path)
+			assert.Equal(t, http.MethodGet, r.Method)
+			_, _ = w.Write([]byte(`{"siteName": "Payload"}`))
+		})
+		defer teardown()
+
+		g := NewTypedGlobal[settings](GlobalsServiceOp{Client: client}, "settings")
+		assert.Equal(t, Global("settings"), g.Global())
+
+		got, _, err := g.Get(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, settings{SiteName: "Payload"}, got)
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		t.Parallel()
+
+		client, teardown := Setup(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodPost, r.Method)
+			_, _ = w.Write([]byte(`{"message": "Global saved successfully.", "result": {"siteName": "Go"}}`))
+		})
+		defer teardown()
+
+		g := NewTypedGlobal[settings](GlobalsServiceOp{Client: client}, "settings")
+		got, _, err := g.Update(context.Background(), settings{SiteName: "Go"})
+		require.NoError(t, err)
+		assert.Equal(t, settings{SiteName: "Go"}, got)
+	})
+
+	t.Run("Update Error", func(t *testing.T) {
+		t.Parallel()
+
+		client, teardown := Setup(t, func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		})
+		defer teardown()
+
+		g := NewTypedGlobal[settings](GlobalsServiceOp{Client: client}, "settings")
+		_, _, err := g.Update(context.Background(), settings{})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,168 @@
+package payloadcms
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"strings"
+
+	// Register the decoders used to read the dimensions of images.
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+)
+
+// UploadConstraints are checked against a file before it's uploaded, so
+// that files Payload would reject fail early, rather than after the
+// whole file has been sent. They typically mirror the mimeTypes and
+// limits of the upload config of the collection.
+//
+// See: https://payloadcms.com/docs/upload/overview#mimetypes
+type UploadConstraints struct {
+	// AllowedTypes are the MIME types the file may be, such as
+	// "image/png" or "image/*". Any type is allowed when empty.
+	AllowedTypes []string
+	// MaxBytes is the maximum size of the file in bytes, there's no
+	// limit when zero.
+	MaxBytes int64
+	// MinWidth, MinHeight, MaxWidth and MaxHeight limit the dimensions of
+	// images in pixels, where zero is no limit. They're only checked for
+	// PNG, JPEG and GIF images, whose dimensions can be read from the
+	// start of the file.
+	MinWidth  int
+	MinHeight int
+	MaxWidth  int
+	MaxHeight int
+}
+
+// Constraints that can be broken by a file, set on ValidationError.
+const (
+	ConstraintType       = "type"
+	ConstraintSize       = "size"
+	ConstraintDimensions = "dimensions"
+)
+
+// ValidationError is returned when a file breaks one of the
+// UploadConstraints of an upload. Use errors.As to retrieve it.
+type ValidationError struct {
+	// Constraint is the constraint that was broken, one of
+	// ConstraintType, ConstraintSize or ConstraintDimensions.
+	Constraint string
+	// Message describes how the constraint was broken.
+	Message string
+}
+
+// Error implements the error interface.
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("upload validation failed: %s", e.Message)
+}
+
+// dimensionsLen is the maximum number of bytes read from the start of an
+// image to find its dimensions. JPEGs may have large metadata segments
+// before the frame header.
+const dimensionsLen = 64 << 10
+
+// empty reports whether there are no constraints.
+func (c UploadConstraints) empty() bool {
+	return len(c.AllowedTypes) == 0 && c.MaxBytes <= 0 && !c.hasDimensions()
+}
+
+func (c UploadConstraints) hasDimensions() bool {
+	return c.MinWidth > 0 || c.MinHeight > 0 || c.MaxWidth > 0 || c.MaxHeight > 0
+}
+
+// check checks the file against the constraints. When the size of the
+// file isn't known, the rest of the file is limited so that the upload
+// fails once it's larger than MaxBytes.
+func (c UploadConstraints) check(file *filePart) error {
+	if c.empty() {
+		return nil
+	}
+
+	if len(c.AllowedTypes) > 0 && !mimeAllowed(file.mime, c.AllowedTypes) {
+		return &ValidationError{
+			Constraint: ConstraintType,
+			Message:    fmt.Sprintf("type %s is not one of %s", file.mime, strings.Join(c.AllowedTypes, ", ")),
+		}
+	}
+
+	if c.hasDimensions() && strings.HasPrefix(file.mime, "image/") {
+		if err := file.peek(dimensionsLen); err != nil {
+			return err
+		}
+		if err := c.checkDimensions(file.head); err != nil {
+			return err
+		}
+	}
+
+	if c.MaxBytes > 0 {
+		tooLarge := func(size string) error {
+			return &ValidationError{
+				Constraint: ConstraintSize,
+				Message:    fmt.Sprintf("%s is over the limit of %d bytes", size, c.MaxBytes),
+			}
+		}
+		switch {
+		case file.size > c.MaxBytes:
+			return tooLarge(fmt.Sprintf("%d bytes", file.size))
+		case int64(len(file.head)) > c.MaxBytes:
+			return tooLarge("file")
+		case file.size < 0:
+			file.rest = &maxBytesReader{
+				r:         file.rest,
+				remaining: c.MaxBytes - int64(len(file.head)),
+				err:       tooLarge("file"),
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkDimensions checks the dimensions of the image, skipping formats
+// that can't be decoded.
+func (c UploadConstraints) checkDimensions(head []byte) error {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(head))
+	if err != nil {
+		return nil //nolint:nilerr // Unknown formats aren't checked.
+	}
+
+	var broken []string
+	if c.MinWidth > 0 && cfg.Width < c.MinWidth {
+		broken = append(broken, fmt.Sprintf("width is under the minimum of %d", c.MinWidth))
+	}
+	if c.MaxWidth > 0 && cfg.Width > c.MaxWidth {
+		broken = append(broken, fmt.Sprintf("width is over the maximum of %d", c.MaxWidth))
+	}
+	if c.MinHeight > 0 && cfg.Height < c.MinHeight {
+		broken = append(broken, fmt.Sprintf("height is under the minimum of %d", c.MinHeight))
+	}
+	if c.MaxHeight > 0 && cfg.Height > c.MaxHeight {
+		broken = append(broken, fmt.Sprintf("height is over the maximum of %d", c.MaxHeight))
+	}
+	if len(broken) == 0 {
+		return nil
+	}
+
+	return &ValidationError{
+		Constraint: ConstraintDimensions,
+		Message:    fmt.Sprintf("image is %dx%d, %s", cfg.Width, cfg.Height, strings.Join(broken, ", ")),
+	}
+}
+
+// peek reads the start of the file into head until it's at least n
+// bytes, or the file has been read in full.
+func (f *filePart) peek(n int) error {
+	if len(f.head) >= n {
+		return nil
+	}
+	buf := make([]byte, n-len(f.head))
+	read, err := io.ReadFull(f.rest, buf)
+	f.head = append(f.head, buf[:read]...)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return fmt.Errorf("failed to read file: %w", err)
+	}
+	return nil
+}
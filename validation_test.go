@@ -0,0 +1,190 @@
+package payloadcms
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/png"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testPNG encodes a blank PNG of the given dimensions.
+func testPNG(t *testing.T, width, height int) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, image.NewGray(image.Rect(0, 0, width, height))))
+	return buf.Bytes()
+}
+
+func TestMediaService_UploadConstraints(t *testing.T) {
+	t.Parallel()
+
+	img := testPNG(t, 200, 100)
+	text := bytes.Repeat([]byte("a"), sniffLen*2)
+
+	tt := map[string]struct {
+		file           func() io.Reader
+		opts           MediaOptions
+		wantConstraint string
+		wantMessage    string
+	}{
+		"Allowed Type": {
+			file: func() io.Reader { return bytes.NewReader(img) },
+			opts: MediaOptions{Constraints: UploadConstraints{AllowedTypes: []string{"image/png"}}},
+		},
+		"Allowed Wildcard": {
+			file: func() io.Reader { return bytes.NewReader(img) },
+			opts: MediaOptions{Constraints: UploadConstraints{AllowedTypes: []string{"application/pdf", "image/*"}}},
+		},
+		"Type Not Allowed": {
+			file:           func() io.Reader { return bytes.NewReader(text) },
+			opts:           MediaOptions{Constraints: UploadConstraints{AllowedTypes: []string{"image/*"}}},
+			wantConstraint: ConstraintType,
+			wantMessage:    "type text/plain; charset=utf-8 is not one of image/*",
+		},
+		"Explicit Type Not Allowed": {
+			file:           func() io.Reader { return bytes.NewReader(img) },
+			opts:           MediaOptions{ContentType: "image/svg+xml", Constraints: UploadConstraints{AllowedTypes: []string{"image/png"}}},
+			wantConstraint: ConstraintType,
+		},
+		"Known Size Too Large": {
+			file:           func() io.Reader { return bytes.NewReader(text) },
+			opts:           MediaOptions{Constraints: UploadConstraints{MaxBytes: 100}},
+			wantConstraint: ConstraintSize,
+			wantMessage:    "6144 bytes is over the limit of 100 bytes",
+		},
+		"Unknown Size Too Large For Head": {
+			file:           func() io.Reader { return io.MultiReader(bytes.NewReader(text)) },
+			opts:           MediaOptions{Constraints: UploadConstraints{MaxBytes: 100}},
+			wantConstraint: ConstraintSize,
+		},
+		"Within Size": {
+			file: func() io.Reader { return io.MultiReader(bytes.NewReader(text)) },
+			opts: MediaOptions{Constraints: UploadConstraints{MaxBytes: int64(len(text))}},
+		},
+		"Within Dimensions": {
+			file: func() io.Reader { return bytes.NewReader(img) },
+			opts: MediaOptions{Constraints: UploadConstraints{MinWidth: 200, MaxWidth: 200, MinHeight: 50, MaxHeight: 100}},
+		},
+		"Too Narrow": {
+			file:           func() io.Reader { return bytes.NewReader(img) },
+			opts:           MediaOptions{Constraints: UploadConstraints{MinWidth: 300}},
+			wantConstraint: ConstraintDimensions,
+			wantMessage:    "image is 200x100, width is under the minimum of 300",
+		},
+		"Too Tall And Wide": {
+			file:           func() io.Reader { return bytes.NewReader(img) },
+			opts:           MediaOptions{Constraints: UploadConstraints{MaxWidth: 100, MaxHeight: 50}},
+			wantConstraint: ConstraintDimensions,
+			wantMessage:    "image is 200x100, width is over the maximum of 100, height is over the maximum of 50",
+		},
+		"Dimensions With Explicit Type": {
+			file:           func() io.Reader { return bytes.NewReader(img) },
+			opts:           MediaOptions{ContentType: "image/png", Constraints: UploadConstraints{MinHeight: 101}},
+			wantConstraint: ConstraintDimensions,
+		},
+		"Dimensions Of Unknown Format": {
+			file: func() io.Reader { return strings.NewReader(`<svg xmlns="http://www.w3.org/2000/svg"></svg>`) },
+			opts: MediaOptions{ContentType: "image/svg+xml", Constraints: UploadConstraints{MinWidth: 100}},
+		},
+		"Dimensions Of Non Image": {
+			file: func() io.Reader { return bytes.NewReader(text) },
+			opts: MediaOptions{Constraints: UploadConstraints{MinWidth: 100}},
+		},
+	}
+
+	for name, test := range tt {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var m MediaServiceOp
+			if test.wantConstraint != "" {
+				m.Client = noUpload(t)
+			} else {
+				client, _ := uploadedFile(t)
+				m.Client = client
+			}
+
+			opts := test.opts
+			opts.FileName = "file"
+			if opts.ContentType != "" {
+				opts.FileName = "file.bin"
+			}
+
+			_, err := m.Upload(context.TODO(), test.file(), nil, nil, opts)
+			if test.wantConstraint == "" {
+				require.NoError(t, err)
+				return
+			}
+
+			var verr *ValidationError
+			require.ErrorAs(t, err, &verr)
+			AssertEqual(t, test.wantConstraint, verr.Constraint)
+			if test.wantMessage != "" {
+				AssertEqual(t, test.wantMessage, verr.Message)
+			}
+		})
+	}
+
+	t.Run("Unknown Size Too Large While Streaming", func(t *testing.T) {
+		t.Parallel()
+
+		client, teardown := Setup(t, func(_ http.ResponseWriter, r *http.Request) {
+			_, _ = io.Copy(io.Discard, r.Body)
+		})
+		defer teardown()
+
+		m := MediaServiceOp{Client: client}
+		_, err := m.Upload(context.TODO(), io.MultiReader(bytes.NewReader(text)), nil, nil, MediaOptions{
+			FileName:    "file",
+			Constraints: UploadConstraints{MaxBytes: int64(len(text)) - 1},
+		})
+
+		var verr *ValidationError
+		require.ErrorAs(t, err, &verr)
+		AssertEqual(t, ConstraintSize, verr.Constraint)
+	})
+}
+
+func TestValidationError(t *testing.T) {
+	t.Parallel()
+
+	var err error = &ValidationError{Constraint: ConstraintSize, Message: "file is too large"}
+	AssertEqual(t, "upload validation failed: file is too large", err.Error())
+
+	var verr *ValidationError
+	assert.True(t, errors.As(err, &verr))
+}
+
+func TestMimeAllowed(t *testing.T) {
+	t.Parallel()
+
+	tt := map[string]struct {
+		mime    string
+		allowed []string
+		want    bool
+	}{
+		"Exact":             {mime: "image/png", allowed: []string{"image/png"}, want: true},
+		"Case Insensitive":  {mime: "Image/PNG", allowed: []string{"image/png"}, want: true},
+		"Parameters":        {mime: "text/plain; charset=utf-8", allowed: []string{"text/plain"}, want: true},
+		"Wildcard":          {mime: "image/webp", allowed: []string{"image/*"}, want: true},
+		"Wildcard Mismatch": {mime: "imagex/webp", allowed: []string{"image/*"}, want: false},
+		"Alias":             {mime: "application/x-pdf", allowed: []string{"application/pdf"}, want: true},
+		"Not Allowed":       {mime: "application/zip", allowed: []string{"image/*", "text/plain"}, want: false},
+		"Unknown Type":      {mime: "application/x-custom", allowed: []string{"application/x-custom"}, want: true},
+	}
+
+	for name, test := range tt {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			AssertEqual(t, test.want, mimeAllowed(test.mime, test.allowed))
+		})
+	}
+}
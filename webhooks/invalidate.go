@@ -0,0 +1,76 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+
+	"github.com/ainsleyclark/go-payloadcms"
+)
+
+// Invalidator removes cached responses, it's implemented by
+// *payloadcms.Client when it's created with payloadcms.WithCache.
+type Invalidator interface {
+	InvalidateDocument(collection payloadcms.Collection, id any)
+	InvalidateCollection(collection payloadcms.Collection)
+	InvalidateGlobal(global payloadcms.Global)
+}
+
+// Ensure the Client is an Invalidator.
+var _ Invalidator = (*payloadcms.Client)(nil)
+
+// InvalidateCache registers a callback that removes the cached responses
+// of whatever changed for every webhook, so the cache is fresh as soon
+// as a document is changed in Payload rather than once its TTL expires.
+//
+// The document and the lists of its collection are removed for webhooks
+// of a collection, or the whole collection when the document has no ID.
+// The global is removed for webhooks of a global.
+//
+// Example:
+//
+//	client, _ := payloadcms.New(payloadcms.WithCache(payloadcms.NewMemoryCache(1000), time.Minute))
+//	h := webhooks.NewHandler(os.Getenv("WEBHOOK_SECRET"))
+//	webhooks.InvalidateCache(h, client)
+//	http.Handle("/webhooks/payload", h)
+func InvalidateCache(h *Handler, cache Invalidator) {
+	h.HandleAll(func(_ context.Context, e Event) error {
+		if e.Global != "" {
+			cache.InvalidateGlobal(e.Global)
+		}
+		if e.Collection == "" {
+			return nil
+		}
+		if id := docID(e.Doc); id != nil {
+			cache.InvalidateDocument(e.Collection, id)
+			return nil
+		}
+		cache.InvalidateCollection(e.Collection)
+		return nil
+	})
+}
+
+// docID returns the ID of the document as a json.Number or string, or
+// nil if it doesn't have one.
+func docID(raw json.RawMessage) any {
+	if !present(raw) {
+		return nil
+	}
+	var doc struct {
+		ID any `json:"id"`
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	if err := dec.Decode(&doc); err != nil {
+		return nil
+	}
+	switch id := doc.ID.(type) {
+	case json.Number:
+		return id
+	case string:
+		if id != "" {
+			return id
+		}
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ainsleyclark/go-payloadcms"
+)
+
+type invalidation struct {
+	Method string
+	Name   string
+	ID     any
+}
+
+type recordingInvalidator struct {
+	calls []invalidation
+}
+
+func (r *recordingInvalidator) InvalidateDocument(collection payloadcms.Collection, id any) {
+	r.calls = append(r.calls, invalidation{Method: "document", Name: string(collection), ID: id})
+}
+
+func (r *recordingInvalidator) InvalidateCollection(collection payloadcms.Collection) {
+	r.calls = append(r.calls, invalidation{Method: "collection", Name: string(collection)})
+}
+
+func (r *recordingInvalidator) InvalidateGlobal(global payloadcms.Global) {
+	r.calls = append(r.calls, invalidation{Method: "global", Name: string(global)})
+}
+
+func TestInvalidateCache(t *testing.T) {
+	t.Parallel()
+
+	tt := map[string]struct {
+		body string
+		want []invalidation
+	}{
+		"Numeric ID": {
+			body: `{"collection":"posts","operation":"update","doc":{"id":1}}`,
+			want: []invalidation{{Method: "document", Name: "posts", ID: json.Number("1")}},
+		},
+		"String ID": {
+			body: `{"collection":"posts","operation":"delete","doc":{"id":"abc"}}`,
+			want: []invalidation{{Method: "document", Name: "posts", ID: "abc"}},
+		},
+		"No ID": {
+			body: `{"collection":"posts","operation":"update","doc":{"title":"Hello"}}`,
+			want: []invalidation{{Method: "collection", Name: "posts"}},
+		},
+		"No Doc": {
+			body: `{"collection":"posts","operation":"update"}`,
+			want: []invalidation{{Method: "collection", Name: "posts"}},
+		},
+		"Global": {
+			body: `{"global":"settings","operation":"update","doc":{"siteName":"Site"}}`,
+			want: []invalidation{{Method: "global", Name: "settings"}},
+		},
+	}
+
+	for name, test := range tt {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			h := newTestHandler()
+			cache := &recordingInvalidator{}
+			InvalidateCache(h, cache)
+
+			rec := send(h, test.body, now)
+			require.Equal(t, http.StatusNoContent, rec.Code)
+			assert.Equal(t, test.want, cache.calls)
+		})
+	}
+
+	t.Run("Client", func(t *testing.T) {
+		t.Parallel()
+
+		var hits atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = fmt.Fprintf(w, `{"id":1,"title":"Post %d"}`, hits.Add(1))
+		}))
+		t.Cleanup(server.Close)
+
+		client, err := payloadcms.New(
+			payloadcms.WithBaseURL(server.URL),
+			payloadcms.WithCache(payloadcms.NewMemoryCache(10), time.Hour),
+		)
+		require.NoError(t, err)
+
+		h := newTestHandler()
+		InvalidateCache(h, client)
+
+		find := func() string {
+			var p post
+			_, err := client.Collections.FindByID(context.Background(), "posts", 1, &p)
+			require.NoError(t, err)
+			return p.Title
+		}
+
+		assert.Equal(t, "Post 1", find())
+		assert.Equal(t, "Post 1", find())
+
+		rec := send(h, `{"collection":"posts","operation":"update","doc":{"id":1}}`, now)
+		require.Equal(t, http.StatusNoContent, rec.Code)
+
+		assert.Equal(t, "Post 2", find())
+		assert.Equal(t, int32(2), hits.Load())
+	})
+}
@@ -0,0 +1,78 @@
+package webhooks
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"time"
+)
+
+// Headers that carry the signature of a webhook.
+const (
+	// SignatureHeader is the hex encoded HMAC-SHA256 of the timestamp
+	// and the body, joined by a dot.
+	SignatureHeader = "X-Payload-Signature"
+	// TimestampHeader is the time the webhook was sent, in Unix seconds.
+	TimestampHeader = "X-Payload-Timestamp"
+)
+
+// Errors returned by Verify.
+var (
+	// ErrInvalidSignature is returned when the signature is missing or
+	// doesn't match the body.
+	ErrInvalidSignature = errors.New("webhooks: invalid signature")
+	// ErrInvalidTimestamp is returned when the timestamp is missing or
+	// outside the tolerance of the handler.
+	ErrInvalidTimestamp = errors.New("webhooks: invalid timestamp")
+	// ErrNoSecret is returned when the secret is empty, as anyone can
+	// sign a webhook with an empty secret.
+	ErrNoSecret = errors.New("webhooks: no secret")
+)
+
+// Sign returns the signature of the body sent at the timestamp, which is
+// sent in the SignatureHeader along with the timestamp in the
+// TimestampHeader. It's useful for tests and for sending webhooks from
+// Go; Payload hooks compute the same signature with:
+//
+//	crypto.createHmac('sha256', secret).update(`${timestamp}.${body}`).digest('hex')
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	return sign(secret, strconv.FormatInt(timestamp.Unix(), 10), body)
+}
+
+// Verify checks that the signature matches the body and that the
+// timestamp is within the tolerance of now, returning the time the
+// webhook was sent. An empty secret is always rejected with ErrNoSecret.
+func Verify(secret, signature, timestamp string, body []byte, tolerance time.Duration, now time.Time) (time.Time, error) {
+	if secret == "" {
+		return time.Time{}, ErrNoSecret
+	}
+
+	sec, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return time.Time{}, ErrInvalidTimestamp
+	}
+	sent := time.Unix(sec, 0)
+
+	if tolerance > 0 {
+		if d := now.Sub(sent); d > tolerance || d < -tolerance {
+			return time.Time{}, ErrInvalidTimestamp
+		}
+	}
+
+	want := sign(secret, timestamp, body)
+	if signature == "" || !hmac.Equal([]byte(signature), []byte(want)) {
+		return time.Time{}, ErrInvalidSignature
+	}
+
+	return sent, nil
+}
+
+func sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,339 @@
+// Package webhooks receives the webhooks sent by Payload hooks, such as
+// afterChange and afterDelete, and dispatches them to typed callbacks.
+//
+// Each webhook is signed with a shared secret using HMAC-SHA256 over the
+// timestamp and the body, and webhooks outside of the tolerance window
+// or that have already been received are rejected. See Sign for how the
+// signature is computed.
+//
+// Example:
+//
+//	h := webhooks.NewHandler(os.Getenv("WEBHOOK_SECRET"))
+//	webhooks.OnCollection(h, "posts", func(ctx context.Context, c webhooks.Change[Post]) error {
+//		return search.Index(ctx, c.Doc)
+//	})
+//	http.Handle("/webhooks/payload", h)
+//
+// See: https://payloadcms.com/docs/hooks/overview
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ainsleyclark/go-payloadcms"
+)
+
+// Operation is the operation that triggered the hook.
+type Operation string
+
+// Operations sent by Payload hooks.
+const (
+	OperationCreate Operation = "create"
+	OperationUpdate Operation = "update"
+	OperationDelete Operation = "delete"
+)
+
+// Event is the envelope of a webhook. Either the Collection or the
+// Global is set, depending on what changed.
+type Event struct {
+	Collection  payloadcms.Collection `json:"collection,omitempty"`
+	Global      payloadcms.Global     `json:"global,omitempty"`
+	Operation   Operation             `json:"operation"`
+	Doc         json.RawMessage       `json:"doc"`
+	PreviousDoc json.RawMessage       `json:"previousDoc,omitempty"`
+	// SentAt is the time the webhook was sent, from the TimestampHeader.
+	SentAt time.Time `json:"-"`
+}
+
+// Change is an Event with the documents decoded into T.
+type Change[T any] struct {
+	Collection payloadcms.Collection
+	Global     payloadcms.Global
+	Operation  Operation
+	// Doc is the document after the change, or the deleted document.
+	Doc T
+	// PreviousDoc is the document before the change, which is nil when
+	// it wasn't sent, such as for a create.
+	PreviousDoc *T
+	// Event is the webhook the change was decoded from.
+	Event Event
+}
+
+// Func handles a webhook.
+type Func func(ctx context.Context, e Event) error
+
+// Handler is an http.Handler that verifies and dispatches webhooks sent
+// by Payload hooks. It responds with:
+//
+//   - 401 if the signature or timestamp is invalid, or the webhook has
+//     already been received.
+//   - 400 if the body can't be decoded.
+//   - 500 if a callback returns an error, so the hook can retry, or
+//     if the handler was created with an empty secret.
+//   - 204 otherwise, including when no callback is registered.
+type Handler struct {
+	secret    string
+	tolerance time.Duration
+	maxBytes  int64
+	now       func() time.Time
+	onError   func(r *http.Request, err error)
+
+	mu          sync.RWMutex
+	collections map[payloadcms.Collection][]Func
+	globals     map[payloadcms.Global][]Func
+	fallback    []Func
+
+	seenMu sync.Mutex
+	seen   map[string]time.Time
+}
+
+// Option is a functional option type that allows us to configure a Handler.
+type Option func(*Handler)
+
+const (
+	// DefaultTolerance is the default window either side of now that the
+	// timestamp of a webhook must be within.
+	DefaultTolerance = 5 * time.Minute
+	// DefaultMaxBytes is the default maximum size of a webhook body.
+	DefaultMaxBytes = 5 << 20
+)
+
+// WithTolerance sets the window either side of now that the timestamp
+// of a webhook must be within.
+func WithTolerance(d time.Duration) Option {
+	return func(h *Handler) {
+		h.tolerance = d
+	}
+}
+
+// WithMaxBytes sets the maximum size of a webhook body.
+func WithMaxBytes(n int64) Option {
+	return func(h *Handler) {
+		h.maxBytes = n
+	}
+}
+
+// WithErrorHandler sets the function called with the errors of rejected
+// webhooks and callbacks, for logging.
+func WithErrorHandler(fn func(r *http.Request, err error)) Option {
+	return func(h *Handler) {
+		h.onError = fn
+	}
+}
+
+// NewHandler creates a new Handler that verifies webhooks with the
+// shared secret. The secret must not be empty, otherwise every webhook
+// is rejected with a 500 and ErrNoSecret.
+func NewHandler(secret string, options ...Option) *Handler {
+	h := &Handler{
+		secret:      secret,
+		tolerance:   DefaultTolerance,
+		maxBytes:    DefaultMaxBytes,
+		now:         time.Now,
+		collections: make(map[payloadcms.Collection][]Func),
+		globals:     make(map[payloadcms.Global][]Func),
+		seen:        make(map[string]time.Time),
+	}
+	for _, opt := range options {
+		opt(h)
+	}
+	if h.tolerance <= 0 {
+		h.tolerance = DefaultTolerance
+	}
+	return h
+}
+
+// HandleCollection registers the callback for webhooks of the collection.
+func (h *Handler) HandleCollection(collection payloadcms.Collection, fn Func) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.collections[collection] = append(h.collections[collection], fn)
+}
+
+// HandleGlobal registers the callback for webhooks of the global.
+func (h *Handler) HandleGlobal(global payloadcms.Global, fn Func) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.globals[global] = append(h.globals[global], fn)
+}
+
+// HandleAll registers the callback for every webhook, after any
+// callbacks registered for the collection or global.
+func (h *Handler) HandleAll(fn Func) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.fallback = append(h.fallback, fn)
+}
+
+// OnCollection registers the callback for webhooks of the collection,
+// decoding the documents into T.
+func OnCollection[T any](h *Handler, collection payloadcms.Collection, fn func(ctx context.Context, c Change[T]) error) {
+	h.HandleCollection(collection, typed(fn))
+}
+
+// OnGlobal registers the callback for webhooks of the global, decoding
+// the documents into T.
+func OnGlobal[T any](h *Handler, global payloadcms.Global, fn func(ctx context.Context, c Change[T]) error) {
+	h.HandleGlobal(global, typed(fn))
+}
+
+// typed returns a Func that decodes the event into a Change.
+func typed[T any](fn func(ctx context.Context, c Change[T]) error) Func {
+	return func(ctx context.Context, e Event) error {
+		c, err := Decode[T](e)
+		if err != nil {
+			return err
+		}
+		return fn(ctx, c)
+	}
+}
+
+// Decode decodes the documents of the event into T.
+func Decode[T any](e Event) (Change[T], error) {
+	c := Change[T]{
+		Collection: e.Collection,
+		Global:     e.Global,
+		Operation:  e.Operation,
+		Event:      e,
+	}
+	if present(e.Doc) {
+		if err := json.Unmarshal(e.Doc, &c.Doc); err != nil {
+			return c, fmt.Errorf("decoding doc: %w", err)
+		}
+	}
+	if present(e.PreviousDoc) {
+		c.PreviousDoc = new(T)
+		if err := json.Unmarshal(e.PreviousDoc, c.PreviousDoc); err != nil {
+			return c, fmt.Errorf("decoding previousDoc: %w", err)
+		}
+	}
+	return c, nil
+}
+
+// present reports whether the raw JSON is set and isn't null or an
+// empty object, which Payload sends as the previousDoc of a create.
+func present(raw json.RawMessage) bool {
+	raw = bytes.TrimSpace(raw)
+	return len(raw) > 0 && !bytes.Equal(raw, []byte("null")) && !bytes.Equal(raw, []byte("{}"))
+}
+
+// ServeHTTP implements http.Handler.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	if h.secret == "" {
+		h.reject(w, r, http.StatusInternalServerError, ErrNoSecret)
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, h.maxBytes))
+	if err != nil {
+		h.reject(w, r, http.StatusBadRequest, fmt.Errorf("reading body: %w", err))
+		return
+	}
+
+	signature := r.Header.Get(SignatureHeader)
+	sent, err := Verify(h.secret, signature, r.Header.Get(TimestampHeader), body, h.tolerance, h.now())
+	if err != nil {
+		h.reject(w, r, http.StatusUnauthorized, err)
+		return
+	}
+	if !h.first(signature, sent) {
+		h.reject(w, r, http.StatusUnauthorized, ErrReplayed)
+		return
+	}
+
+	var e Event
+	if err := json.Unmarshal(body, &e); err != nil {
+		h.forget(signature)
+		h.reject(w, r, http.StatusBadRequest, fmt.Errorf("decoding event: %w", err))
+		return
+	}
+	if e.Collection == "" && e.Global == "" {
+		h.forget(signature)
+		h.reject(w, r, http.StatusBadRequest, errors.New("event has no collection or global"))
+		return
+	}
+	e.SentAt = sent
+
+	if err := h.Dispatch(r.Context(), e); err != nil {
+		// Allow the hook to retry the webhook.
+		h.forget(signature)
+		h.reject(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Dispatch calls the callbacks registered for the event, stopping at the
+// first error.
+func (h *Handler) Dispatch(ctx context.Context, e Event) error {
+	h.mu.RLock()
+	var fns []Func
+	if e.Collection != "" {
+		fns = append(fns, h.collections[e.Collection]...)
+	}
+	if e.Global != "" {
+		fns = append(fns, h.globals[e.Global]...)
+	}
+	fns = append(fns, h.fallback...)
+	h.mu.RUnlock()
+
+	for _, fn := range fns {
+		if err := fn(ctx, e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ErrReplayed is returned when a webhook with the same signature has
+// already been received within the tolerance window.
+var ErrReplayed = errors.New("webhooks: webhook already received")
+
+// first records the signature, reporting false if it's already been
+// received. Signatures are kept until the timestamp they were sent at
+// falls outside the tolerance window, after which Verify rejects them.
+func (h *Handler) first(signature string, sent time.Time) bool {
+	h.seenMu.Lock()
+	defer h.seenMu.Unlock()
+
+	now := h.now()
+	for s, t := range h.seen {
+		if now.Sub(t) > h.tolerance {
+			delete(h.seen, s)
+		}
+	}
+
+	if _, ok := h.seen[signature]; ok {
+		return false
+	}
+	h.seen[signature] = sent
+	return true
+}
+
+// forget removes the signature, so the webhook can be sent again.
+func (h *Handler) forget(signature string) {
+	h.seenMu.Lock()
+	defer h.seenMu.Unlock()
+	delete(h.seen, signature)
+}
+
+func (h *Handler) reject(w http.ResponseWriter, r *http.Request, status int, err error) {
+	if h.onError != nil {
+		h.onError(r, err)
+	}
+	http.Error(w, http.StatusText(status), status)
+}
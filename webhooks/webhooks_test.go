@@ -0,0 +1,379 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const secret = "shh"
+
+type post struct {
+	ID    int    `json:"id"`
+	Title string `json:"title"`
+}
+
+type settings struct {
+	SiteName string `json:"siteName"`
+}
+
+var now = time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+// send signs the body at the timestamp and serves it to the handler.
+func send(h http.Handler, body string, at time.Time) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/webhooks", strings.NewReader(body))
+	req.Header.Set(TimestampHeader, strconv.FormatInt(at.Unix(), 10))
+	req.Header.Set(SignatureHeader, Sign(secret, at, []byte(body)))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func newTestHandler(options ...Option) *Handler {
+	h := NewHandler(secret, options...)
+	h.now = func() time.Time { return now }
+	return h
+}
+
+func TestHandler_Dispatch(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Collection", func(t *testing.T) {
+		t.Parallel()
+
+		h := newTestHandler()
+		var got Change[post]
+		OnCollection(h, "posts", func(_ context.Context, c Change[post]) error {
+			got = c
+			return nil
+		})
+		OnCollection(h, "pages", func(_ context.Context, _ Change[post]) error {
+			t.Error("unexpected call for pages")
+			return nil
+		})
+
+		rec := send(h, `{
+			"collection": "posts",
+			"operation": "update",
+			"doc": {"id": 1, "title": "New"},
+			"previousDoc": {"id": 1, "title": "Old"}
+		}`, now)
+		require.Equal(t, http.StatusNoContent, rec.Code)
+
+		assert.Equal(t, "posts", string(got.Collection))
+		assert.Equal(t, OperationUpdate, got.Operation)
+		assert.Equal(t, post{ID: 1, Title: "New"}, got.Doc)
+		require.NotNil(t, got.PreviousDoc)
+		assert.Equal(t, "Old", got.PreviousDoc.Title)
+		assert.Equal(t, now, got.Event.SentAt.UTC())
+	})
+
+	t.Run("Create Has No Previous Doc", func(t *testing.T) {
+		t.Parallel()
+
+		h := newTestHandler()
+		var got Change[post]
+		OnCollection(h, "posts", func(_ context.Context, c Change[post]) error {
+			got = c
+			return nil
+		})
+
+		rec := send(h, `{"collection": "posts", "operation": "create", "doc": {"id": 2}, "previousDoc": {}}`, now)
+		require.Equal(t, http.StatusNoContent, rec.Code)
+		assert.Equal(t, OperationCreate, got.Operation)
+		assert.Nil(t, got.PreviousDoc)
+	})
+
+	t.Run("Global", func(t *testing.T) {
+		t.Parallel()
+
+		h := newTestHandler()
+		var got Change[settings]
+		OnGlobal(h, "settings", func(_ context.Context, c Change[settings]) error {
+			got = c
+			return nil
+		})
+
+		rec := send(h, `{"global": "settings", "operation": "update", "doc": {"siteName": "Payload"}}`, now)
+		require.Equal(t, http.StatusNoContent, rec.Code)
+		assert.Equal(t, "settings", string(got.Global))
+		assert.Equal(t, "Payload", got.Doc.SiteName)
+	})
+
+	t.Run("HandleAll", func(t *testing.T) {
+		t.Parallel()
+
+		h := newTestHandler()
+		var order []string
+		h.HandleCollection("posts", func(_ context.Context, _ Event) error {
+			order = append(order, "posts")
+			return nil
+		})
+		h.HandleAll(func(_ context.Context, e Event) error {
+			order = append(order, "all:"+string(e.Collection))
+			return nil
+		})
+
+		send(h, `{"collection": "posts", "operation": "delete", "doc": {"id": 1}}`, now)
+		send(h, `{"collection": "media", "operation": "delete", "doc": {"id": 1}}`, now)
+		assert.Equal(t, []string{"posts", "all:posts", "all:media"}, order)
+	})
+
+	t.Run("No Callback", func(t *testing.T) {
+		t.Parallel()
+
+		rec := send(newTestHandler(), `{"collection": "posts", "operation": "create", "doc": {}}`, now)
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+	})
+
+	t.Run("Callback Error Allows Retry", func(t *testing.T) {
+		t.Parallel()
+
+		var errs []error
+		h := newTestHandler(WithErrorHandler(func(_ *http.Request, err error) {
+			errs = append(errs, err)
+		}))
+		calls := 0
+		h.HandleCollection("posts", func(_ context.Context, _ Event) error {
+			calls++
+			if calls == 1 {
+				return errors.New("index unavailable")
+			}
+			return nil
+		})
+
+		body := `{"collection": "posts", "operation": "create", "doc": {"id": 1}}`
+		assert.Equal(t, http.StatusInternalServerError, send(h, body, now).Code)
+		assert.Equal(t, http.StatusNoContent, send(h, body, now).Code)
+		require.Len(t, errs, 1)
+		assert.EqualError(t, errs[0], "index unavailable")
+	})
+
+	t.Run("Decode Error", func(t *testing.T) {
+		t.Parallel()
+
+		h := newTestHandler()
+		OnCollection(h, "posts", func(_ context.Context, _ Change[post]) error {
+			return nil
+		})
+
+		rec := send(h, `{"collection": "posts", "operation": "create", "doc": {"id": "not a number"}}`, now)
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	})
+}
+
+func TestHandler_Reject(t *testing.T) {
+	t.Parallel()
+
+	body := `{"collection": "posts", "operation": "create", "doc": {"id": 1}}`
+
+	tt := map[string]struct {
+		request func() *http.Request
+		want    int
+		wantErr error
+	}{
+		"Method": {
+			request: func() *http.Request {
+				return httptest.NewRequest(http.MethodGet, "/", nil)
+			},
+			want: http.StatusMethodNotAllowed,
+		},
+		"No Signature": {
+			request: func() *http.Request {
+				req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+				req.Header.Set(TimestampHeader, strconv.FormatInt(now.Unix(), 10))
+				return req
+			},
+			want:    http.StatusUnauthorized,
+			wantErr: ErrInvalidSignature,
+		},
+		"Wrong Secret": {
+			request: func() *http.Request {
+				req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+				req.Header.Set(TimestampHeader, strconv.FormatInt(now.Unix(), 10))
+				req.Header.Set(SignatureHeader, Sign("wrong", now, []byte(body)))
+				return req
+			},
+			want:    http.StatusUnauthorized,
+			wantErr: ErrInvalidSignature,
+		},
+		"Tampered Body": {
+			request: func() *http.Request {
+				req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(strings.Replace(body, "1", "2", 1)))
+				req.Header.Set(TimestampHeader, strconv.FormatInt(now.Unix(), 10))
+				req.Header.Set(SignatureHeader, Sign(secret, now, []byte(body)))
+				return req
+			},
+			want:    http.StatusUnauthorized,
+			wantErr: ErrInvalidSignature,
+		},
+		"Tampered Timestamp": {
+			request: func() *http.Request {
+				req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+				req.Header.Set(TimestampHeader, strconv.FormatInt(now.Unix()+1, 10))
+				req.Header.Set(SignatureHeader, Sign(secret, now, []byte(body)))
+				return req
+			},
+			want:    http.StatusUnauthorized,
+			wantErr: ErrInvalidSignature,
+		},
+		"No Timestamp": {
+			request: func() *http.Request {
+				req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+				req.Header.Set(SignatureHeader, Sign(secret, now, []byte(body)))
+				return req
+			},
+			want:    http.StatusUnauthorized,
+			wantErr: ErrInvalidTimestamp,
+		},
+		"Too Old": {
+			request: func() *http.Request {
+				at := now.Add(-DefaultTolerance - time.Second)
+				req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+				req.Header.Set(TimestampHeader, strconv.FormatInt(at.Unix(), 10))
+				req.Header.Set(SignatureHeader, Sign(secret, at, []byte(body)))
+				return req
+			},
+			want:    http.StatusUnauthorized,
+			wantErr: ErrInvalidTimestamp,
+		},
+		"Too Far In Future": {
+			request: func() *http.Request {
+				at := now.Add(DefaultTolerance + time.Second)
+				req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+				req.Header.Set(TimestampHeader, strconv.FormatInt(at.Unix(), 10))
+				req.Header.Set(SignatureHeader, Sign(secret, at, []byte(body)))
+				return req
+			},
+			want:    http.StatusUnauthorized,
+			wantErr: ErrInvalidTimestamp,
+		},
+		"Invalid JSON": {
+			request: func() *http.Request {
+				req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("{"))
+				req.Header.Set(TimestampHeader, strconv.FormatInt(now.Unix(), 10))
+				req.Header.Set(SignatureHeader, Sign(secret, now, []byte("{")))
+				return req
+			},
+			want: http.StatusBadRequest,
+		},
+		"No Collection Or Global": {
+			request: func() *http.Request {
+				b := `{"operation": "create"}`
+				req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(b))
+				req.Header.Set(TimestampHeader, strconv.FormatInt(now.Unix(), 10))
+				req.Header.Set(SignatureHeader, Sign(secret, now, []byte(b)))
+				return req
+			},
+			want: http.StatusBadRequest,
+		},
+		"Too Large": {
+			request: func() *http.Request {
+				b := `{"collection": "posts", "doc": {"title": "` + strings.Repeat("a", 100) + `"}}`
+				req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(b))
+				req.Header.Set(TimestampHeader, strconv.FormatInt(now.Unix(), 10))
+				req.Header.Set(SignatureHeader, Sign(secret, now, []byte(b)))
+				return req
+			},
+			want: http.StatusBadRequest,
+		},
+	}
+
+	for name, test := range tt {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var got error
+			h := newTestHandler(WithMaxBytes(100), WithErrorHandler(func(_ *http.Request, err error) {
+				got = err
+			}))
+			h.HandleAll(func(_ context.Context, _ Event) error {
+				t.Error("unexpected dispatch")
+				return nil
+			})
+
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, test.request())
+			assert.Equal(t, test.want, rec.Code)
+			if test.wantErr != nil {
+				assert.ErrorIs(t, got, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestHandler_NoSecret(t *testing.T) {
+	t.Parallel()
+
+	var got error
+	h := NewHandler("", WithErrorHandler(func(_ *http.Request, err error) {
+		got = err
+	}))
+	h.now = func() time.Time { return now }
+	h.HandleAll(func(_ context.Context, _ Event) error {
+		t.Error("unexpected dispatch")
+		return nil
+	})
+
+	// A webhook signed with the empty secret is still rejected.
+	body := `{"collection": "posts", "operation": "create", "doc": {"id": 1}}`
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	req.Header.Set(TimestampHeader, strconv.FormatInt(now.Unix(), 10))
+	req.Header.Set(SignatureHeader, Sign("", now, []byte(body)))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.ErrorIs(t, got, ErrNoSecret)
+}
+
+func TestHandler_Replay(t *testing.T) {
+	t.Parallel()
+
+	h := newTestHandler()
+	calls := 0
+	h.HandleAll(func(_ context.Context, _ Event) error {
+		calls++
+		return nil
+	})
+
+	body := `{"collection": "posts", "operation": "create", "doc": {"id": 1}}`
+	assert.Equal(t, http.StatusNoContent, send(h, body, now).Code)
+	assert.Equal(t, http.StatusUnauthorized, send(h, body, now).Code)
+
+	// The same body sent at a different time has a different signature.
+	assert.Equal(t, http.StatusNoContent, send(h, body, now.Add(-time.Second)).Code)
+	assert.Equal(t, 2, calls)
+
+	// Signatures are forgotten once they fall outside the window.
+	h.now = func() time.Time { return now.Add(DefaultTolerance + time.Minute) }
+	h.first("other", h.now())
+	assert.Len(t, h.seen, 1)
+}
+
+func TestSign(t *testing.T) {
+	t.Parallel()
+
+	// The HMAC-SHA256 of "1717243200.{"collection":"posts"}".
+	got := Sign("secret", time.Unix(1717243200, 0), []byte(`{"collection":"posts"}`))
+	assert.Equal(t, "ae8d9c5b1d7a522a1e11a8d914b4ef16c0447cc5fd21b798d458d596af18ef8f", got)
+
+	sent, err := Verify("secret", got, "1717243200", []byte(`{"collection":"posts"}`), time.Minute, time.Unix(1717243230, 0))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1717243200), sent.Unix())
+
+	_, err = Verify("secret", got, "1717243200", []byte(`{"collection":"posts"}`), time.Minute, time.Unix(1717243300, 0))
+	assert.ErrorIs(t, err, ErrInvalidTimestamp)
+
+	empty := Sign("", time.Unix(1717243200, 0), []byte(`{"collection":"posts"}`))
+	_, err = Verify("", empty, "1717243200", []byte(`{"collection":"posts"}`), time.Minute, time.Unix(1717243230, 0))
+	assert.ErrorIs(t, err, ErrNoSecret)
+}
@@ -0,0 +1,105 @@
+package where
+
+import (
+	"cmp"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// dateLayouts are the layouts dates are read in, Payload stores them as
+// ISO 8601 strings.
+var dateLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	time.DateOnly,
+}
+
+// compare compares two values, reporting false if they can't be
+// compared. Values from a query string are strings, so a string is read
+// as a number or bool when compared to one. Strings that are both dates
+// are compared as times, and other strings lexically.
+func compare(a, b any) (int, bool) {
+	if an, ok := toNumber(a); ok {
+		if bn, ok := toNumber(b); ok {
+			return cmp.Compare(an, bn), true
+		}
+		if bs, ok := b.(string); ok {
+			if bn, err := strconv.ParseFloat(bs, 64); err == nil {
+				return cmp.Compare(an, bn), true
+			}
+		}
+		return 0, false
+	}
+	if _, ok := toNumber(b); ok {
+		c, ok := compare(b, a)
+		return -c, ok
+	}
+
+	if ab, ok := a.(bool); ok {
+		bb, ok := toBool(b)
+		if !ok {
+			return 0, false
+		}
+		return cmp.Compare(boolInt(ab), boolInt(bb)), true
+	}
+	if _, ok := b.(bool); ok {
+		c, ok := compare(b, a)
+		return -c, ok
+	}
+
+	as, aok := a.(string)
+	bs, bok := b.(string)
+	if !aok || !bok {
+		return 0, false
+	}
+	if at, ok := parseDate(as); ok {
+		if bt, ok := parseDate(bs); ok {
+			return at.Compare(bt), true
+		}
+	}
+	return strings.Compare(as, bs), true
+}
+
+func toNumber(v any) (float64, bool) {
+	switch n := v.(type) {
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint:
+		return float64(n), true
+	case uint32:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	default:
+		return 0, false
+	}
+}
+
+func parseDate(s string) (time.Time, bool) {
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func boolInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,168 @@
+package where
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/ainsleyclark/go-payloadcms"
+)
+
+// group holds the nested queries of an "and" or "or" while a query
+// string is parsed, by their index.
+type group map[string]Where
+
+// Parse parses the where query from the keys of a query string that
+// start with "where", such as where[title][equals]=Hello. Other keys
+// are ignored.
+//
+// Nested groups can be indexed, as in where[or][0][title][equals], or
+// appended, as in where[or][][title][equals], where each key appends a
+// new query to the group. The values of in, not_in and all can be comma
+// separated or given as an array, as in where[tags][in][]=go.
+func Parse(values url.Values) (Where, error) {
+	w := Where{}
+	appended := 0
+	for _, key := range slices.Sorted(maps.Keys(values)) {
+		if !strings.HasPrefix(key, "where[") {
+			continue
+		}
+		tokens, err := tokenize(key)
+		if err != nil {
+			return nil, fmt.Errorf("where: %s: %w", key, err)
+		}
+		for _, v := range values[key] {
+			if err := insert(w, tokens, v, &appended); err != nil {
+				return nil, fmt.Errorf("where: %s: %w", key, err)
+			}
+		}
+	}
+	return finalize(w), nil
+}
+
+// FromQuery parses the where query built by the QueryBuilder.
+func FromQuery(qb *payloadcms.QueryBuilder) (Where, error) {
+	if qb == nil {
+		return Where{}, nil
+	}
+	values, err := url.ParseQuery(qb.Build())
+	if err != nil {
+		return nil, fmt.Errorf("where: %w", err)
+	}
+	return Parse(values)
+}
+
+// tokenize splits a key into the segments within its brackets. Segments
+// that are themselves where keys, which the QueryBuilder nests within
+// and/or groups, are split in turn.
+func tokenize(key string) ([]string, error) {
+	rest := strings.TrimPrefix(key, "where")
+	var tokens []string
+	for rest != "" {
+		if rest[0] != '[' {
+			return nil, errors.New("malformed key")
+		}
+		end, depth := -1, 0
+		for i := range len(rest) {
+			switch rest[i] {
+			case '[':
+				depth++
+			case ']':
+				depth--
+			}
+			if depth == 0 {
+				end = i
+				break
+			}
+		}
+		if end < 0 {
+			return nil, errors.New("unbalanced brackets")
+		}
+
+		token := rest[1:end]
+		rest = rest[end+1:]
+		if strings.HasPrefix(token, "where[") {
+			nested, err := tokenize(token)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, nested...)
+			continue
+		}
+		tokens = append(tokens, token)
+	}
+	return tokens, nil
+}
+
+// insert adds the value to the query at the path of the tokens.
+func insert(w Where, tokens []string, value string, appended *int) error {
+	if len(tokens) < 2 {
+		return errors.New("missing operator")
+	}
+
+	if name := strings.ToLower(tokens[0]); name == "and" || name == "or" {
+		index := tokens[1]
+		if index == "" {
+			index = "+" + strconv.Itoa(*appended)
+			*appended++
+		} else if _, err := strconv.Atoi(index); err != nil {
+			return fmt.Errorf("invalid index %q", index)
+		}
+
+		g, ok := w[name].(group)
+		if !ok {
+			g = group{}
+			w[name] = g
+		}
+		child, ok := g[index]
+		if !ok {
+			child = Where{}
+			g[index] = child
+		}
+		return insert(child, tokens[2:], value, appended)
+	}
+
+	field, op := tokens[0], tokens[1]
+	ops, ok := w[field].(map[string]any)
+	if !ok {
+		ops = map[string]any{}
+		w[field] = ops
+	}
+
+	existing, ok := ops[op]
+	switch {
+	case !ok && len(tokens) == 2:
+		ops[op] = value
+	case !ok:
+		// An array value, such as where[tags][in][]=go.
+		ops[op] = []any{value}
+	default:
+		switch e := existing.(type) {
+		case []any:
+			ops[op] = append(e, value)
+		default:
+			ops[op] = []any{e, value}
+		}
+	}
+	return nil
+}
+
+// finalize replaces the groups of the query with lists of queries.
+func finalize(w Where) Where {
+	for key, v := range w {
+		g, ok := v.(group)
+		if !ok {
+			continue
+		}
+		queries := make([]any, 0, len(g))
+		for _, index := range slices.Sorted(maps.Keys(g)) {
+			queries = append(queries, finalize(g[index]))
+		}
+		w[key] = queries
+	}
+	return w
+}
@@ -0,0 +1,151 @@
+package where
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ainsleyclark/go-payloadcms"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	tt := map[string]struct {
+		query string
+		want  Where
+	}{
+		"Empty": {
+			query: "sort=title&limit=10",
+			want:  Where{},
+		},
+		"Field": {
+			query: "where[title][equals]=Hello",
+			want:  Where{"title": map[string]any{"equals": "Hello"}},
+		},
+		"Dotted Path": {
+			query: "where[author.name][like]=jane",
+			want:  Where{"author.name": map[string]any{"like": "jane"}},
+		},
+		"Several Operators": {
+			query: "where[views][greater_than]=1&where[views][less_than]=10",
+			want:  Where{"views": map[string]any{"greater_than": "1", "less_than": "10"}},
+		},
+		"Array Value": {
+			query: "where[tags][in][]=go&where[tags][in][]=cms",
+			want:  Where{"tags": map[string]any{"in": []any{"go", "cms"}}},
+		},
+		"Indexed Array Value": {
+			query: "where[tags][all][0]=go&where[tags][all][1]=cms",
+			want:  Where{"tags": map[string]any{"all": []any{"go", "cms"}}},
+		},
+		"Indexed Group": {
+			query: "where[or][0][title][equals]=A&where[or][1][title][equals]=B&where[or][1][views][equals]=2",
+			want: Where{"or": []any{
+				Where{"title": map[string]any{"equals": "A"}},
+				Where{"title": map[string]any{"equals": "B"}, "views": map[string]any{"equals": "2"}},
+			}},
+		},
+		"Nested Groups": {
+			query: "where[and][0][or][0][title][equals]=A&where[and][0][or][1][title][equals]=B",
+			want: Where{"and": []any{
+				Where{"or": []any{
+					Where{"title": map[string]any{"equals": "A"}},
+					Where{"title": map[string]any{"equals": "B"}},
+				}},
+			}},
+		},
+		"Appended Group": {
+			query: "where[or][][title][equals]=A&where[or][][views][equals]=2",
+			want: Where{"or": []any{
+				Where{"title": map[string]any{"equals": "A"}},
+				Where{"views": map[string]any{"equals": "2"}},
+			}},
+		},
+	}
+
+	for name, test := range tt {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			values, err := url.ParseQuery(test.query)
+			require.NoError(t, err)
+
+			got, err := Parse(values)
+			require.NoError(t, err)
+			assert.Equal(t, test.want, got)
+		})
+	}
+
+	t.Run("Errors", func(t *testing.T) {
+		t.Parallel()
+
+		for _, query := range []string{
+			"where[title]=Hello",
+			"where[title][equals=Hello",
+			"where[or][first][title][equals]=A",
+			"where[title]x[equals]=Hello",
+		} {
+			values, err := url.ParseQuery(query)
+			require.NoError(t, err)
+
+			_, err = Parse(values)
+			assert.Error(t, err, query)
+		}
+	})
+}
+
+func TestFromQuery(t *testing.T) {
+	t.Parallel()
+
+	doc := decode(t, post)
+
+	tt := map[string]struct {
+		query *payloadcms.QueryBuilder
+		want  bool
+	}{
+		"Nil": {
+			query: nil,
+			want:  true,
+		},
+		"Equals": {
+			query: payloadcms.Query().Equals("title", "Hello Go World"),
+			want:  true,
+		},
+		"Chained": {
+			query: payloadcms.Query().GreaterThanEqual("views", "42").In("tags", []string{"rust", "go"}),
+			want:  true,
+		},
+		"Chained Miss": {
+			query: payloadcms.Query().GreaterThan("views", "42").Exists("tags", true),
+			want:  false,
+		},
+		"And": {
+			query: payloadcms.Query().And(payloadcms.Query().Equals("author.name", "Jane").LessThan("rating", "5")),
+			want:  true,
+		},
+		"Or": {
+			query: payloadcms.Query().Or(payloadcms.Query().Equals("title", "Other").NotEquals("views", "1")),
+			want:  true,
+		},
+		"Or Miss": {
+			query: payloadcms.Query().Or(payloadcms.Query().Equals("title", "Other").Exists("published", false)),
+			want:  false,
+		},
+	}
+
+	for name, test := range tt {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			w, err := FromQuery(test.query)
+			require.NoError(t, err)
+
+			got, err := w.Match(doc)
+			require.NoError(t, err)
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
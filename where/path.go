@@ -0,0 +1,87 @@
+package where
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Lookup returns the values at the dot separated path of the document,
+// reporting false if the path doesn't exist. Arrays along the path are
+// flattened, so "blocks.title" returns the title of every block, while
+// a numeric segment such as "blocks.0.title" indexes into the array.
+//
+// Relationships are compared by ID, so a populated relationship returns
+// its id, and a polymorphic one its value.
+func Lookup(doc map[string]any, path string) ([]any, bool) {
+	values := []any{doc}
+	found := true
+	for _, key := range strings.Split(path, ".") {
+		var next []any
+		found = false
+		for _, v := range values {
+			for _, child := range step(v, key) {
+				found = true
+				next = append(next, child)
+			}
+		}
+		values = next
+	}
+
+	var flat []any
+	for _, v := range values {
+		flat = appendValue(flat, v)
+	}
+	return flat, found
+}
+
+// step returns the children of the value at the key, flattening arrays.
+func step(v any, key string) []any {
+	switch t := v.(type) {
+	case map[string]any:
+		child, ok := t[key]
+		if !ok {
+			return nil
+		}
+		return []any{child}
+	case Where:
+		return step(map[string]any(t), key)
+	case []any:
+		if i, err := strconv.Atoi(key); err == nil {
+			if i >= 0 && i < len(t) {
+				return []any{t[i]}
+			}
+			return nil
+		}
+		var children []any
+		for _, elem := range t {
+			children = append(children, step(elem, key)...)
+		}
+		return children
+	default:
+		return nil
+	}
+}
+
+// appendValue appends the value, flattening arrays and replacing
+// relationships with their ID.
+func appendValue(values []any, v any) []any {
+	switch t := v.(type) {
+	case []any:
+		for _, elem := range t {
+			values = appendValue(values, elem)
+		}
+		return values
+	case map[string]any:
+		if value, ok := t["value"]; ok {
+			if _, ok := t["relationTo"]; ok {
+				return appendValue(values, value)
+			}
+		}
+		if id, ok := t["id"]; ok {
+			return append(values, id)
+		}
+		return append(values, v)
+	default:
+		return append(values, v)
+	}
+}
@@ -0,0 +1,73 @@
+package where
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Sort sorts the documents in place by the sort of a Payload query,
+// which is a comma separated list of field paths, each prefixed with a
+// "-" to sort in descending order. Documents that are equal are kept in
+// their original order, and missing values sort first.
+//
+// Example:
+//
+//	where.Sort(docs, "-publishedAt,title")
+func Sort(docs []map[string]any, sort string) {
+	type key struct {
+		path string
+		desc bool
+	}
+	var keys []key
+	for _, field := range strings.Split(sort, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		path, desc := strings.CutPrefix(field, "-")
+		keys = append(keys, key{path: path, desc: desc})
+	}
+	if len(keys) == 0 {
+		return
+	}
+
+	slices.SortStableFunc(docs, func(a, b map[string]any) int {
+		for _, k := range keys {
+			c := compareSort(sortValue(a, k.path), sortValue(b, k.path))
+			if k.desc {
+				c = -c
+			}
+			if c != 0 {
+				return c
+			}
+		}
+		return 0
+	})
+}
+
+// sortValue returns the first value at the path, or nil if it's missing.
+func sortValue(doc map[string]any, path string) any {
+	values, _ := Lookup(doc, path)
+	if len(values) == 0 {
+		return nil
+	}
+	return values[0]
+}
+
+// compareSort compares two values for sorting, where missing values
+// sort first and values that can't be compared are compared as strings.
+func compareSort(a, b any) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return -1
+	case b == nil:
+		return 1
+	}
+	if c, ok := compare(a, b); ok {
+		return c
+	}
+	return strings.Compare(fmt.Sprint(a), fmt.Sprint(b))
+}
@@ -0,0 +1,52 @@
+package where
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSort(t *testing.T) {
+	t.Parallel()
+
+	docs := func() []map[string]any {
+		return []map[string]any{
+			decode(t, `{"id": 1, "title": "Banana", "views": 10, "date": "2024-06-03T00:00:00.000Z", "author": {"name": "Bo"}}`),
+			decode(t, `{"id": 2, "title": "apple", "views": 5, "date": "2024-06-01T00:00:00.000Z", "author": {"name": "Al"}}`),
+			decode(t, `{"id": 3, "title": "Cherry", "views": 10, "date": "2024-06-02T00:00:00.000Z"}`),
+			decode(t, `{"id": 4, "title": "Apple", "views": 100}`),
+		}
+	}
+
+	tt := map[string]struct {
+		sort string
+		want []string
+	}{
+		"None":            {sort: "", want: []string{"1", "2", "3", "4"}},
+		"Ascending":       {sort: "views", want: []string{"2", "1", "3", "4"}},
+		"Descending":      {sort: "-views", want: []string{"4", "1", "3", "2"}},
+		"Strings":         {sort: "title", want: []string{"4", "1", "3", "2"}},
+		"Dates":           {sort: "date", want: []string{"4", "2", "3", "1"}},
+		"Multiple":        {sort: "-views,title", want: []string{"4", "1", "3", "2"}},
+		"Multiple Desc":   {sort: "views,-date", want: []string{"2", "1", "3", "4"}},
+		"Dotted Path":     {sort: "author.name", want: []string{"3", "4", "2", "1"}},
+		"Missing Desc":    {sort: "-author.name", want: []string{"1", "2", "3", "4"}},
+		"Spaces And Gaps": {sort: " -views , ,title", want: []string{"4", "1", "3", "2"}},
+	}
+
+	for name, test := range tt {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			d := docs()
+			Sort(d, test.sort)
+
+			var got []string
+			for _, doc := range d {
+				got = append(got, doc["id"].(json.Number).String())
+			}
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
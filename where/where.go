@@ -0,0 +1,333 @@
+// Package where evaluates Payload where queries against documents in
+// Go, as Payload would when finding them. It's used by the payloadtest
+// fake server, and can be used on its own to filter and sort documents
+// decoded into maps.
+//
+// A Where mirrors the where object of the Payload REST and Local APIs,
+// mapping field paths to operators and values, with nested "and" and
+// "or" groups. Field paths use dot notation to reach into nested fields,
+// and match if any element of an array along the path matches.
+//
+// The supported operators are equals, not_equals, in, not_in, all,
+// exists, greater_than, greater_than_equal, less_than, less_than_equal,
+// like and contains. The geospatial operators near, within and
+// intersects aren't supported.
+//
+// Example:
+//
+//	w, err := where.FromQuery(payloadcms.Query().Equals("status", "published"))
+//	if err != nil {
+//		return err
+//	}
+//	docs, err = where.Filter(docs, w)
+//	where.Sort(docs, "-publishedAt,title")
+//
+// See: https://payloadcms.com/docs/queries/overview
+package where
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Where is a where query, which maps field paths to a map of operators
+// and values, or "and" and "or" to a list of nested queries. The zero
+// value matches every document.
+//
+// Example:
+//
+//	where.Where{
+//		"status": map[string]any{"equals": "published"},
+//		"or": []any{
+//			where.Where{"author.name": map[string]any{"equals": "Jane"}},
+//			where.Where{"tags": map[string]any{"in": []any{"go", "cms"}}},
+//		},
+//	}
+type Where map[string]any
+
+// Operators of a where query.
+const (
+	Equals           = "equals"
+	NotEquals        = "not_equals"
+	In               = "in"
+	NotIn            = "not_in"
+	All              = "all"
+	Exists           = "exists"
+	GreaterThan      = "greater_than"
+	GreaterThanEqual = "greater_than_equal"
+	LessThan         = "less_than"
+	LessThanEqual    = "less_than_equal"
+	Like             = "like"
+	Contains         = "contains"
+)
+
+// ErrUnsupportedOperator is returned when a query uses an operator that
+// isn't supported.
+var ErrUnsupportedOperator = errors.New("where: unsupported operator")
+
+// Filter returns the documents that match the query, in their original
+// order.
+func Filter(docs []map[string]any, w Where) ([]map[string]any, error) {
+	matched := make([]map[string]any, 0, len(docs))
+	for _, doc := range docs {
+		ok, err := w.Match(doc)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			matched = append(matched, doc)
+		}
+	}
+	return matched, nil
+}
+
+// Match reports whether the document matches every condition of the
+// query.
+func (w Where) Match(doc map[string]any) (bool, error) {
+	// Sort the keys so that errors are reported consistently.
+	for _, key := range slices.Sorted(maps.Keys(w)) {
+		var (
+			ok  bool
+			err error
+		)
+		switch strings.ToLower(key) {
+		case "and":
+			ok, err = matchGroup(doc, w[key], true)
+		case "or":
+			ok, err = matchGroup(doc, w[key], false)
+		default:
+			ok, err = matchField(doc, key, w[key])
+		}
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// matchGroup matches the nested queries of an "and" or "or" group. An
+// empty group matches every document.
+func matchGroup(doc map[string]any, group any, and bool) (bool, error) {
+	queries, err := nested(group)
+	if err != nil {
+		return false, err
+	}
+	if len(queries) == 0 {
+		return true, nil
+	}
+	for _, q := range queries {
+		ok, err := q.Match(doc)
+		if err != nil {
+			return false, err
+		}
+		if ok != and {
+			return ok, nil
+		}
+	}
+	return and, nil
+}
+
+// nested returns the queries of a group, which can be a list of queries
+// or maps, as decoded from JSON.
+func nested(group any) ([]Where, error) {
+	switch g := group.(type) {
+	case nil:
+		return nil, nil
+	case []Where:
+		return g, nil
+	case []map[string]any:
+		queries := make([]Where, len(g))
+		for i, q := range g {
+			queries[i] = q
+		}
+		return queries, nil
+	case []any:
+		queries := make([]Where, len(g))
+		for i, v := range g {
+			q, ok := asMap(v)
+			if !ok {
+				return nil, fmt.Errorf("where: and/or must contain queries, got %T", v)
+			}
+			queries[i] = q
+		}
+		return queries, nil
+	default:
+		return nil, fmt.Errorf("where: and/or must be a list of queries, got %T", group)
+	}
+}
+
+// matchField matches the operators of a field, all of which must match.
+func matchField(doc map[string]any, path string, ops any) (bool, error) {
+	conditions, ok := asMap(ops)
+	if !ok {
+		return false, fmt.Errorf("where: %s must map operators to values, got %T", path, ops)
+	}
+
+	values, found := Lookup(doc, path)
+	for _, op := range slices.Sorted(maps.Keys(conditions)) {
+		ok, err := match(strings.ToLower(op), values, found, conditions[op])
+		if err != nil {
+			return false, fmt.Errorf("where: %s: %w", path, err)
+		}
+		if !ok {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// match applies the operator to the values found at a path. Operators
+// match if any of the values match, apart from the negative operators,
+// which match if none of them do.
+func match(op string, values []any, found bool, want any) (bool, error) {
+	switch op {
+	case Equals:
+		return anyValue(values, want, equal), nil
+	case NotEquals:
+		return !anyValue(values, want, equal), nil
+	case In:
+		return anyIn(values, list(want)), nil
+	case NotIn:
+		return !anyIn(values, list(want)), nil
+	case All:
+		for _, w := range list(want) {
+			if !anyValue(values, w, equal) {
+				return false, nil
+			}
+		}
+		return true, nil
+	case Exists:
+		b, ok := toBool(want)
+		if !ok {
+			return false, fmt.Errorf("exists must be true or false, got %v", want)
+		}
+		return (found && slices.ContainsFunc(values, func(v any) bool { return v != nil })) == b, nil
+	case GreaterThan:
+		return anyValue(values, want, ordered(func(c int) bool { return c > 0 })), nil
+	case GreaterThanEqual:
+		return anyValue(values, want, ordered(func(c int) bool { return c >= 0 })), nil
+	case LessThan:
+		return anyValue(values, want, ordered(func(c int) bool { return c < 0 })), nil
+	case LessThanEqual:
+		return anyValue(values, want, ordered(func(c int) bool { return c <= 0 })), nil
+	case Like:
+		return anyValue(values, want, like), nil
+	case Contains:
+		return anyValue(values, want, contains), nil
+	default:
+		return false, fmt.Errorf("%w: %s", ErrUnsupportedOperator, op)
+	}
+}
+
+// anyValue reports whether fn is true for any of the values. Missing
+// values are only passed to fn when there are no others, so that
+// equals null matches missing fields.
+func anyValue(values []any, want any, fn func(v, want any) bool) bool {
+	if len(values) == 0 {
+		return fn(nil, want)
+	}
+	return slices.ContainsFunc(values, func(v any) bool { return fn(v, want) })
+}
+
+func anyIn(values, want []any) bool {
+	return slices.ContainsFunc(want, func(w any) bool { return anyValue(values, w, equal) })
+}
+
+func equal(v, want any) bool {
+	if isNull(want) {
+		return v == nil
+	}
+	if v == nil {
+		return false
+	}
+	c, ok := compare(v, want)
+	return ok && c == 0
+}
+
+func ordered(fn func(c int) bool) func(v, want any) bool {
+	return func(v, want any) bool {
+		if v == nil {
+			return false
+		}
+		c, ok := compare(v, want)
+		return ok && fn(c)
+	}
+}
+
+// like reports whether every word of want is in the value, ignoring case.
+func like(v, want any) bool {
+	s, ok := v.(string)
+	if !ok {
+		return false
+	}
+	s = strings.ToLower(s)
+	for _, word := range strings.Fields(strings.ToLower(fmt.Sprint(want))) {
+		if !strings.Contains(s, word) {
+			return false
+		}
+	}
+	return true
+}
+
+// contains reports whether want is in the value, ignoring case.
+func contains(v, want any) bool {
+	s, ok := v.(string)
+	if !ok {
+		return false
+	}
+	return strings.Contains(strings.ToLower(s), strings.ToLower(fmt.Sprint(want)))
+}
+
+// list returns the values of an in, not_in or all operator, which can be
+// a list or a comma separated string.
+func list(v any) []any {
+	switch l := v.(type) {
+	case []any:
+		return l
+	case []string:
+		values := make([]any, len(l))
+		for i, s := range l {
+			values[i] = s
+		}
+		return values
+	case string:
+		var values []any
+		for _, s := range strings.Split(l, ",") {
+			values = append(values, strings.TrimSpace(s))
+		}
+		return values
+	default:
+		return []any{v}
+	}
+}
+
+func isNull(v any) bool {
+	return v == nil || v == "null"
+}
+
+func toBool(v any) (bool, bool) {
+	switch b := v.(type) {
+	case bool:
+		return b, true
+	case string:
+		parsed, err := strconv.ParseBool(b)
+		return parsed, err == nil
+	default:
+		return false, false
+	}
+}
+
+func asMap(v any) (map[string]any, bool) {
+	switch m := v.(type) {
+	case Where:
+		return m, true
+	case map[string]any:
+		return m, true
+	default:
+		return nil, false
+	}
+}
@@ -0,0 +1,252 @@
+package where
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// decode decodes the JSON as Payload documents are, with numbers as
+// json.Number.
+func decode(t *testing.T, s string) map[string]any {
+	t.Helper()
+	dec := json.NewDecoder(strings.NewReader(s))
+	dec.UseNumber()
+	var doc map[string]any
+	require.NoError(t, dec.Decode(&doc))
+	return doc
+}
+
+const post = `{
+	"id": 1,
+	"title": "Hello Go World",
+	"views": 42,
+	"rating": 4.5,
+	"published": true,
+	"publishedAt": "2024-06-01T12:00:00.000Z",
+	"subtitle": null,
+	"tags": ["go", "cms"],
+	"author": {"id": 7, "name": "Jane"},
+	"category": {"relationTo": "categories", "value": 3},
+	"meta": {"seo": {"title": "SEO Title"}},
+	"blocks": [
+		{"id": "a", "blockType": "hero", "heading": "Welcome"},
+		{"id": "b", "blockType": "content", "heading": "Body", "items": [{"label": "One"}, {"label": "Two"}]}
+	]
+}`
+
+func TestWhere_Match(t *testing.T) {
+	t.Parallel()
+
+	doc := decode(t, post)
+
+	tt := map[string]struct {
+		where Where
+		want  bool
+	}{
+		"Empty":                {where: Where{}, want: true},
+		"Equals":               {where: Where{"title": map[string]any{"equals": "Hello Go World"}}, want: true},
+		"Equals Case":          {where: Where{"title": map[string]any{"equals": "hello go world"}}, want: false},
+		"Equals Number String": {where: Where{"views": map[string]any{"equals": "42"}}, want: true},
+		"Equals Number":        {where: Where{"views": map[string]any{"equals": 42}}, want: true},
+		"Equals Float":         {where: Where{"rating": map[string]any{"equals": "4.5"}}, want: true},
+		"Equals Bool String":   {where: Where{"published": map[string]any{"equals": "true"}}, want: true},
+		"Equals Bool":          {where: Where{"published": map[string]any{"equals": false}}, want: false},
+		"Equals Null":          {where: Where{"subtitle": map[string]any{"equals": nil}}, want: true},
+		"Equals Null Missing":  {where: Where{"missing": map[string]any{"equals": "null"}}, want: true},
+		"Equals Missing":       {where: Where{"missing": map[string]any{"equals": "x"}}, want: false},
+		"Equals Has Many":      {where: Where{"tags": map[string]any{"equals": "cms"}}, want: true},
+		"Not Equals":           {where: Where{"title": map[string]any{"not_equals": "Other"}}, want: true},
+		"Not Equals Has Many":  {where: Where{"tags": map[string]any{"not_equals": "go"}}, want: false},
+		"Not Equals Missing":   {where: Where{"missing": map[string]any{"not_equals": "x"}}, want: true},
+		"In String":            {where: Where{"views": map[string]any{"in": "1,42"}}, want: true},
+		"In List":              {where: Where{"tags": map[string]any{"in": []any{"rust", "go"}}}, want: true},
+		"In Miss":              {where: Where{"tags": map[string]any{"in": []string{"rust"}}}, want: false},
+		"Not In":               {where: Where{"tags": map[string]any{"not_in": "rust,zig"}}, want: true},
+		"Not In Miss":          {where: Where{"tags": map[string]any{"not_in": "rust,cms"}}, want: false},
+		"All":                  {where: Where{"tags": map[string]any{"all": "go,cms"}}, want: true},
+		"All Miss":             {where: Where{"tags": map[string]any{"all": "go,rust"}}, want: false},
+		"Exists":               {where: Where{"title": map[string]any{"exists": "true"}}, want: true},
+		"Exists Null":          {where: Where{"subtitle": map[string]any{"exists": true}}, want: false},
+		"Not Exists":           {where: Where{"missing": map[string]any{"exists": false}}, want: true},
+		"Greater Than":         {where: Where{"views": map[string]any{"greater_than": "41"}}, want: true},
+		"Greater Than Equal":   {where: Where{"views": map[string]any{"greater_than_equal": 42}}, want: true},
+		"Less Than":            {where: Where{"views": map[string]any{"less_than": "42"}}, want: false},
+		"Less Than Equal":      {where: Where{"rating": map[string]any{"less_than_equal": "4.5"}}, want: true},
+		"Less Than Missing":    {where: Where{"missing": map[string]any{"less_than": "1"}}, want: false},
+		"Date":                 {where: Where{"publishedAt": map[string]any{"greater_than": "2024-05-31"}}, want: true},
+		"Date Before":          {where: Where{"publishedAt": map[string]any{"less_than": "2024-06-01T11:00:00Z"}}, want: false},
+		"String Order":         {where: Where{"title": map[string]any{"greater_than": "Apple"}}, want: true},
+		"Like":                 {where: Where{"title": map[string]any{"like": "world hello"}}, want: true},
+		"Like Miss":            {where: Where{"title": map[string]any{"like": "hello rust"}}, want: false},
+		"Contains":             {where: Where{"title": map[string]any{"contains": "GO WOR"}}, want: true},
+		"Contains Miss":        {where: Where{"title": map[string]any{"contains": "world hello"}}, want: false},
+		"Several Operators":    {where: Where{"views": map[string]any{"greater_than": 10, "less_than": 40}}, want: false},
+		"Dotted Path":          {where: Where{"meta.seo.title": map[string]any{"equals": "SEO Title"}}, want: true},
+		"Relationship":         {where: Where{"author": map[string]any{"equals": "7"}}, want: true},
+		"Relationship Field":   {where: Where{"author.name": map[string]any{"equals": "Jane"}}, want: true},
+		"Polymorphic":          {where: Where{"category": map[string]any{"in": "2,3"}}, want: true},
+		"Polymorphic Value":    {where: Where{"category.value": map[string]any{"equals": 3}}, want: true},
+		"Array":                {where: Where{"blocks.blockType": map[string]any{"equals": "content"}}, want: true},
+		"Array Miss":           {where: Where{"blocks.blockType": map[string]any{"equals": "gallery"}}, want: false},
+		"Array Index":          {where: Where{"blocks.0.blockType": map[string]any{"equals": "content"}}, want: false},
+		"Nested Array":         {where: Where{"blocks.items.label": map[string]any{"equals": "Two"}}, want: true},
+		"And": {
+			where: Where{"and": []any{
+				Where{"views": map[string]any{"greater_than": 10}},
+				map[string]any{"tags": map[string]any{"equals": "go"}},
+			}},
+			want: true,
+		},
+		"And Miss": {
+			where: Where{"and": []Where{
+				{"views": map[string]any{"greater_than": 10}},
+				{"tags": map[string]any{"equals": "rust"}},
+			}},
+			want: false,
+		},
+		"Or": {
+			where: Where{"or": []any{
+				Where{"views": map[string]any{"greater_than": 100}},
+				Where{"tags": map[string]any{"equals": "go"}},
+			}},
+			want: true,
+		},
+		"Or Miss": {
+			where: Where{"or": []any{
+				Where{"views": map[string]any{"greater_than": 100}},
+				Where{"tags": map[string]any{"equals": "rust"}},
+			}},
+			want: false,
+		},
+		"Nested": {
+			where: Where{
+				"published": map[string]any{"equals": true},
+				"or": []any{
+					Where{"title": map[string]any{"contains": "rust"}},
+					Where{"and": []any{
+						Where{"author.name": map[string]any{"equals": "Jane"}},
+						Where{"views": map[string]any{"greater_than_equal": 42}},
+					}},
+				},
+			},
+			want: true,
+		},
+		"Empty Or": {where: Where{"or": []any{}}, want: true},
+	}
+
+	for name, test := range tt {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := test.where.Match(doc)
+			require.NoError(t, err)
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
+
+func TestWhere_MatchError(t *testing.T) {
+	t.Parallel()
+
+	doc := decode(t, post)
+
+	tt := map[string]struct {
+		where Where
+		want  string
+	}{
+		"Unsupported Operator": {
+			where: Where{"location": map[string]any{"near": "1,2"}},
+			want:  "where: location: where: unsupported operator: near",
+		},
+		"Invalid Exists": {
+			where: Where{"title": map[string]any{"exists": "maybe"}},
+			want:  "exists must be true or false",
+		},
+		"Invalid Operators": {
+			where: Where{"title": "Hello"},
+			want:  "title must map operators to values",
+		},
+		"Invalid Group": {
+			where: Where{"or": "title"},
+			want:  "and/or must be a list of queries",
+		},
+		"Invalid Group Element": {
+			where: Where{"and": []any{"title"}},
+			want:  "and/or must contain queries",
+		},
+	}
+
+	for name, test := range tt {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := test.where.Match(doc)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), test.want)
+		})
+	}
+
+	t.Run("Is Unsupported", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := Where{"location": map[string]any{"within": "x"}}.Match(doc)
+		assert.ErrorIs(t, err, ErrUnsupportedOperator)
+	})
+}
+
+func TestFilter(t *testing.T) {
+	t.Parallel()
+
+	docs := []map[string]any{
+		decode(t, `{"id": 1, "status": "published"}`),
+		decode(t, `{"id": 2, "status": "draft"}`),
+		decode(t, `{"id": 3, "status": "published"}`),
+	}
+
+	got, err := Filter(docs, Where{"status": map[string]any{"equals": "published"}})
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	assert.Equal(t, json.Number("1"), got[0]["id"])
+	assert.Equal(t, json.Number("3"), got[1]["id"])
+
+	_, err = Filter(docs, Where{"status": map[string]any{"near": "x"}})
+	assert.ErrorIs(t, err, ErrUnsupportedOperator)
+}
+
+func TestLookup(t *testing.T) {
+	t.Parallel()
+
+	doc := decode(t, post)
+
+	tt := map[string]struct {
+		path      string
+		want      []any
+		wantFound bool
+	}{
+		"Field":        {path: "title", want: []any{"Hello Go World"}, wantFound: true},
+		"Null":         {path: "subtitle", want: []any{nil}, wantFound: true},
+		"Missing":      {path: "missing", wantFound: false},
+		"Nested":       {path: "meta.seo.title", want: []any{"SEO Title"}, wantFound: true},
+		"Has Many":     {path: "tags", want: []any{"go", "cms"}, wantFound: true},
+		"Array":        {path: "blocks.heading", want: []any{"Welcome", "Body"}, wantFound: true},
+		"Array Index":  {path: "blocks.1.heading", want: []any{"Body"}, wantFound: true},
+		"Out Of Range": {path: "blocks.5.heading", wantFound: false},
+		"Relationship": {path: "author", want: []any{json.Number("7")}, wantFound: true},
+		"Polymorphic":  {path: "category", want: []any{json.Number("3")}, wantFound: true},
+		"Into Scalar":  {path: "title.length", wantFound: false},
+	}
+
+	for name, test := range tt {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got, found := Lookup(doc, test.path)
+			assert.Equal(t, test.wantFound, found)
+			assert.Equal(t, test.want, got)
+		})
+	}
+}